@@ -0,0 +1,240 @@
+// Package backup stores device configurations in a local git repository.
+//
+// Every device gets one file per configuration format. A device is only
+// committed when its configuration changed, with the author and message of
+// the latest commit on the device.
+package backup
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// Options holds the settings of a backup run.
+type Options struct {
+	// Directory is the git repository, it is initialized when needed.
+	Directory string
+	// Formats lists the configuration formats to store, "text" and "set".
+	Formats []string
+	// StripSecrets replaces encrypted passwords and keys with a placeholder.
+	StripSecrets bool
+	// Parallel is the number of devices fetched at the same time.
+	Parallel int
+}
+
+// Result is the outcome of the backup of one device.
+type Result struct {
+	Host    string `json:"host"`
+	Changed bool   `json:"changed"`
+	Commit  string `json:"commit,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Extensions maps the configuration formats to the extension of their files.
+var Extensions = map[string]string{
+	"text": ".conf",
+	"set":  ".set",
+}
+
+var (
+	volatileLines = regexp.MustCompile(`(?m)^## Last (commit|changed): .*\n`)
+	secretValues  = regexp.MustCompile(`"\$[0-9]+\$[^"]*"`)
+	secretMarkers = regexp.MustCompile(`(?m) *## SECRET-DATA$`)
+)
+
+// Strip removes the lines that change without a configuration change, and
+// optionally the encrypted secrets.
+func Strip(config string, secrets bool) string {
+	config = volatileLines.ReplaceAllString(config, "")
+	if secrets {
+		config = secretValues.ReplaceAllString(config, `"<removed>"`)
+		config = secretMarkers.ReplaceAllString(config, "")
+	}
+	return config
+}
+
+type device struct {
+	host    string
+	configs map[string]string
+	commit  networkapi.CommitHistory
+}
+
+// Run backs up the devices and returns one result per device, sorted by host.
+func Run(inventory *networkapi.Inventory, devices []networkapi.Device, opts Options) ([]Result, error) {
+
+	if len(opts.Formats) == 0 {
+		opts.Formats = []string{"text", "set"}
+	}
+	for _, format := range opts.Formats {
+		if _, ok := Extensions[format]; !ok {
+			return nil, fmt.Errorf("unsupported configuration format %q", format)
+		}
+	}
+	if opts.Parallel < 1 {
+		opts.Parallel = 1
+	}
+
+	repo, err := openRepository(opts.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []Result
+	)
+
+	sem := make(chan struct{}, opts.Parallel)
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d networkapi.Device) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := Result{Host: d.Hostname}
+			fetched, err := fetch(inventory.Client(d), d.Hostname, opts)
+			if err == nil {
+				// Commits are serialized, the index is shared.
+				mu.Lock()
+				res.Changed, res.Commit, err = repo.store(fetched, opts)
+				mu.Unlock()
+			}
+			if err != nil {
+				res.Error = err.Error()
+			}
+
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Host < results[j].Host })
+	return results, nil
+}
+
+// fetch reads the configurations and the latest commit of the device. A
+// command failing fails the device, its output being partial at best.
+func fetch(c *networkapi.Client, host string, opts Options) (*device, error) {
+
+	defer c.DisconnectSSH()
+
+	d := &device{host: host, configs: make(map[string]string)}
+	for _, format := range opts.Formats {
+		command := "show configuration"
+		if format != "text" {
+			command += " | display " + format
+		}
+		config, err := c.RunSSH(command)
+		if err != nil {
+			return nil, fmt.Errorf("%s configuration: %s", format, err)
+		}
+		if strings.TrimSpace(config) == "" {
+			return nil, fmt.Errorf("empty %s configuration", format)
+		}
+		d.configs[format] = Strip(config, opts.StripSecrets)
+	}
+
+	history, err := c.RunSSH("show system commit | display xml")
+	if err != nil {
+		return nil, fmt.Errorf("commit history: %s", err)
+	}
+	if d.commit, err = latestCommit(history); err != nil {
+		return nil, fmt.Errorf("commit history: %s", err)
+	}
+
+	return d, nil
+}
+
+type commitInformation struct {
+	Entries []struct {
+		Sequence  int    `xml:"sequence-number"`
+		User      string `xml:"user"`
+		Client    string `xml:"client"`
+		Timestamp string `xml:"date-time"`
+		Log       string `xml:"log"`
+		Comment   string `xml:"comment"`
+	} `xml:"commit-information>commit-history"`
+}
+
+// latestCommit returns the first entry of the commit history, the latest one.
+// A device without commits, freshly zeroized, has an empty history.
+func latestCommit(output string) (networkapi.CommitHistory, error) {
+
+	var info commitInformation
+	if err := xml.Unmarshal([]byte(output), &info); err != nil {
+		return networkapi.CommitHistory{}, err
+	}
+	if len(info.Entries) == 0 {
+		return networkapi.CommitHistory{}, nil
+	}
+
+	entry := info.Entries[0]
+	return networkapi.CommitHistory{
+		User:      strings.TrimSpace(entry.User),
+		Method:    strings.TrimSpace(entry.Client),
+		Log:       strings.TrimSpace(entry.Log),
+		Comment:   strings.TrimSpace(entry.Comment),
+		Timestamp: strings.TrimSpace(entry.Timestamp),
+	}, nil
+}
+
+// message returns the commit message for a configuration change.
+func (d *device) message() string {
+
+	summary := d.commit.Comment
+	if summary == "" {
+		summary = d.commit.Log
+	}
+	if summary == "" {
+		summary = "configuration changed"
+	}
+
+	message := fmt.Sprintf("%s: %s\n", d.host, summary)
+	if d.commit.User != "" {
+		message += fmt.Sprintf("\nCommitted by %s via %s at %s\n", d.commit.User, d.commit.Method, d.commit.Timestamp)
+	}
+	return message
+}
+
+// author returns the git author of a configuration change.
+func (d *device) author() string {
+	user := d.commit.User
+	if user == "" {
+		user = "unknown"
+	}
+	return fmt.Sprintf("%s <%s@%s>", user, user, d.host)
+}
+
+func writeFile(filename, content string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), ".backup-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/sftptest"
+)
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStrip(t *testing.T) {
+	config := `## Last changed: 2023-10-19 10:40:42 UTC
+version 21.4R3.15;
+system {
+    root-authentication {
+        encrypted-password "$6$Zk1f$Qz0bq7m1"; ## SECRET-DATA
+    }
+    description "## Last commit: not a header";
+}
+`
+	tests := []struct {
+		name    string
+		config  string
+		secrets bool
+		want    string
+	}{
+		{"volatile lines", config, false, `version 21.4R3.15;
+system {
+    root-authentication {
+        encrypted-password "$6$Zk1f$Qz0bq7m1"; ## SECRET-DATA
+    }
+    description "## Last commit: not a header";
+}
+`},
+		{"secrets", config, true, `version 21.4R3.15;
+system {
+    root-authentication {
+        encrypted-password "<removed>";
+    }
+    description "## Last commit: not a header";
+}
+`},
+		{"set", "set system root-authentication encrypted-password \"$6$Zk1f$Qz0bq7m1\"\nset snmp community public authorization read-only\n", true,
+			"set system root-authentication encrypted-password \"<removed>\"\nset snmp community public authorization read-only\n"},
+		{"last commit", "## Last commit: 2023-10-19 10:40:42 UTC by jdoe\nversion 21.4R3.15;\n", false, "version 21.4R3.15;\n"},
+	}
+	for _, tt := range tests {
+		if got := Strip(tt.config, tt.secrets); got != tt.want {
+			t.Errorf("%s: Strip() =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLatestCommit(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   networkapi.CommitHistory
+		err    bool
+	}{
+		{"history", readFile(t, "../testdata/junos/commit.xml"), networkapi.CommitHistory{
+			User:      "jdoe",
+			Method:    "cli",
+			Comment:   "add neighbor core-3",
+			Timestamp: "2023-10-19 10:40:42 UTC",
+		}, false},
+		{"no commits", "<rpc-reply>\n<commit-information/>\n</rpc-reply>\n", networkapi.CommitHistory{}, false},
+		{"not xml", "error: syntax error, expecting <command>: commit\n", networkapi.CommitHistory{}, true},
+	}
+	for _, tt := range tests {
+		got, err := latestCommit(tt.output)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: latestCommit() error = %v, want error %t", tt.name, err, tt.err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: latestCommit() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv, err := sftptest.NewServer("admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	c := networkapi.NetworkClient(srv.Addr, "admin", "secret")
+
+	// The commit history isn't handled, its command fails.
+	srv.Handle("show configuration", readFile(t, "../testdata/junos/config.txt"))
+	srv.Handle("show configuration | display set", readFile(t, "../testdata/junos/config.set"))
+	opts := Options{Formats: []string{"text", "set"}, StripSecrets: true}
+	if _, err := fetch(c, "edge-1", opts); err == nil || !strings.Contains(err.Error(), "commit history") {
+		t.Errorf("fetch() error = %v, want the commit history failing", err)
+	}
+
+	srv.Handle("show system commit | display xml", readFile(t, "../testdata/junos/commit.xml"))
+	d, err := fetch(c, "edge-1", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(d.configs["set"], `encrypted-password "<removed>"`) || !strings.HasPrefix(d.configs["text"], "version 21.4R3.15;") {
+		t.Errorf("fetch() configs = %q", d.configs)
+	}
+	if d.commit.User != "jdoe" {
+		t.Errorf("fetch() commit = %+v", d.commit)
+	}
+
+	srv.Handle("show configuration | display set", "")
+	if _, err := fetch(c, "edge-1", opts); err == nil || err.Error() != "empty set configuration" {
+		t.Errorf("fetch() error = %v, want an empty set configuration", err)
+	}
+}
@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// repository runs the git command line in the backup directory.
+type repository struct {
+	dir string
+}
+
+func openRepository(dir string) (*repository, error) {
+
+	if dir == "" {
+		return nil, fmt.Errorf("no backup directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	repo := &repository{dir: dir}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if _, err := repo.git("init", "--quiet"); err != nil {
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+func (r *repository) git(args ...string) (string, error) {
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"GIT_COMMITTER_NAME=networkapi backup",
+		"GIT_COMMITTER_EMAIL=networkapi@localhost",
+	)
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// store writes the configuration files of the device and commits them when
+// they changed. It returns whether they changed and the commit hash.
+func (r *repository) store(d *device, opts Options) (bool, string, error) {
+
+	var files []string
+	for _, format := range opts.Formats {
+		file := d.host + Extensions[format]
+		if err := writeFile(filepath.Join(r.dir, file), d.configs[format]); err != nil {
+			return false, "", err
+		}
+		files = append(files, file)
+	}
+
+	if _, err := r.git(append([]string{"add", "--"}, files...)...); err != nil {
+		return false, "", err
+	}
+
+	// diff --quiet exits with 1 when there are staged changes.
+	args := append([]string{"diff", "--cached", "--quiet", "--"}, files...)
+	if _, err := r.git(args...); err == nil {
+		return false, "", nil
+	}
+
+	args = append([]string{"commit", "--quiet", "--author", d.author(), "--message", d.message(), "--"}, files...)
+	if _, err := r.git(args...); err != nil {
+		return false, "", err
+	}
+
+	commit, err := r.git("rev-parse", "--short", "HEAD")
+	if err != nil {
+		return true, "", err
+	}
+	return true, commit, nil
+}
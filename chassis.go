@@ -0,0 +1,209 @@
+package networkapi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"sort"
+	"strings"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+// chassisModuleXML is a module of show chassis hardware, which names its
+// children after their depth.
+type chassisModuleXML struct {
+	ChassisModule
+	Modules          []chassisModuleXML `xml:"chassis-module"`
+	SubModules       []chassisModuleXML `xml:"chassis-sub-module"`
+	SubSubModules    []chassisModuleXML `xml:"chassis-sub-sub-module"`
+	SubSubSubModules []chassisModuleXML `xml:"chassis-sub-sub-sub-module"`
+}
+
+type chassisHardwareSSH struct {
+	XMLName                xml.Name           `xml:"rpc-reply"`
+	Chassis                []chassisModuleXML `xml:"chassis-inventory>chassis"`
+	MultiRoutingEngineItem []struct {
+		ReName  string             `xml:"re-name"`
+		Chassis []chassisModuleXML `xml:"chassis-inventory>chassis"`
+	} `xml:"multi-routing-engine-results>multi-routing-engine-item"`
+}
+
+func (m chassisModuleXML) module() ChassisModule {
+	module := m.ChassisModule
+	module.Modules = nil
+	for _, children := range [][]chassisModuleXML{m.Modules, m.SubModules, m.SubSubModules, m.SubSubSubModules} {
+		for _, child := range children {
+			module.Modules = append(module.Modules, child.module())
+		}
+	}
+	return module
+}
+
+//GetChassisInventorySSH ...Returns the hardware modules of the chassis, with their part and serial numbers
+func (c *Client) GetChassisInventorySSH(session *ssh.Session) ([]ChassisInventory, error) {
+	var stdoutBuf bytes.Buffer
+	session.Stdout = &stdoutBuf
+	if err := session.Run("show chassis hardware | display xml"); err != nil {
+		return nil, err
+	}
+	return parseChassisInventory(stdoutBuf.Bytes())
+}
+
+//GetChassisInventory ...Returns the hardware modules of the chassis, with their part and serial numbers
+func (c *Client) GetChassisInventory(session *junos.Junos) ([]ChassisInventory, error) {
+	return ReadChassisInventory(NetconfRunner(session))
+}
+
+//ReadChassisInventory ... Returns the hardware modules of the chassis of a Junos device
+//
+// Virtual chassis members and cluster nodes each have their chassis.
+func ReadChassisInventory(run Runner) ([]ChassisInventory, error) {
+	output, err := run(displayCommand("show chassis hardware", "xml"))
+	if err != nil {
+		return nil, err
+	}
+	return parseChassisInventory([]byte(output))
+}
+
+func parseChassisInventory(output []byte) ([]ChassisInventory, error) {
+	if i := bytes.IndexByte(output, '<'); i > 0 {
+		output = output[i:]
+	}
+	var reply chassisHardwareSSH
+	if err := xml.Unmarshal(output, &reply); err != nil {
+		return nil, err
+	}
+
+	var inventory []ChassisInventory
+	for _, chassis := range reply.Chassis {
+		inventory = append(inventory, ChassisInventory{Chassis: chassis.module()})
+	}
+	for _, item := range reply.MultiRoutingEngineItem {
+		for _, chassis := range item.Chassis {
+			inventory = append(inventory, ChassisInventory{Member: strings.TrimSpace(item.ReName), Chassis: chassis.module()})
+		}
+	}
+	return inventory, nil
+}
+
+//Walk ... Calls fn for the module and every module it holds, with the names of the modules above
+func (m ChassisModule) Walk(fn func(path []string, module ChassisModule)) {
+	m.walk(nil, fn)
+}
+
+func (m ChassisModule) walk(path []string, fn func(path []string, module ChassisModule)) {
+	fn(path, m)
+	path = append(path[:len(path):len(path)], m.Name)
+	for _, child := range m.Modules {
+		child.walk(path, fn)
+	}
+}
+
+//GetFacts ... Returns the hostname, model, serial number and routing engines of the device
+func (c *Client) GetFacts(session *junos.Junos) (*DeviceFacts, error) {
+	return ReadFacts(NetconfRunner(session))
+}
+
+var domainPattern = regexp.MustCompile(`domain-name\s+"?([^";\s]+)`)
+
+//ReadFacts ... Returns the facts of a device, with the routing engines and domain name of a Junos device
+//
+// The facts are detected with DetectFacts, on Junos the version is then read
+// from every routing engine, and the status, mastership and uptime from show
+// chassis routing-engine. Over SSH, run is client.RunSSH.
+func ReadFacts(run Runner) (*DeviceFacts, error) {
+
+	facts, err := DetectFacts(run)
+	if err != nil || facts.Platform != PlatformJunos {
+		return facts, err
+	}
+
+	res := make(map[string]*RoutingEngineFacts)
+	re := func(name string) *RoutingEngineFacts {
+		if res[name] == nil {
+			res[name] = &RoutingEngineFacts{Name: name}
+		}
+		return res[name]
+	}
+	for _, r := range facts.RoutingEngines {
+		*re(r.Name) = r
+	}
+
+	// Devices refusing invoke-on keep the version of show version.
+	var version SoftwareInformationSSH
+	if err := junosXML(run, "show version invoke-on all-routing-engines", &version); err == nil {
+		if software := version.SoftwareInformation; software != nil {
+			re("re0").Version = software.Version()
+			re("re0").Model = strings.TrimSpace(software.ProductModel)
+		}
+		for _, item := range version.MultiRoutingEngineItem {
+			r := re(strings.TrimSpace(item.ReName))
+			r.Version = item.SoftwareInformation.Version()
+			r.Model = strings.TrimSpace(item.SoftwareInformation.ProductModel)
+		}
+	}
+
+	var engines RouteEngineInformationSSH
+	if err := junosXML(run, "show chassis routing-engine", &engines); err != nil {
+		return nil, err
+	}
+	prefix := "re"
+	if facts.VirtualChassis {
+		prefix = "fpc"
+	}
+	for _, engine := range engines.RouteEngine {
+		routingEngineFacts(re(prefix+strings.TrimSpace(engine.Slot)), engine)
+	}
+	for _, item := range engines.MultiRoutingEngineItem {
+		for _, engine := range item.RouteEngine {
+			routingEngineFacts(re(strings.TrimSpace(item.ReName)), engine)
+		}
+	}
+
+	if config, err := run("show configuration system domain-name"); err == nil {
+		if m := domainPattern.FindStringSubmatch(config); m != nil {
+			facts.Domain = m[1]
+		}
+	}
+
+	facts.RoutingEngines = nil
+	routingEngines := 0
+	for name, r := range res {
+		facts.RoutingEngines = append(facts.RoutingEngines, *r)
+		if strings.HasPrefix(name, "re") {
+			routingEngines++
+		}
+	}
+	sort.Slice(facts.RoutingEngines, func(i, j int) bool { return facts.RoutingEngines[i].Name < facts.RoutingEngines[j].Name })
+	facts.MultiRE = routingEngines > 1
+
+	for _, r := range facts.RoutingEngines {
+		if strings.EqualFold(r.Mastership, "master") || strings.EqualFold(r.Mastership, "primary") {
+			facts.MasterRE = r.Name
+			if r.Version != "" {
+				facts.Version = r.Version
+			}
+			facts.Uptime = r.Uptime
+			break
+		}
+	}
+	if facts.MasterRE == "" && len(facts.RoutingEngines) > 0 {
+		if v := facts.RoutingEngines[0].Version; v != "" {
+			facts.Version = v
+		}
+		facts.Uptime = facts.RoutingEngines[0].Uptime
+	}
+	return facts, nil
+}
+
+func routingEngineFacts(r *RoutingEngineFacts, engine RouteEngine) {
+	r.Status = strings.TrimSpace(engine.Status)
+	r.Mastership = strings.ToLower(strings.TrimSpace(engine.MastershipState))
+	r.SerialNumber = strings.TrimSpace(engine.SerialNumber)
+	if model := strings.TrimSpace(engine.Model); model != "" {
+		r.Model = model
+	}
+	r.Uptime = junosDuration(engine.UpTime)
+}
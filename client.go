@@ -1,10 +1,27 @@
 package networkapi
 
+import (
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
 //Client Initialize the Constructor Variables
 type Client struct {
 	Hostname string
 	Username string
 	Password string
+	// Platform selects the driver. When empty, the platform detected on
+	// connect is used, or Junos when none was.
+	Platform string
+	// AutoDetect detects the facts of the device when connecting.
+	AutoDetect bool
+
+	mu        sync.Mutex
+	sshClient *ssh.Client
+	facts     *DeviceFacts
+	// detecting counts the detections running, whose commands connect too.
+	detecting int
 }
 
 //NetworkClient Initialize the Constructor
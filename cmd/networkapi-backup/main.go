@@ -0,0 +1,63 @@
+// Command networkapi-backup stores the configuration of the inventory devices
+// in a local git repository.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/backup"
+)
+
+func main() {
+
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	selector := flag.String("select", "all", "inventory selector, e.g. \"core-*,site=ams1\"")
+	directory := flag.String("dir", "configs", "git repository the configurations are stored in")
+	formats := flag.String("formats", "text,set", "comma separated configuration formats: text, set")
+	stripSecrets := flag.Bool("strip-secrets", false, "replace encrypted passwords and keys with a placeholder")
+	parallel := flag.Int("parallel", 10, "number of devices fetched at the same time")
+	flag.Parse()
+
+	if *inventoryFile == "" {
+		log.Fatal("networkapi-backup: --inventory is required")
+	}
+	inventory, err := networkapi.LoadInventory(*inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-backup: %s", err)
+	}
+	devices, err := inventory.Select(*selector)
+	if err != nil {
+		log.Fatalf("networkapi-backup: %s", err)
+	}
+
+	results, err := backup.Run(inventory, devices, backup.Options{
+		Directory:    *directory,
+		Formats:      strings.Split(*formats, ","),
+		StripSecrets: *stripSecrets,
+		Parallel:     *parallel,
+	})
+	if err != nil {
+		log.Fatalf("networkapi-backup: %s", err)
+	}
+
+	failed := false
+	for _, res := range results {
+		switch {
+		case res.Error != "":
+			failed = true
+			fmt.Printf("%s: error: %s\n", res.Host, res.Error)
+		case res.Changed:
+			fmt.Printf("%s: changed, committed %s\n", res.Host, res.Commit)
+		default:
+			fmt.Printf("%s: unchanged\n", res.Host)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
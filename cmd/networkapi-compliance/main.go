@@ -0,0 +1,99 @@
+// Command networkapi-compliance checks the configuration of the inventory
+// devices, or of configuration files, against compliance rules.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/compliance"
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+func main() {
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: networkapi-compliance --rules rules.yaml [flags] [config files]\n\nChecks the given configuration files, named after their host, or the inventory devices.\n\n")
+		flag.PrintDefaults()
+	}
+	rulesFile := flag.String("rules", "", "rules file (YAML or JSON)")
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	selector := flag.String("select", "all", "inventory selector, e.g. \"core-*,site=ams1\"")
+	parallel := flag.Int("parallel", 10, "number of devices fetched at the same time")
+	format := flag.String("format", "text", "output format: text, json")
+	flag.Parse()
+
+	if *rulesFile == "" {
+		log.Fatal("networkapi-compliance: --rules is required")
+	}
+	rules, err := compliance.LoadRules(*rulesFile)
+	if err != nil {
+		log.Fatalf("networkapi-compliance: %s", err)
+	}
+
+	var results []compliance.Result
+	if flag.NArg() > 0 {
+		for _, file := range flag.Args() {
+			host := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				log.Fatalf("networkapi-compliance: %s", err)
+			}
+			config, err := configtree.Parse(data, "")
+			if err != nil {
+				log.Fatalf("networkapi-compliance: %s: %s", file, err)
+			}
+			results = append(results, compliance.Check(host, config, rules)...)
+		}
+	} else {
+		if *inventoryFile == "" {
+			log.Fatal("networkapi-compliance: --inventory or configuration files are required")
+		}
+		inventory, err := networkapi.LoadInventory(*inventoryFile)
+		if err != nil {
+			log.Fatalf("networkapi-compliance: %s", err)
+		}
+		devices, err := inventory.Select(*selector)
+		if err != nil {
+			log.Fatalf("networkapi-compliance: %s", err)
+		}
+		results = compliance.Audit(inventory, devices, rules, *parallel)
+	}
+
+	failed := false
+	for _, res := range results {
+		failed = failed || !res.Passed
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(results)
+	default:
+		for _, res := range results {
+			switch {
+			case res.Error != "":
+				fmt.Printf("ERROR %s %s: %s\n", res.Host, res.Rule, res.Error)
+			case res.Passed:
+				fmt.Printf("PASS  %s %s\n", res.Host, res.Rule)
+			default:
+				fmt.Printf("FAIL  %s %s\n", res.Host, res.Rule)
+				for _, v := range res.Violations {
+					fmt.Printf("      %s\n", v)
+				}
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+// Command networkapi-convert converts a Junos configuration between the text,
+// set, XML and JSON formats without connecting to the device.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi/convert"
+)
+
+func main() {
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: networkapi-convert [flags] [file]\n\nReads the configuration from file, or the standard input.\n\n")
+		flag.PrintDefaults()
+	}
+	from := flag.String("from", "", "format of the configuration, detected when empty: "+strings.Join(convert.Formats, ", "))
+	to := flag.String("to", "set", "format to convert to: "+strings.Join(convert.Formats, ", "))
+	flag.Parse()
+
+	var data []byte
+	var err error
+	switch flag.NArg() {
+	case 0:
+		data, err = ioutil.ReadAll(os.Stdin)
+	case 1:
+		data, err = ioutil.ReadFile(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("networkapi-convert: %s", err)
+	}
+
+	output, err := convert.Convert(data, *from, *to)
+	if err != nil {
+		log.Fatalf("networkapi-convert: %s", err)
+	}
+	os.Stdout.Write(output)
+}
@@ -0,0 +1,78 @@
+// Command networkapi-descriptions checks the interface descriptions of the
+// inventory devices against their LLDP neighbors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/descriptions"
+)
+
+func main() {
+
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	selector := flag.String("select", "all", "inventory selector, e.g. \"core-*,site=ams1\"")
+	pattern := flag.String("pattern", descriptions.DefaultPattern, "regular expression extracting the host and port groups from a description")
+	template := flag.String("template", descriptions.DefaultTemplate, "description of a neighbor")
+	interfaces := flag.String("interfaces", descriptions.DefaultInterfaces, "regular expression selecting the interfaces checked")
+	format := flag.String("format", "text", "output format: text, json, set (the corrective commands by host)")
+	parallel := flag.Int("parallel", 10, "number of devices polled at the same time")
+	flag.Parse()
+
+	if *inventoryFile == "" {
+		log.Fatal("networkapi-descriptions: --inventory is required")
+	}
+	inventory, err := networkapi.LoadInventory(*inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-descriptions: %s", err)
+	}
+	devices, err := inventory.Select(*selector)
+	if err != nil {
+		log.Fatalf("networkapi-descriptions: %s", err)
+	}
+
+	findings, err := descriptions.Audit(inventory, devices, descriptions.Options{
+		Pattern:    *pattern,
+		Template:   *template,
+		Interfaces: *interfaces,
+	}, *parallel)
+	if err != nil {
+		log.Fatalf("networkapi-descriptions: %s", err)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(findings)
+	case "set":
+		host := ""
+		for _, f := range findings {
+			if f.Command == "" {
+				continue
+			}
+			if f.Host != host {
+				host = f.Host
+				fmt.Printf("# %s\n", host)
+			}
+			fmt.Println(f.Command)
+		}
+	default:
+		for _, f := range findings {
+			fmt.Printf("%s %s: %s: %s\n", f.Host, f.Interface, f.Type, f.Message)
+			if f.Command != "" {
+				fmt.Printf("    %s\n", f.Command)
+			}
+		}
+		fmt.Printf("%d findings on %d devices\n", len(findings), len(devices))
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
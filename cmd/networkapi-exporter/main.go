@@ -0,0 +1,51 @@
+// Command networkapi-exporter serves device metrics to Prometheus.
+//
+// Prometheus scrapes /metrics?target=<host> for every inventory device, for
+// example with this scrape configuration:
+//
+//   - job_name: junos
+//     metrics_path: /metrics
+//     static_configs:
+//   - targets: [core-1.ams1, core-2.ams1]
+//     relabel_configs:
+//   - source_labels: [__address__]
+//     target_label: __param_target
+//   - source_labels: [__param_target]
+//     target_label: instance
+//   - target_label: __address__
+//     replacement: localhost:9326
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/exporter"
+)
+
+func main() {
+
+	listen := flag.String("listen", ":9326", "address to listen on")
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of a scrape")
+	flag.Parse()
+
+	if *inventoryFile == "" {
+		log.Fatal("networkapi-exporter: --inventory is required")
+	}
+	inventory, err := networkapi.LoadInventory(*inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-exporter: %s", err)
+	}
+
+	exp := exporter.New(inventory, *timeout)
+	defer exp.Close()
+
+	http.Handle("/metrics", exp)
+	log.Printf("networkapi-exporter: listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
@@ -0,0 +1,48 @@
+// Command networkapi-server serves the networkapi collection methods over HTTP.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/server"
+)
+
+func main() {
+
+	listen := flag.String("listen", ":8080", "address to listen on")
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	timeout := flag.Duration("timeout", 60*time.Second, "timeout of the requests to the devices")
+	allow := flag.String("allow", strings.Join(server.DefaultAllowlist, ","), "comma separated command prefixes accepted by the command endpoint")
+	flag.Parse()
+
+	if *inventoryFile == "" {
+		log.Fatal("networkapi-server: --inventory is required")
+	}
+	inventory, err := networkapi.LoadInventory(*inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-server: %s", err)
+	}
+
+	var allowlist []string
+	for _, prefix := range strings.Split(*allow, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			allowlist = append(allowlist, prefix)
+		}
+	}
+
+	srv := server.New(server.Config{
+		Inventory: inventory,
+		Timeout:   *timeout,
+		Allowlist: allowlist,
+	})
+	defer srv.Close()
+
+	log.Printf("networkapi-server: listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, srv))
+}
@@ -0,0 +1,131 @@
+// Command networkapi-snapshot takes snapshots of the inventory devices before
+// and after a change, and compares them.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/snapshot"
+)
+
+const usage = `usage:
+  networkapi-snapshot take --name pre [flags]
+  networkapi-snapshot compare --pre pre --post post --tests tests.yaml [flags]
+
+`
+
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("networkapi-snapshot "+os.Args[1], flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", "snapshots", "directory the snapshots are stored in")
+
+	switch os.Args[1] {
+	case "take":
+		inventoryFile := flags.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+		selector := flags.String("select", "all", "inventory selector, e.g. \"core-*,site=ams1\"")
+		name := flags.String("name", "", "snapshot name, e.g. pre or post")
+		collectors := flags.String("collectors", strings.Join(snapshot.DefaultCollectors, ","), "comma separated collectors")
+		parallel := flags.Int("parallel", 10, "number of devices polled at the same time")
+		flags.Parse(os.Args[2:])
+		if *inventoryFile == "" || *name == "" {
+			log.Fatal("networkapi-snapshot: --inventory and --name are required")
+		}
+		take(*inventoryFile, *selector, *dir, *name, strings.Split(*collectors, ","), *parallel)
+
+	case "compare":
+		pre := flags.String("pre", "pre", "name of the snapshot taken before the change")
+		post := flags.String("post", "post", "name of the snapshot taken after the change")
+		testsFile := flags.String("tests", "", "tests file (YAML or JSON)")
+		format := flags.String("format", "text", "output format: text, json")
+		flags.Parse(os.Args[2:])
+		if *testsFile == "" {
+			log.Fatal("networkapi-snapshot: --tests is required")
+		}
+		compare(*dir, *pre, *post, *testsFile, *format)
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func take(inventoryFile, selector, dir, name string, collectors []string, parallel int) {
+
+	inventory, err := networkapi.LoadInventory(inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-snapshot: %s", err)
+	}
+	devices, err := inventory.Select(selector)
+	if err != nil {
+		log.Fatalf("networkapi-snapshot: %s", err)
+	}
+
+	errors := snapshot.TakeAll(inventory, devices, dir, name, collectors, parallel)
+	for _, d := range devices {
+		if err, failed := errors[d.Hostname]; failed {
+			fmt.Printf("%s: error: %s\n", d.Hostname, err)
+		} else {
+			fmt.Printf("%s: %s snapshot taken\n", d.Hostname, name)
+		}
+	}
+	if len(errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func compare(dir, pre, post, testsFile, format string) {
+
+	tests, err := snapshot.LoadTests(testsFile)
+	if err != nil {
+		log.Fatalf("networkapi-snapshot: %s", err)
+	}
+	hosts, err := snapshot.Hosts(dir, post)
+	if err != nil {
+		log.Fatalf("networkapi-snapshot: %s", err)
+	}
+	if len(hosts) == 0 {
+		log.Fatalf("networkapi-snapshot: no %s snapshot in %s", post, dir)
+	}
+
+	var reports []snapshot.Report
+	passed := true
+	for _, host := range hosts {
+		before, err := snapshot.Load(dir, pre, host)
+		if err != nil {
+			log.Fatalf("networkapi-snapshot: %s", err)
+		}
+		after, err := snapshot.Load(dir, post, host)
+		if err != nil {
+			log.Fatalf("networkapi-snapshot: %s", err)
+		}
+		report := snapshot.Compare(before, after, tests)
+		passed = passed && report.Passed()
+		reports = append(reports, report)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(reports)
+	} else {
+		snapshot.WriteText(os.Stdout, reports)
+	}
+	if !passed {
+		os.Exit(1)
+	}
+}
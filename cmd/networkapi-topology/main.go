@@ -0,0 +1,43 @@
+// Command networkapi-topology builds the network topology from the LLDP
+// neighbors of the inventory devices.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/topology"
+)
+
+func main() {
+
+	inventoryFile := flag.String("inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	selector := flag.String("select", "all", "inventory selector, e.g. \"core-*,site=ams1\"")
+	format := flag.String("format", "json", "output format: "+strings.Join(topology.Formats, ", "))
+	parallel := flag.Int("parallel", 10, "number of devices polled at the same time")
+	flag.Parse()
+
+	if *inventoryFile == "" {
+		log.Fatal("networkapi-topology: --inventory is required")
+	}
+	inventory, err := networkapi.LoadInventory(*inventoryFile)
+	if err != nil {
+		log.Fatalf("networkapi-topology: %s", err)
+	}
+	devices, err := inventory.Select(*selector)
+	if err != nil {
+		log.Fatalf("networkapi-topology: %s", err)
+	}
+
+	graph := topology.Build(inventory, devices, *parallel)
+	if err := graph.Write(os.Stdout, *format); err != nil {
+		log.Fatalf("networkapi-topology: %s", err)
+	}
+	for _, issue := range graph.Issues {
+		fmt.Fprintf(os.Stderr, "%s: %s %s: %s\n", issue.Type, issue.Device, issue.Interface, issue.Message)
+	}
+}
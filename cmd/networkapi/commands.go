@@ -0,0 +1,498 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"github.com/kgrvamsi/networkapi"
+)
+
+// sshFunc runs its commands with Client.RunSSH, in a session of their own.
+type sshFunc func(c *networkapi.Client, o *options, args []string) (interface{}, error)
+
+type netconfFunc func(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error)
+
+type restFunc func(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error)
+
+type telnetFunc func(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error)
+
+type command struct {
+	help    string
+	args    string
+	minArgs int
+	ssh     sshFunc
+	netconf netconfFunc
+	rest    restFunc
+	telnet  telnetFunc
+}
+
+var commands = map[string]*command{
+	"show": {
+		help:    "run an operational command, e.g. show \"show chassis alarms\"",
+		args:    "a command",
+		minArgs: 1,
+		ssh:     sshShow,
+		netconf: netconfShow,
+		rest:    restShow,
+		telnet:  telnetShow,
+	},
+	"config": {
+		help:    "show the configuration, optionally as text, set, xml or json",
+		ssh:     sshConfig,
+		netconf: netconfConfig,
+		rest:    restConfig,
+		telnet:  telnetConfig,
+	},
+	"interfaces": driverCommand("list interfaces with their status, description and speed",
+		func(driver networkapi.Driver, run networkapi.Runner) (interface{}, error) {
+			return driver.GetInterfaces(run)
+		}),
+	"bgp": driverCommand("list BGP peers with their state and prefix counts",
+		func(driver networkapi.Driver, run networkapi.Runner) (interface{}, error) {
+			return driver.GetBGPSummary(run)
+		}),
+	"lldp": driverCommand("list LLDP neighbors",
+		func(driver networkapi.Driver, run networkapi.Runner) (interface{}, error) {
+			return driver.GetLLDPNeighbors(run)
+		}),
+	"optics": driverCommand("show optics diagnostics of the interfaces",
+		func(driver networkapi.Driver, run networkapi.Runner) (interface{}, error) {
+			return driver.GetOptics(run)
+		}),
+	"uptime": driverCommand("show the system uptime", uptime),
+	"logs": {
+		help:    "show a log file, messages by default",
+		ssh:     sshLogs,
+		netconf: netconfLogs,
+		rest:    restLogs,
+		telnet:  telnetLogs,
+	},
+	"commit-history": {
+		help:    "show the commit history",
+		ssh:     sshCommitHistory,
+		netconf: netconfCommitHistory,
+		rest:    restCommand("show system commit"),
+		telnet:  telnetCommand("show system commit"),
+	},
+	"lsp": readerCommand("list MPLS LSPs with their role, state, active path and bandwidth", "",
+		func(run networkapi.Runner, args []string) (interface{}, error) {
+			return networkapi.ReadMPLSLSPs(run)
+		}),
+	"rsvp": readerCommand("list RSVP sessions", "",
+		func(run networkapi.Runner, args []string) (interface{}, error) {
+			return networkapi.ReadRSVPSessions(run)
+		}),
+	"ldp":    readerCommand("list LDP sessions with their label counts, or LDP neighbors with \"ldp neighbors\"", "[neighbors]", ldp),
+	"alarms": readerCommand("list the active chassis and system alarms", "", alarms),
+	"environment": readerCommand("show the status of the temperature sensors, fans and power supplies", "",
+		func(run networkapi.Runner, args []string) (interface{}, error) {
+			return networkapi.ReadEnvironment(run)
+		}),
+	"firewall":   readerCommand("list the counters and policers of the firewall filters, or of the filter given", "[filter]", firewall),
+	"ping":       readerCommand("ping a target from the device", "<target> [count n] [size n] [ttl n] [source address] [routing-instance name] [do-not-fragment]", ping),
+	"traceroute": readerCommand("trace the route to a target from the device", "<target> [ttl n] [source address] [routing-instance name]", traceroute),
+	"lacp": readerCommand("list aggregated ethernet bundles with their active members, flagging degraded ones", "",
+		func(run networkapi.Runner, args []string) (interface{}, error) {
+			return networkapi.ReadLACPBundles(run)
+		}),
+	"routing-engines": readerCommand("show the CPU, memory and temperature of the routing engines", "",
+		func(run networkapi.Runner, args []string) (interface{}, error) {
+			return networkapi.ReadRoutingEngines(run)
+		}),
+}
+
+// readerCommand returns a command reading the device with a function of the
+// library taking a Runner, which runs over any transport.
+func readerCommand(help, args string, read func(run networkapi.Runner, args []string) (interface{}, error)) *command {
+	return runnerCommand(help, args, func(c *networkapi.Client, run networkapi.Runner, args []string) (interface{}, error) {
+		return read(run, args)
+	})
+}
+
+// driverCommand returns a command reading the device with the driver of its
+// platform.
+func driverCommand(help string, read func(driver networkapi.Driver, run networkapi.Runner) (interface{}, error)) *command {
+	return runnerCommand(help, "", func(c *networkapi.Client, run networkapi.Runner, args []string) (interface{}, error) {
+		driver, err := c.Driver()
+		if err != nil {
+			return nil, err
+		}
+		return read(driver, run)
+	})
+}
+
+// runnerCommand returns a command calling read with a Runner of the selected
+// transport.
+func runnerCommand(help, args string, read func(c *networkapi.Client, run networkapi.Runner, args []string) (interface{}, error)) *command {
+	return &command{
+		help: help,
+		args: args,
+		ssh: func(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+			return read(c, c.RunSSH, args)
+		},
+		netconf: func(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+			return read(c, networkapi.NetconfRunner(session), args)
+		},
+		rest: func(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error) {
+			return read(c, session.Run, args)
+		},
+		telnet: func(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error) {
+			return read(c, session.Run, args)
+		},
+	}
+}
+
+// systemUptime is the uptime as a duration and in seconds.
+type systemUptime struct {
+	Uptime  string `json:"uptime" yaml:"uptime" xml:"uptime"`
+	Seconds int64  `json:"seconds" yaml:"seconds" xml:"seconds"`
+}
+
+func uptime(driver networkapi.Driver, run networkapi.Runner) (interface{}, error) {
+	d, err := driver.GetUptime(run)
+	if err != nil {
+		return nil, err
+	}
+	return systemUptime{Uptime: d.String(), Seconds: int64(d / time.Second)}, nil
+}
+
+// alarms lists the chassis alarms followed by the system alarms.
+func alarms(run networkapi.Runner, args []string) (interface{}, error) {
+	chassis, err := networkapi.ReadChassisAlarms(run)
+	if err != nil {
+		return nil, err
+	}
+	system, err := networkapi.ReadSystemAlarms(run)
+	if err != nil {
+		return nil, err
+	}
+	return append(chassis, system...), nil
+}
+
+func ldp(run networkapi.Runner, args []string) (interface{}, error) {
+	if len(args) > 0 && args[0] == "neighbors" {
+		return networkapi.ReadLDPNeighbors(run)
+	}
+	return networkapi.ReadLDPSessions(run)
+}
+
+// firewall lists the counters of every filter, or of the filter given.
+func firewall(run networkapi.Runner, args []string) (interface{}, error) {
+	filter := ""
+	if len(args) > 0 {
+		filter = args[0]
+	}
+	return networkapi.ReadFirewallCounters(run, filter)
+}
+
+// ping pings the target of the arguments with the options following it.
+func ping(run networkapi.Runner, args []string) (interface{}, error) {
+	target, options, err := probeOptions("ping", args, "count", "size", "ttl", "source", "routing-instance", "do-not-fragment")
+	if err != nil {
+		return nil, err
+	}
+	opts := networkapi.PingOptions{
+		Source:          options["source"],
+		RoutingInstance: options["routing-instance"],
+		DoNotFragment:   options["do-not-fragment"] != "",
+	}
+	for name, value := range map[string]*int{"count": &opts.Count, "size": &opts.Size, "ttl": &opts.TTL} {
+		if options[name] == "" {
+			continue
+		}
+		if *value, err = strconv.Atoi(options[name]); err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, options[name])
+		}
+	}
+	return networkapi.ReadPing(run, target, opts)
+}
+
+// traceroute traces the route to the target of the arguments.
+func traceroute(run networkapi.Runner, args []string) (interface{}, error) {
+	target, options, err := probeOptions("traceroute", args, "ttl", "source", "routing-instance")
+	if err != nil {
+		return nil, err
+	}
+	opts := networkapi.TracerouteOptions{
+		Source:          options["source"],
+		RoutingInstance: options["routing-instance"],
+	}
+	if options["ttl"] != "" {
+		if opts.TTL, err = strconv.Atoi(options["ttl"]); err != nil {
+			return nil, fmt.Errorf("invalid ttl %q", options["ttl"])
+		}
+	}
+	return networkapi.ReadTraceroute(run, target, opts)
+}
+
+// probeOptions returns the target of the arguments and the options following
+// it, given as name and value except do-not-fragment.
+func probeOptions(command string, args []string, names ...string) (string, map[string]string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s needs a target", command)
+	}
+	known := make(map[string]bool)
+	for _, name := range names {
+		known[name] = true
+	}
+	options := make(map[string]string)
+	for i := 1; i < len(args); i++ {
+		name := args[i]
+		switch {
+		case !known[name]:
+			return "", nil, fmt.Errorf("unknown %s option %q", command, name)
+		case name == "do-not-fragment":
+			options[name] = "yes"
+		case i+1 == len(args):
+			return "", nil, fmt.Errorf("%s option %s needs a value", command, name)
+		default:
+			options[name] = args[i+1]
+			i++
+		}
+	}
+	return args[0], options, nil
+}
+
+// run connects to the device over the selected transport and runs the command.
+func (cmd *command) run(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+
+	switch o.transport {
+	case "netconf":
+		session, err := c.Connect()
+		if err != nil {
+			return nil, err
+		}
+		defer c.Close(session)
+		return cmd.netconf(c, session, o, args)
+	case "rest":
+		session, err := c.ConnectREST(networkapi.RESTOptions{
+			Port:     o.restPort,
+			HTTPS:    o.restHTTPS,
+			CAFile:   o.restCA,
+			Insecure: o.restInsecure,
+			Timeout:  o.timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer c.CloseREST(session)
+		return cmd.rest(c, session, o, args)
+	case "telnet":
+		session, err := c.ConnectTelnet(networkapi.TelnetOptions{
+			Port:    o.telnetPort,
+			Timeout: o.timeout,
+			Prompt:  o.telnetPrompt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer c.CloseTelnet(session)
+		return cmd.telnet(c, session, o, args)
+	default:
+		return cmd.ssh(c, o, args)
+	}
+}
+
+// deviceFormat returns the format the device should display its output in.
+func deviceFormat(format string) string {
+	switch format {
+	case "xml":
+		return "xml"
+	case "json", "yaml":
+		return "json"
+	}
+	return "text"
+}
+
+// displayCommand pipes the command to display xml or json, as the format of
+// the device output asks.
+func displayCommand(command, format string) string {
+	if format == "text" {
+		return command
+	}
+	return command + " | display " + format
+}
+
+// rawOutput decodes JSON output of the device so it can be rendered as YAML
+// or re-indented, and returns any other output as is.
+func rawOutput(output string, o *options) interface{} {
+	if deviceFormat(o.format) == "json" {
+		var data interface{}
+		if err := json.Unmarshal([]byte(output), &data); err == nil {
+			return data
+		}
+	}
+	return output
+}
+
+func sshShow(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+	output, err := c.RunSSH(displayCommand(strings.Join(args, " "), deviceFormat(o.format)))
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+func netconfShow(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+	return netconfCommand(strings.Join(args, " "))(c, session, o, args)
+}
+
+func restShow(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error) {
+	return restCommand(strings.Join(args, " "))(c, session, o, args)
+}
+
+// restCommand runs a fixed command over the REST API.
+func restCommand(command string) restFunc {
+	return func(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error) {
+		output, err := c.GetOutputREST(session, command, deviceFormat(o.format))
+		if err != nil {
+			return nil, err
+		}
+		return rawOutput(output, o), nil
+	}
+}
+
+func telnetShow(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error) {
+	return telnetCommand(strings.Join(args, " "))(c, session, o, args)
+}
+
+// telnetCommand runs a fixed command over telnet.
+func telnetCommand(command string) telnetFunc {
+	return func(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error) {
+		output, err := c.GetOutputTelnet(session, command, deviceFormat(o.format))
+		if err != nil {
+			return nil, err
+		}
+		return rawOutput(output, o), nil
+	}
+}
+
+// netconfCommand runs a fixed command over netconf, which only returns text or XML.
+func netconfCommand(command string) netconfFunc {
+	return func(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+		format := "text"
+		if o.format == "xml" {
+			format = "xml"
+		}
+		return session.Command(command, format)
+	}
+}
+
+// configFormat returns the configuration format asked for on the command
+// line, or the one matching the output format.
+func configFormat(o *options, args []string) (string, error) {
+	if len(args) == 0 {
+		return deviceFormat(o.format), nil
+	}
+	switch args[0] {
+	case "text", "set", "xml", "json":
+		return args[0], nil
+	}
+	return "", fmt.Errorf("unsupported configuration format %q", args[0])
+}
+
+func sshConfig(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+	format, err := configFormat(o, args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.RunSSH(displayCommand("show configuration", format))
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+func netconfConfig(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+	format, err := configFormat(o, args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.GetConfig(session, format)
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+func restConfig(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error) {
+	format, err := configFormat(o, args)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.GetConfigREST(session, format)
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+func telnetConfig(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error) {
+	format, err := configFormat(o, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var output string
+	if format == "text" {
+		output, err = c.GetOutputTelnet(session, "show configuration", "text")
+	} else {
+		output, err = c.GetConfigTelnet(session, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+// logFile returns the log file asked for on the command line.
+func logFile(args []string) string {
+	if len(args) == 0 {
+		return "messages"
+	}
+	return args[0]
+}
+
+func sshLogs(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+	return c.RunSSH("show log " + logFile(args))
+}
+
+func netconfLogs(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+	return session.Command("show log "+logFile(args), "text")
+}
+
+func restLogs(c *networkapi.Client, session *networkapi.RESTSession, o *options, args []string) (interface{}, error) {
+	return c.GetOutputREST(session, "show log "+logFile(args), "text")
+}
+
+func telnetLogs(c *networkapi.Client, session *networkapi.TelnetSession, o *options, args []string) (interface{}, error) {
+	return c.GetOutputTelnet(session, "show log "+logFile(args), "text")
+}
+
+func sshCommitHistory(c *networkapi.Client, o *options, args []string) (interface{}, error) {
+	output, err := c.RunSSH(displayCommand("show system commit", deviceFormat(o.format)))
+	if err != nil {
+		return nil, err
+	}
+	return rawOutput(output, o), nil
+}
+
+func netconfCommitHistory(c *networkapi.Client, session *junos.Junos, o *options, args []string) (interface{}, error) {
+	output, err := c.GetCommitHistory(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []networkapi.CommitHistory
+	if err := json.Unmarshal([]byte(output), &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,246 @@
+// Command networkapi collects data from network devices using the networkapi library.
+//
+// Usage:
+//
+//	networkapi <command> [flags] [arguments]
+//
+// The target is either a single device given with --host, or a set of devices
+// picked from an inventory file with --inventory and --select.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+type options struct {
+	host      string
+	inventory string
+	selector  string
+	username  string
+	password  string
+	format    string
+	transport string
+	parallel  int
+	timeout   time.Duration
+
+	restPort     int
+	restHTTPS    bool
+	restCA       string
+	restInsecure bool
+
+	telnetPort   int
+	telnetPrompt string
+}
+
+type result struct {
+	Host  string      `json:"host" yaml:"host" xml:"host,attr"`
+	Data  interface{} `json:"data,omitempty" yaml:"data,omitempty" xml:"data,omitempty"`
+	Error string      `json:"error,omitempty" yaml:"error,omitempty" xml:"error,omitempty"`
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: networkapi <command> [flags] [arguments]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, commands[name].help)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'networkapi <command> -h' for the list of flags.\n")
+}
+
+func main() {
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "networkapi: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	var opts options
+	fs := flag.NewFlagSet("networkapi "+os.Args[1], flag.ExitOnError)
+	fs.StringVar(&opts.host, "host", "", "hostname or address of a single device")
+	fs.StringVar(&opts.inventory, "inventory", os.Getenv("NETWORKAPI_INVENTORY"), "inventory file (JSON or YAML)")
+	fs.StringVar(&opts.selector, "select", "all", "inventory selector, e.g. \"core-*,site=ams1,tag=edge\"")
+	fs.StringVar(&opts.username, "user", os.Getenv("NETWORKAPI_USER"), "username, overrides the inventory")
+	fs.StringVar(&opts.password, "password", os.Getenv("NETWORKAPI_PASSWORD"), "password, overrides the inventory")
+	fs.StringVar(&opts.format, "format", "text", "output format: text, json, xml, yaml or table")
+	fs.StringVar(&opts.transport, "transport", "ssh", "transport: ssh, netconf, rest or telnet")
+	fs.IntVar(&opts.parallel, "parallel", 10, "number of devices queried at the same time")
+	fs.DurationVar(&opts.timeout, "timeout", 60*time.Second, "timeout per device")
+	fs.IntVar(&opts.restPort, "rest-port", 0, "port of the REST API, 3000 or 3443 with --rest-https by default")
+	fs.BoolVar(&opts.restHTTPS, "rest-https", false, "connect to the REST API over HTTPS")
+	fs.StringVar(&opts.restCA, "rest-ca", "", "PEM file of the certificate authorities trusted for the REST API")
+	fs.BoolVar(&opts.restInsecure, "rest-insecure", false, "don't verify the certificate of the REST API")
+	fs.IntVar(&opts.telnetPort, "telnet-port", 23, "telnet port, e.g. the port of a console server line")
+	fs.StringVar(&opts.telnetPrompt, "telnet-prompt", "", "regular expression matching the CLI prompt over telnet, the prompt after login by default")
+	fs.Parse(os.Args[2:])
+
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "networkapi: %s\n", err)
+		os.Exit(2)
+	}
+	if cmd.minArgs > fs.NArg() {
+		fmt.Fprintf(os.Stderr, "networkapi: %s requires %s\n", os.Args[1], cmd.args)
+		os.Exit(2)
+	}
+
+	clients, err := opts.clients()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "networkapi: %s\n", err)
+		os.Exit(2)
+	}
+
+	results := run(clients, &opts, cmd, fs.Args())
+	if err := render(os.Stdout, opts.format, results); err != nil {
+		fmt.Fprintf(os.Stderr, "networkapi: %s\n", err)
+		os.Exit(1)
+	}
+
+	for _, res := range results {
+		if res.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
+
+func (o *options) validate() error {
+
+	switch o.format {
+	case "text", "json", "xml", "yaml", "table":
+	default:
+		return fmt.Errorf("unsupported format %q", o.format)
+	}
+
+	switch o.transport {
+	case "ssh", "netconf", "rest", "telnet":
+	default:
+		return fmt.Errorf("unsupported transport %q", o.transport)
+	}
+
+	if o.host == "" && o.inventory == "" {
+		return fmt.Errorf("either --host or --inventory is required")
+	}
+	if o.parallel < 1 {
+		o.parallel = 1
+	}
+
+	return nil
+}
+
+// clients returns the clients of the selected devices, keyed by device name.
+func (o *options) clients() (map[string]*networkapi.Client, error) {
+
+	clients := make(map[string]*networkapi.Client)
+
+	if o.host != "" {
+		clients[o.host] = networkapi.NetworkClient(o.host, o.username, o.password)
+		return clients, nil
+	}
+
+	inventory, err := networkapi.LoadInventory(o.inventory)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := inventory.Select(o.selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no device matches %q", o.selector)
+	}
+
+	for _, device := range devices {
+		client := inventory.Client(device)
+		if o.username != "" {
+			client.Username = o.username
+		}
+		if o.password != "" {
+			client.Password = o.password
+		}
+		clients[device.Hostname] = client
+	}
+
+	return clients, nil
+}
+
+// run executes the command against every client, at most o.parallel at a
+// time, and returns the results sorted by host.
+func run(clients map[string]*networkapi.Client, o *options, cmd *command, args []string) []result {
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []result
+	)
+
+	sem := make(chan struct{}, o.parallel)
+	for host, client := range clients {
+		wg.Add(1)
+		go func(host string, client *networkapi.Client) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res := result{Host: host}
+			data, err := withTimeout(o.timeout, func() (interface{}, error) {
+				return cmd.run(client, o, args)
+			})
+			if err != nil {
+				res.Error = err.Error()
+			} else {
+				res.Data = data
+			}
+
+			mu.Lock()
+			results = append(results, res)
+			mu.Unlock()
+		}(host, client)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return strings.Compare(results[i].Host, results[j].Host) < 0
+	})
+
+	return results
+}
+
+// withTimeout runs fn and gives up waiting for it after timeout. The library
+// calls are blocking, so a timed out call is left to finish in the background.
+func withTimeout(timeout time.Duration, fn func() (interface{}, error)) (interface{}, error) {
+
+	type outcome struct {
+		data interface{}
+		err  error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		data, err := fn()
+		done <- outcome{data, err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.data, out.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+// render writes the results in the requested output format.
+func render(w io.Writer, format string, results []result) error {
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", output)
+		return err
+	case "yaml":
+		output, err := yaml.Marshal(results)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(output)
+		return err
+	case "xml":
+		return renderXML(w, results)
+	case "table":
+		return renderTable(w, results)
+	}
+	return renderText(w, results)
+}
+
+// renderText prints the output of every device, preceded by its name when
+// more than one device was queried. Structured data is printed as a table.
+func renderText(w io.Writer, results []result) error {
+	for _, res := range results {
+		if res.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", res.Host, res.Error)
+			continue
+		}
+		if len(results) > 1 {
+			fmt.Fprintf(w, "==> %s <==\n", res.Host)
+		}
+		if output, ok := res.Data.(string); ok {
+			fmt.Fprintln(w, strings.TrimRight(output, "\n"))
+			continue
+		}
+		if err := renderTable(w, []result{res}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderXML prints the results wrapped in a <results> element. Output that is
+// already XML is written as is.
+func renderXML(w io.Writer, results []result) error {
+	fmt.Fprintln(w, "<results>")
+	for _, res := range results {
+		fmt.Fprintf(w, "<result host=%q>\n", res.Host)
+		switch data := res.Data.(type) {
+		case nil:
+		case string:
+			fmt.Fprintln(w, strings.TrimSpace(data))
+		default:
+			output, err := xml.MarshalIndent(data, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", output)
+		}
+		if res.Error != "" {
+			fmt.Fprint(w, "<error>")
+			xml.EscapeText(w, []byte(res.Error))
+			fmt.Fprintln(w, "</error>")
+		}
+		fmt.Fprintln(w, "</result>")
+	}
+	fmt.Fprintln(w, "</results>")
+	return nil
+}
+
+// renderTable prints structs and slices of structs as one table with a HOST
+// column. Anything else is printed as text below the name of the device.
+func renderTable(w io.Writer, results []result) error {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := false
+
+	for _, res := range results {
+		if res.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", res.Host, res.Error)
+			continue
+		}
+
+		rows := tableRows(res.Data)
+		if rows == nil {
+			tw.Flush()
+			fmt.Fprintf(w, "==> %s <==\n%v\n", res.Host, res.Data)
+			continue
+		}
+		if !header && len(rows) > 0 {
+			fmt.Fprintf(tw, "HOST\t%s\n", strings.Join(tableHeader(rows[0]), "\t"))
+			header = true
+		}
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\n", res.Host, strings.Join(tableRow(row), "\t"))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// tableRows returns the structs held by data, or nil when data isn't a struct
+// or a slice of structs.
+func tableRows(data interface{}) []reflect.Value {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	switch {
+	case v.Kind() == reflect.Struct:
+		return []reflect.Value{v}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		rows := make([]reflect.Value, v.Len())
+		for i := range rows {
+			rows[i] = v.Index(i)
+		}
+		return rows
+	}
+	return nil
+}
+
+func tableHeader(row reflect.Value) []string {
+	var header []string
+	for i := 0; i < row.NumField(); i++ {
+		field := row.Type().Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		header = append(header, strings.ToUpper(name))
+	}
+	return header
+}
+
+func tableRow(row reflect.Value) []string {
+	var values []string
+	for i := 0; i < row.NumField(); i++ {
+		field := row.Type().Field(i)
+		if field.PkgPath != "" || field.Name == "XMLName" {
+			continue
+		}
+		values = append(values, fmt.Sprint(row.Field(i).Interface()))
+	}
+	return values
+}
@@ -0,0 +1,144 @@
+// Package compliance checks device configurations against rules.
+//
+// Rules are written in YAML, or in Go, against the configuration tree of
+// configtree, so they apply whatever format the configuration was retrieved
+// in. Every rule passes or fails per device, a failure lists the offending
+// statements with the set or delete commands fixing them.
+package compliance
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+// Result is the outcome of a rule on one device.
+type Result struct {
+	Host        string      `json:"host"`
+	Rule        string      `json:"rule"`
+	Description string      `json:"description,omitempty"`
+	Passed      bool        `json:"passed"`
+	Violations  []Violation `json:"violations,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// Check evaluates the rules on the configuration of a device.
+func Check(host string, config *configtree.Node, rules []Rule) []Result {
+	results := make([]Result, len(rules))
+	for i, rule := range rules {
+		violations := rule.Evaluate(config)
+		results[i] = Result{
+			Host:        host,
+			Rule:        rule.Name,
+			Description: rule.Description,
+			Passed:      len(violations) == 0,
+			Violations:  violations,
+		}
+	}
+	return results
+}
+
+// Audit fetches the configuration of the devices and evaluates the rules on
+// each of them. Results are sorted by host, then in the order of the rules.
+// A device whose configuration can't be fetched fails every rule.
+func Audit(inventory *networkapi.Inventory, devices []networkapi.Device, rules []Rule, parallel int) []Result {
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string][]Result)
+	)
+
+	sem := make(chan struct{}, parallel)
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d networkapi.Device) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var res []Result
+			config, err := fetch(inventory.Client(d))
+			if err != nil {
+				res = failed(d.Hostname, rules, err)
+			} else {
+				res = Check(d.Hostname, config, rules)
+			}
+
+			mu.Lock()
+			results[d.Hostname] = res
+			mu.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	hosts := make([]string, 0, len(results))
+	for host := range results {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var all []Result
+	for _, host := range hosts {
+		all = append(all, results[host]...)
+	}
+	return all
+}
+
+// fetch reads the configuration of the device in the set format.
+func fetch(c *networkapi.Client) (*configtree.Node, error) {
+
+	defer c.DisconnectSSH()
+
+	config, err := c.RunSSH("show configuration | display set")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(config) == "" {
+		return nil, errors.New("empty set configuration")
+	}
+	return configtree.ParseSet([]byte(config))
+}
+
+func failed(host string, rules []Rule, err error) []Result {
+	results := make([]Result, len(rules))
+	for i, rule := range rules {
+		results[i] = Result{Host: host, Rule: rule.Name, Description: rule.Description, Error: err.Error()}
+	}
+	return results
+}
+
+// Remediation returns the commands fixing the violations of the results of a
+// device, without duplicates.
+func Remediation(results []Result) []string {
+	var commands []string
+	seen := make(map[string]bool)
+	for _, res := range results {
+		for _, v := range res.Violations {
+			for _, command := range v.Remediation {
+				if !seen[command] {
+					seen[command] = true
+					commands = append(commands, command)
+				}
+			}
+		}
+	}
+	return commands
+}
+
+// String returns the violation as a report line.
+func (v Violation) String() string {
+	s := configtree.PathString(v.Path) + ": " + v.Message
+	if len(v.Remediation) > 0 {
+		s += " (" + strings.Join(v.Remediation, "; ") + ")"
+	}
+	return s
+}
@@ -0,0 +1,255 @@
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kgrvamsi/networkapi/configtree"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule is a compliance rule.
+//
+// Path selects the statements the rule applies to, one pattern per word:
+// "protocols bgp group *" selects every BGP group. A pattern is a glob where
+// * matches any text, alternatives are separated by |, "interfaces
+// ge-*|xe-*|et-*" selects the physical interfaces.
+//
+// Each selected statement is then checked:
+//   - Require lists the statements that must be configured below it,
+//   - Forbid lists the statements that must not be configured below it,
+//   - Values lists the values it must hold, no more and no less.
+//
+// The command suggested for a statement missing from Require sets it, with a
+// placeholder for its value: "set interfaces ge-0/0/0 description
+// <description>". Remediation overrides it, "{path}" being replaced with the
+// selected statement and "{name}" with its last word. Rules written in Go
+// set Check instead.
+type Rule struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Path        string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Require     []string `json:"require,omitempty" yaml:"require,omitempty"`
+	Forbid      []string `json:"forbid,omitempty" yaml:"forbid,omitempty"`
+	Values      []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Remediation string   `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+
+	// Check returns the violations of the configuration.
+	Check func(config *configtree.Node) []Violation `json:"-" yaml:"-"`
+}
+
+// Violation is a statement not complying with a rule.
+type Violation struct {
+	Path        []string `json:"path"`
+	Message     string   `json:"message"`
+	Remediation []string `json:"remediation,omitempty"`
+}
+
+type ruleFile struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// LoadRules reads the rules from a YAML or JSON file.
+func LoadRules(filename string) ([]Rule, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file ruleFile
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported rules format %q", filepath.Ext(filename))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading rules %s - %s", filename, err)
+	}
+
+	for i, rule := range file.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("rules %s: rule %d: %s", filename, i, err)
+		}
+	}
+	return file.Rules, nil
+}
+
+// Validate reports whether the rule is complete.
+func (r Rule) Validate() error {
+	switch {
+	case r.Name == "":
+		return fmt.Errorf("rule has no name")
+	case r.Check != nil:
+		return nil
+	case strings.TrimSpace(r.Path) == "":
+		return fmt.Errorf("%s: no path", r.Name)
+	case len(r.Require) == 0 && len(r.Forbid) == 0 && len(r.Values) == 0:
+		return fmt.Errorf("%s: nothing to check, set require, forbid or values", r.Name)
+	}
+	return nil
+}
+
+// Evaluate returns the violations of the configuration.
+func (r Rule) Evaluate(config *configtree.Node) []Violation {
+
+	if r.Check != nil {
+		return r.Check(config)
+	}
+
+	pattern := strings.Fields(r.Path)
+	matches := Find(config, r.Path)
+	if len(matches) == 0 && !hasGlob(pattern) {
+		// A missing statement has no required statement nor value.
+		matches = []Match{{Path: pattern, Node: configtree.New()}}
+	}
+
+	var violations []Violation
+	for _, m := range matches {
+		violations = append(violations, r.require(m)...)
+		violations = append(violations, r.forbid(m)...)
+		violations = append(violations, r.values(m)...)
+	}
+	return violations
+}
+
+func (r Rule) require(m Match) []Violation {
+
+	var violations []Violation
+	for _, statement := range r.Require {
+		if len(Find(m.Node, statement)) > 0 {
+			continue
+		}
+		v := Violation{Path: m.Path, Message: "missing " + statement}
+		switch {
+		case r.Remediation != "":
+			replacer := strings.NewReplacer("{path}", configtree.PathString(m.Path), "{name}", configtree.Quote(m.Path[len(m.Path)-1]))
+			v.Remediation = []string{replacer.Replace(r.Remediation)}
+		case !hasGlob(strings.Fields(statement)):
+			words := strings.Fields(statement)
+			command := "set " + configtree.PathString(append(m.Path[:len(m.Path):len(m.Path)], words...))
+			if last := words[len(words)-1]; configtree.TakesValue(last) {
+				command += " <" + last + ">"
+			}
+			v.Remediation = []string{command}
+		}
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+func (r Rule) forbid(m Match) []Violation {
+
+	var violations []Violation
+	for _, statement := range r.Forbid {
+		for _, found := range Find(m.Node, statement) {
+			path := append(append([]string(nil), m.Path...), found.Path...)
+			violations = append(violations, Violation{
+				Path:        path,
+				Message:     "forbidden " + statement,
+				Remediation: []string{"delete " + configtree.PathString(path)},
+			})
+		}
+	}
+	return violations
+}
+
+func (r Rule) values(m Match) []Violation {
+
+	if len(r.Values) == 0 {
+		return nil
+	}
+
+	var violations []Violation
+	want := make(map[string]bool)
+	for _, value := range r.Values {
+		want[value] = true
+		if m.Node.Child(value) == nil {
+			violations = append(violations, Violation{
+				Path:        m.Path,
+				Message:     "missing value " + value,
+				Remediation: []string{"set " + configtree.PathString(append(m.Path[:len(m.Path):len(m.Path)], value))},
+			})
+		}
+	}
+	for _, child := range m.Node.Children {
+		if child.Key && !want[child.Name] {
+			path := append(m.Path[:len(m.Path):len(m.Path)], child.Name)
+			violations = append(violations, Violation{
+				Path:        path,
+				Message:     "unexpected value " + child.Name,
+				Remediation: []string{"delete " + configtree.PathString(path)},
+			})
+		}
+	}
+	return violations
+}
+
+// Match is a statement selected by a path pattern.
+type Match struct {
+	Path []string
+	Node *configtree.Node
+}
+
+// Find returns the statements below node matching the path pattern, one glob
+// per word. Inactive statements aren't in effect and never match, neither
+// does an empty pattern.
+func Find(node *configtree.Node, pattern string) []Match {
+	words := strings.Fields(pattern)
+	if len(words) == 0 {
+		return nil
+	}
+	var matches []Match
+	find(node, nil, words, &matches)
+	return matches
+}
+
+func find(node *configtree.Node, path, pattern []string, matches *[]Match) {
+	if len(pattern) == 0 {
+		*matches = append(*matches, Match{Path: path, Node: node})
+		return
+	}
+	for _, child := range node.Children {
+		if !child.Inactive && matchWord(pattern[0], child.Name) {
+			find(child, append(path[:len(path):len(path)], child.Name), pattern[1:], matches)
+		}
+	}
+}
+
+// globs caches the regular expressions of the glob patterns, the same rules
+// being evaluated on every statement of every device.
+var globs sync.Map
+
+// matchWord reports whether word matches the glob pattern.
+func matchWord(pattern, word string) bool {
+	if !strings.ContainsAny(pattern, "*|") {
+		return pattern == word
+	}
+	if re, ok := globs.Load(pattern); ok {
+		return re.(*regexp.Regexp).MatchString(word)
+	}
+	alternatives := strings.Split(pattern, "|")
+	for i, alt := range alternatives {
+		alternatives[i] = strings.Replace(regexp.QuoteMeta(alt), `\*`, ".*", -1)
+	}
+	re := regexp.MustCompile(`^(` + strings.Join(alternatives, "|") + `)$`)
+	globs.Store(pattern, re)
+	return re.MatchString(word)
+}
+
+func hasGlob(words []string) bool {
+	for _, word := range words {
+		if strings.ContainsAny(word, "*|") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package configtree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeType is the kind of a configuration change.
+type ChangeType string
+
+// Kinds of configuration changes.
+const (
+	Added       ChangeType = "added"
+	Removed     ChangeType = "removed"
+	Changed     ChangeType = "changed"
+	Reordered   ChangeType = "reordered"
+	Activated   ChangeType = "activated"
+	Deactivated ChangeType = "deactivated"
+	Protected   ChangeType = "protected"
+	Unprotected ChangeType = "unprotected"
+)
+
+// Change is a difference between two configurations.
+//
+// Path is the hierarchy of the statement. Node holds the added or removed
+// statement with everything below it. Old and New hold the values of a
+// changed statement, or the entries of a reordered one.
+type Change struct {
+	Type ChangeType `json:"type"`
+	Path []string   `json:"path"`
+	Node *Node      `json:"-"`
+	Old  []string   `json:"old,omitempty"`
+	New  []string   `json:"new,omitempty"`
+}
+
+// String returns the change in a form suited to a report.
+func (c Change) String() string {
+	path := PathString(c.Path)
+	switch c.Type {
+	case Added:
+		return "+ " + path
+	case Removed:
+		return "- " + path
+	case Changed:
+		return fmt.Sprintf("~ %s: %s -> %s", path, PathString(c.Old), PathString(c.New))
+	case Reordered:
+		return fmt.Sprintf("~ %s: order [%s] -> [%s]", path, PathString(c.Old), PathString(c.New))
+	}
+	return fmt.Sprintf("~ %s: %s", path, c.Type)
+}
+
+// Diff returns the changes turning the old configuration into the new one.
+//
+// Statements are matched by name, so their order doesn't matter, except for
+// the entries of the statements in orderedKeywords, such as policy terms.
+// A statement whose single value changed, "host-name r1" becoming
+// "host-name r2", is reported as changed instead of removed and added.
+func Diff(old, new *Node) []Change {
+	var changes []Change
+	diff(nil, old, new, &changes)
+	return changes
+}
+
+func diff(path []string, old, new *Node, changes *[]Change) {
+
+	if len(path) > 0 {
+		if old.Inactive != new.Inactive {
+			*changes = append(*changes, Change{Type: activation(new.Inactive), Path: path})
+		}
+		if old.Protect != new.Protect {
+			*changes = append(*changes, Change{Type: protection(new.Protect), Path: path})
+		}
+	}
+
+	if isValue(old) && isValue(new) {
+		if o, n := old.Children[0], new.Children[0]; o.Name != n.Name {
+			*changes = append(*changes, Change{Type: Changed, Path: path, Old: []string{o.Name}, New: []string{n.Name}})
+			return
+		}
+	}
+
+	for _, child := range old.Children {
+		if new.Child(child.Name) == nil {
+			entries(Removed, childPath(path, child), child, changes)
+		}
+	}
+	for _, child := range new.Children {
+		if prev := old.Child(child.Name); prev != nil {
+			diff(childPath(path, child), prev, child, changes)
+		} else {
+			entries(Added, childPath(path, child), child, changes)
+		}
+	}
+
+	if orderedKeywords[new.Name] && !new.Key {
+		oldOrder, newOrder := commonOrder(old, new), commonOrder(new, old)
+		if strings.Join(oldOrder, "\x00") != strings.Join(newOrder, "\x00") {
+			*changes = append(*changes, Change{Type: Reordered, Path: path, Old: oldOrder, New: newOrder})
+		}
+	}
+}
+
+// entries reports an added or removed statement. A list is reported entry by
+// entry, "neighbor 10.0.0.1" and "neighbor 10.0.0.2" rather than "neighbor".
+func entries(kind ChangeType, path []string, node *Node, changes *[]Change) {
+	list := !node.Key && !node.Inactive && !node.Protect && !node.IsLeaf()
+	for _, child := range node.Children {
+		list = list && child.Key
+	}
+	if !list {
+		*changes = append(*changes, Change{Type: kind, Path: path, Node: node})
+		return
+	}
+	for _, child := range node.Children {
+		*changes = append(*changes, Change{Type: kind, Path: childPath(path, child), Node: child})
+	}
+}
+
+// isValue reports whether n holds a single value, as "host-name r1" does, or
+// is a syslog facility holding its level, as "any notice" does.
+func isValue(n *Node) bool {
+	return len(n.Children) == 1 && (n.Children[0].Key || n.IsFacility()) && n.Children[0].IsLeaf()
+}
+
+// commonOrder returns the names of the children of a also found in b, in the order of a.
+func commonOrder(a, b *Node) []string {
+	var names []string
+	for _, child := range a.Children {
+		if b.Child(child.Name) != nil {
+			names = append(names, child.Name)
+		}
+	}
+	return names
+}
+
+func childPath(path []string, child *Node) []string {
+	return append(append([]string(nil), path...), child.Name)
+}
+
+func activation(inactive bool) ChangeType {
+	if inactive {
+		return Deactivated
+	}
+	return Activated
+}
+
+func protection(protect bool) ChangeType {
+	if protect {
+		return Protected
+	}
+	return Unprotected
+}
+
+// SetCommands returns the set, delete, insert, activate and deactivate
+// commands applying the changes.
+func SetCommands(changes []Change) []string {
+
+	var commands []string
+	for _, c := range changes {
+		path := PathString(c.Path)
+		switch c.Type {
+		case Added:
+			commands = append(commands, StatementCommands(c.Path, c.Node)...)
+		case Removed:
+			commands = append(commands, "delete "+path)
+		case Changed:
+			commands = append(commands, "delete "+path, "set "+PathString(append(c.Path[:len(c.Path):len(c.Path)], c.New...)))
+		case Reordered:
+			keyword := c.Path[len(c.Path)-1]
+			for i := 1; i < len(c.New); i++ {
+				commands = append(commands, fmt.Sprintf("insert %s %s after %s %s", path, Quote(c.New[i]), keyword, Quote(c.New[i-1])))
+			}
+		case Activated:
+			commands = append(commands, "activate "+path)
+		case Deactivated:
+			commands = append(commands, "deactivate "+path)
+		case Protected:
+			commands = append(commands, "protect "+path)
+		case Unprotected:
+			commands = append(commands, "unprotect "+path)
+		}
+	}
+	return commands
+}
+
+// StatementCommands returns the commands creating node, found at path, and
+// the statements below it.
+func StatementCommands(path []string, node *Node) []string {
+
+	var commands, flags []string
+	addFlags := func(p []string, n *Node) {
+		if n.Inactive {
+			flags = append(flags, "deactivate "+PathString(p))
+		}
+		if n.Protect {
+			flags = append(flags, "protect "+PathString(p))
+		}
+	}
+
+	if node.IsLeaf() {
+		commands = append(commands, "set "+PathString(path))
+	}
+	addFlags(path, node)
+
+	node.Walk(func(sub []string, n *Node) bool {
+		full := append(append([]string(nil), path...), sub...)
+		if n.IsLeaf() {
+			commands = append(commands, "set "+PathString(full))
+		}
+		addFlags(full, n)
+		return true
+	})
+
+	return append(commands, flags...)
+}
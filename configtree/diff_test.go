@@ -0,0 +1,139 @@
+package configtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// diffTests edit the set format of the captured configuration, replacing the
+// line old by new, or moving it before the first term when reorder is set, and
+// list the changes and the commands expected.
+var diffTests = []struct {
+	name     string
+	old, new string
+	reorder  bool
+	changes  []string
+	commands []string
+}{
+	{
+		name:     "changed value",
+		old:      "set system host-name edge-1",
+		new:      "set system host-name edge-2",
+		changes:  []string{"~ system host-name: edge-1 -> edge-2"},
+		commands: []string{"delete system host-name", "set system host-name edge-2"},
+	},
+	{
+		name:     "changed keyword value",
+		old:      "set snmp community public authorization read-only",
+		new:      "set snmp community public authorization read-write",
+		changes:  []string{"~ snmp community public authorization: read-only -> read-write"},
+		commands: []string{"delete snmp community public authorization", "set snmp community public authorization read-write"},
+	},
+	{
+		name:     "changed syslog level",
+		old:      "set system syslog host 192.0.2.10 any notice",
+		new:      "set system syslog host 192.0.2.10 any warning",
+		changes:  []string{"~ system syslog host 192.0.2.10 any: notice -> warning"},
+		commands: []string{"delete system syslog host 192.0.2.10 any", "set system syslog host 192.0.2.10 any warning"},
+	},
+	{
+		name:     "reordered",
+		old:      "set policy-options policy-statement EXPORT-LO term REJECT then reject",
+		reorder:  true,
+		changes:  []string{"~ policy-options policy-statement EXPORT-LO term: order [LO REJECT] -> [REJECT LO]"},
+		commands: []string{"insert policy-options policy-statement EXPORT-LO term LO after term REJECT"},
+	},
+	{
+		name:     "added",
+		old:      "set protocols bgp group CORE neighbor 10.255.0.2",
+		new:      "set protocols bgp group CORE neighbor 10.255.0.2\nset protocols bgp group CORE neighbor 10.255.0.4",
+		changes:  []string{"+ protocols bgp group CORE neighbor 10.255.0.4"},
+		commands: []string{"set protocols bgp group CORE neighbor 10.255.0.4"},
+	},
+	{
+		name:     "added statements",
+		old:      "set protocols lldp interface all",
+		new:      "set protocols lldp interface all\nset protocols ospf area 0.0.0.0 interface lo0.0 passive",
+		changes:  []string{"+ protocols ospf"},
+		commands: []string{"set protocols ospf area 0.0.0.0 interface lo0.0 passive"},
+	},
+	{
+		name:     "removed",
+		old:      "set interfaces ge-0/0/0 unit 0 family iso",
+		new:      "",
+		changes:  []string{"- interfaces ge-0/0/0 unit 0 family iso"},
+		commands: []string{"delete interfaces ge-0/0/0 unit 0 family iso"},
+	},
+	{
+		name:     "removed list",
+		old:      "set system login class noc permissions view-configuration",
+		new:      "",
+		changes:  []string{"- system login class noc permissions view-configuration"},
+		commands: []string{"delete system login class noc permissions view-configuration"},
+	},
+	{
+		name:     "activated",
+		old:      "deactivate protocols bgp group CORE neighbor 10.255.0.3",
+		new:      "",
+		changes:  []string{"~ protocols bgp group CORE neighbor 10.255.0.3: activated"},
+		commands: []string{"activate protocols bgp group CORE neighbor 10.255.0.3"},
+	},
+}
+
+// editConfig returns the trees of the captured configuration before and after
+// replacing the line old by new, or moving it first among the terms.
+func editConfig(t *testing.T, old, new string, reorder bool) (*Node, *Node) {
+	t.Helper()
+	set := string(readConfig(t, "set"))
+	if !strings.Contains(set, old+"\n") {
+		t.Fatalf("%q not found", old)
+	}
+	edited := strings.Replace(set, old+"\n", new+"\n", 1)
+	if reorder {
+		first := "set policy-options policy-statement EXPORT-LO term LO"
+		edited = strings.Replace(edited, first, old+"\n"+first, 1)
+	}
+	before, err := ParseSet([]byte(set))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := ParseSet([]byte(edited))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return before, after
+}
+
+func TestDiff(t *testing.T) {
+	for _, tt := range diffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := editConfig(t, tt.old, tt.new, tt.reorder)
+			var got []string
+			for _, c := range Diff(before, after) {
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tt.changes) {
+				t.Errorf("Diff() = %q, want %q", got, tt.changes)
+			}
+		})
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	before, after := editConfig(t, "set system host-name edge-1", "set system host-name edge-1", false)
+	if changes := Diff(before, after); len(changes) > 0 {
+		t.Errorf("Diff() = %v, want no changes", changes)
+	}
+}
+
+func TestSetCommands(t *testing.T) {
+	for _, tt := range diffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := editConfig(t, tt.old, tt.new, tt.reorder)
+			if got := SetCommands(Diff(before, after)); !reflect.DeepEqual(got, tt.commands) {
+				t.Errorf("SetCommands() = %q, want %q", got, tt.commands)
+			}
+		})
+	}
+}
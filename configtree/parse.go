@@ -0,0 +1,634 @@
+package configtree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formats lists the configuration formats understood by Parse.
+var Formats = []string{"text", "set", "xml", "json"}
+
+// implicitLists maps the statements whose entries are written without their
+// own keyword in the text and set formats ("interfaces ge-0/0/0") to the
+// element name of the entries in XML and JSON ("interface").
+var implicitLists = map[string]string{
+	"console":           "contents",
+	"interfaces":        "interface",
+	"vlans":             "vlan",
+	"routing-instances": "instance",
+	"bridge-domains":    "domain",
+}
+
+// implicitItems maps the statements whose entries hold values written without
+// a keyword in the text and set formats ("prefix-list PL 10.0.0.0/8") to the
+// element name of those values in XML and JSON ("prefix-list-item").
+//
+// The facilities of the syslog files, hosts and users, "file messages { any
+// notice; }", are the <contents> entries of the destination.
+var implicitItems = map[string]string{
+	"prefix-list": "prefix-list-item",
+	"as-path":     "path",
+	"file":        "contents",
+	"host":        "contents",
+	"user":        "contents",
+}
+
+// ImplicitList returns the XML and JSON element name of the entries of the
+// statement keyword when the text format omits it, or "".
+func ImplicitList(keyword string) string {
+	return implicitLists[keyword]
+}
+
+// ImplicitItem returns the XML and JSON element name of the values held by the
+// entries of the statement keyword when the text format omits it, or "".
+func ImplicitItem(keyword string) string {
+	return implicitItems[keyword]
+}
+
+// implicit reports whether the element name below parent is omitted in the
+// text format. above is the name of the node above parent.
+func implicit(parent *Node, above, name string) bool {
+	if parent.Key {
+		return implicitItems[above] == name
+	}
+	return implicitLists[parent.Name] == name
+}
+
+// Parse parses a configuration in the given format. An empty format is
+// detected from the content.
+func Parse(data []byte, format string) (*Node, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+	switch format {
+	case "text":
+		return ParseText(data)
+	case "set":
+		return ParseSet(data)
+	case "xml":
+		return ParseXML(data)
+	case "json":
+		return ParseJSON(data)
+	}
+	return nil, fmt.Errorf("unsupported configuration format %q", format)
+}
+
+// DetectFormat returns the format of a configuration: text, set, xml or json.
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return "xml"
+	case bytes.HasPrefix(trimmed, []byte("{")) && json.Valid(trimmed):
+		return "json"
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, verb := range []string{"set ", "deactivate ", "protect ", "delete "} {
+			if strings.HasPrefix(line, verb) {
+				return "set"
+			}
+		}
+		break
+	}
+	return "text"
+}
+
+// token kinds of the text and set formats.
+const (
+	tokenWord = iota
+	tokenOpen
+	tokenClose
+	tokenEnd
+	tokenListOpen
+	tokenListClose
+	tokenNewline
+	tokenGroup
+)
+
+// inheritedPattern matches the comment "display inheritance" writes above the
+// statements inherited from a configuration group.
+var inheritedPattern = regexp.MustCompile(`^## '.*' was inherited from group '(.*)'$`)
+
+type token struct {
+	kind int
+	text string
+	line int
+}
+
+// tokenize splits the text or set format into tokens, dropping comments
+// except the inheritance ones, which become group tokens.
+func tokenize(data []byte) ([]token, error) {
+
+	var tokens []token
+	line := 1
+	s := string(data)
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			tokens = append(tokens, token{tokenNewline, "", line})
+			line++
+			i++
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '#':
+			j := i
+			for j < len(s) && s[j] != '\n' {
+				j++
+			}
+			if m := inheritedPattern.FindStringSubmatch(strings.TrimSpace(s[i:j])); m != nil {
+				tokens = append(tokens, token{tokenGroup, m[1], line})
+			}
+			i = j
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(s[i:i+2+end], "\n")
+			i += end + 4
+		case c == '{':
+			tokens = append(tokens, token{tokenOpen, "{", line})
+			i++
+		case c == '}':
+			tokens = append(tokens, token{tokenClose, "}", line})
+			i++
+		case c == ';':
+			tokens = append(tokens, token{tokenEnd, ";", line})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{tokenListOpen, "[", line})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{tokenListClose, "]", line})
+			i++
+		case c == '"':
+			var word strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				if s[j] == '\n' {
+					line++
+				}
+				word.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, token{tokenWord, word.String(), line})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && !strings.ContainsRune("{};[]\"", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, s[i:j], line})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// ParseText parses the curly-brace format of "show configuration".
+func ParseText(data []byte) (*Node, error) {
+
+	tokens, err := tokenize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := New()
+	stack := []*Node{root}
+	var words []string
+	var inactive, protect bool
+	var group string
+
+	statement := func() *Node {
+		node := stack[len(stack)-1].Insert(words...)
+		node.Inactive = node.Inactive || inactive
+		node.Protect = node.Protect || protect
+		if group != "" {
+			node.Group = group
+		}
+		words, inactive, protect, group = nil, false, false, ""
+		return node
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case tokenNewline:
+		case tokenGroup:
+			group = tok.text
+		case tokenWord:
+			switch {
+			case len(words) == 0 && tok.text == "inactive:":
+				inactive = true
+			case len(words) == 0 && tok.text == "protect:":
+				protect = true
+			case len(words) == 0 && tok.text == "replace:":
+			default:
+				words = append(words, tok.text)
+			}
+		case tokenEnd:
+			if len(words) > 0 {
+				statement()
+			}
+		case tokenOpen:
+			if len(words) == 0 {
+				return nil, fmt.Errorf("line %d: block without statement", tok.line)
+			}
+			stack = append(stack, statement())
+		case tokenClose:
+			if len(words) > 0 {
+				return nil, fmt.Errorf("line %d: missing ; before }", tok.line)
+			}
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: unexpected }", tok.line)
+			}
+			stack = stack[:len(stack)-1]
+		case tokenListOpen:
+			if len(words) == 0 {
+				return nil, fmt.Errorf("line %d: list without statement", tok.line)
+			}
+			node := statement()
+			for i++; i < len(tokens) && tokens[i].kind != tokenListClose; i++ {
+				if tokens[i].kind == tokenWord {
+					node.Add(tokens[i].text, true)
+				}
+			}
+			if i >= len(tokens) {
+				return nil, fmt.Errorf("line %d: unterminated list", tok.line)
+			}
+		case tokenListClose:
+			return nil, fmt.Errorf("line %d: unexpected ]", tok.line)
+		}
+	}
+
+	if len(words) > 0 {
+		return nil, fmt.Errorf("missing ; at the end of the configuration")
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing } at the end of the configuration")
+	}
+
+	return root, nil
+}
+
+// ParseSet parses the "display set" format. Besides set, the deactivate,
+// activate, protect, unprotect and delete commands are applied in order.
+func ParseSet(data []byte) (*Node, error) {
+
+	tokens, err := tokenize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := New()
+	var words []string
+	line := 1
+
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && tokens[i].kind == tokenGroup {
+			continue
+		}
+		if i < len(tokens) && tokens[i].kind != tokenNewline {
+			if tokens[i].kind != tokenWord {
+				return nil, fmt.Errorf("line %d: unexpected %q", tokens[i].line, tokens[i].text)
+			}
+			words = append(words, tokens[i].text)
+			line = tokens[i].line
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if err := applyCommand(root, words[0], words[1:]); err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+		words = nil
+	}
+
+	return root, nil
+}
+
+func applyCommand(root *Node, verb string, path []string) error {
+
+	if verb == "set" {
+		if len(path) == 0 {
+			return fmt.Errorf("set without statement")
+		}
+		root.Insert(path...)
+		return nil
+	}
+
+	node := root.Lookup(path...)
+	if node == nil && verb != "delete" {
+		return fmt.Errorf("%s: statement not found: %s", verb, PathString(path))
+	}
+
+	switch verb {
+	case "deactivate":
+		node.Inactive = true
+	case "activate":
+		node.Inactive = false
+	case "protect":
+		node.Protect = true
+	case "unprotect":
+		node.Protect = false
+	case "delete":
+		if len(path) == 0 {
+			root.Children = nil
+		} else if parent := root.Lookup(path[:len(path)-1]...); parent != nil {
+			parent.Remove(path[len(path)-1])
+		}
+	default:
+		return fmt.Errorf("unsupported command %q", verb)
+	}
+	return nil
+}
+
+// element is an XML element of the configuration.
+type element struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*element
+}
+
+// ParseXML parses the "display xml" format or a NETCONF <configuration>.
+func ParseXML(data []byte) (*Node, error) {
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no <configuration> element found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "configuration" {
+			config, err := readElement(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			root := New()
+			for _, child := range config.children {
+				addElement(root, "", child)
+			}
+			return root, nil
+		}
+	}
+}
+
+func readElement(dec *xml.Decoder, start xml.StartElement) (*element, error) {
+
+	e := &element{name: start.Name.Local, attrs: make(map[string]string)}
+	for _, attr := range start.Attr {
+		e.attrs[attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := readElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			e.children = append(e.children, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			e.text = strings.TrimSpace(text.String())
+			return e, nil
+		}
+	}
+}
+
+// addElement adds an XML element below parent, above being the name of the
+// node above parent. List entries are recognized by their <name> child,
+// leaves by their text.
+func addElement(parent *Node, above string, e *element) {
+
+	node, up := parent, above
+	if !implicit(parent, above, e.name) {
+		node, up = parent.Add(e.name, false), parent.Name
+	}
+
+	var children []*element
+	for _, child := range e.children {
+		if child.name == "name" && len(child.children) == 0 {
+			node, up = node.Add(child.text, true), node.Name
+			continue
+		}
+		children = append(children, child)
+	}
+	if len(e.children) == 0 && e.text != "" {
+		node, up = node.Add(e.text, true), node.Name
+	}
+
+	if e.attrs["inactive"] == "inactive" {
+		node.Inactive = true
+	}
+	if e.attrs["protect"] == "protect" {
+		node.Protect = true
+	}
+	if group := e.attrs["group"]; group != "" {
+		node.Group = group
+	}
+
+	for _, child := range children {
+		addElement(node, up, child)
+	}
+}
+
+// jsonMember is a member of a JSON object, kept in document order.
+type jsonMember struct {
+	key   string
+	value interface{}
+}
+
+// ParseJSON parses the "display json" format.
+func ParseJSON(data []byte) (*Node, error) {
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.([]jsonMember)
+	if !ok {
+		return nil, fmt.Errorf("configuration is not a JSON object")
+	}
+	for _, member := range object {
+		if member.key == "configuration" {
+			object, ok = member.value.([]jsonMember)
+			if !ok {
+				return nil, fmt.Errorf("configuration is not a JSON object")
+			}
+			break
+		}
+	}
+
+	root := New()
+	addObject(root, "", object)
+	return root, nil
+}
+
+// decodeJSON decodes the next JSON value, objects as []jsonMember.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			var object []jsonMember
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				object = append(object, jsonMember{key.(string), value})
+			}
+			_, err := dec.Token()
+			return object, err
+		case '[':
+			array := []interface{}{}
+			for dec.More() {
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			}
+			_, err := dec.Token()
+			return array, err
+		}
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	}
+
+	return tok, nil
+}
+
+// addObject adds the members of a JSON object below node, above being the name
+// of the node above it. Attributes of the object are in the "@" member, those
+// of a leaf in "@<leaf name>".
+func addObject(node *Node, above string, object []jsonMember) {
+
+	leafAttrs := make(map[string][]jsonMember)
+	for _, member := range object {
+		switch {
+		case member.key == "@":
+			attrs, _ := member.value.([]jsonMember)
+			applyAttrs(node, attrs)
+		case strings.HasPrefix(member.key, "@"):
+			attrs, _ := member.value.([]jsonMember)
+			leafAttrs[member.key[1:]] = attrs
+		default:
+			addMember(node, above, member.key, member.value)
+		}
+	}
+
+	for name, attrs := range leafAttrs {
+		if leaf := node.Child(name); leaf != nil {
+			if len(leaf.Children) == 1 && leaf.Children[0].IsLeaf() {
+				leaf = leaf.Children[0]
+			}
+			applyAttrs(leaf, attrs)
+		}
+	}
+}
+
+func addMember(parent *Node, above, name string, value interface{}) {
+
+	// child returns the node of the member and the name of the node above it.
+	child := func() (*Node, string) {
+		if implicit(parent, above, name) {
+			return parent, above
+		}
+		return parent.Add(name, false), parent.Name
+	}
+
+	switch v := value.(type) {
+	case []jsonMember:
+		node, up := child()
+		addObject(node, up, v)
+	case []interface{}:
+		node, up := child()
+		for _, item := range v {
+			switch entry := item.(type) {
+			case nil:
+				// [null] is how JSON spells a statement without value.
+			case []jsonMember:
+				target, targetUp := node, up
+				var rest []jsonMember
+				for _, member := range entry {
+					if s, ok := member.value.(string); ok && member.key == "name" {
+						target, targetUp = node.Add(s, true), node.Name
+						continue
+					}
+					rest = append(rest, member)
+				}
+				addObject(target, targetUp, rest)
+			case string:
+				node.Add(entry, true)
+			}
+		}
+	case string:
+		node, _ := child()
+		node.Add(v, true)
+	case nil:
+		child()
+	}
+}
+
+func applyAttrs(node *Node, attrs []jsonMember) {
+	for _, attr := range attrs {
+		switch attr.key {
+		case "inactive":
+			node.Inactive = attr.value == "true"
+		case "protect":
+			node.Protect = attr.value == "true"
+		case "junos:group":
+			node.Group, _ = attr.value.(string)
+		}
+	}
+}
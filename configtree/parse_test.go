@@ -0,0 +1,100 @@
+package configtree
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// configFiles maps the formats to the captured configuration of edge-1, shown
+// with "show configuration" and its display set, xml and json pipes.
+var configFiles = map[string]string{
+	"text": "../testdata/junos/config.txt",
+	"set":  "../testdata/junos/config.set",
+	"xml":  "../testdata/junos/config.xml",
+	"json": "../testdata/junos/config.json",
+}
+
+func readConfig(t *testing.T, format string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(configFiles[format])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func parseConfig(t *testing.T, format string) *Node {
+	t.Helper()
+	tree, err := Parse(readConfig(t, format), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestParse(t *testing.T) {
+	want := parseConfig(t, "text")
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			data := readConfig(t, format)
+			if got := DetectFormat(data); got != format {
+				t.Errorf("DetectFormat() = %s, want %s", got, format)
+			}
+			tree, err := Parse(data, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changes := Diff(want, tree); len(changes) > 0 {
+				t.Errorf("Parse() differs from the text format: %v", changes)
+			}
+			if !reflect.DeepEqual(tree, want) {
+				t.Error("Parse() tree differs from the text format")
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		path []string
+		key  bool
+	}{
+		{[]string{"snmp", "community", "public"}, true},
+		{[]string{"snmp", "community", "public", "authorization", "read-only"}, true},
+		{[]string{"system", "services", "ssh", "root-login", "deny"}, true},
+		{[]string{"system", "login", "class", "noc", "permissions", "view"}, true},
+		{[]string{"system", "syslog", "host", "192.0.2.10", "any"}, true},
+		{[]string{"system", "syslog", "host", "192.0.2.10", "any", "notice"}, false},
+		{[]string{"system", "syslog", "file", "interactive-commands", "interactive-commands", "any"}, false},
+		{[]string{"system", "syslog", "console", "any", "error"}, false},
+		{[]string{"system", "services", "netconf", "ssh"}, false},
+		{[]string{"interfaces", "ge-0/0/0", "unit", "0", "family", "iso"}, false},
+		{[]string{"policy-options", "policy-statement", "EXPORT-LO", "term", "LO", "then", "accept"}, false},
+	}
+	for _, format := range Formats {
+		tree := parseConfig(t, format)
+		for _, tt := range tests {
+			node := tree.Lookup(tt.path...)
+			switch {
+			case node == nil:
+				t.Errorf("%s: %s not found", format, PathString(tt.path))
+			case node.Key != tt.key:
+				t.Errorf("%s: %s Key = %t, want %t", format, PathString(tt.path), node.Key, tt.key)
+			}
+		}
+	}
+}
+
+func TestParseInactive(t *testing.T) {
+	for _, format := range Formats {
+		tree := parseConfig(t, format)
+		neighbors := tree.Lookup("protocols", "bgp", "group", "CORE", "neighbor")
+		if neighbors == nil || len(neighbors.Children) != 2 {
+			t.Fatalf("%s: neighbors = %+v", format, neighbors)
+		}
+		if a, b := neighbors.Children[0], neighbors.Children[1]; a.Inactive || !b.Inactive {
+			t.Errorf("%s: %s inactive = %t, %s inactive = %t", format, a.Name, a.Inactive, b.Name, b.Inactive)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Package configtree parses Junos configurations into one hierarchical tree,
+// whatever format they were retrieved in, and compares them.
+//
+// The tree holds one node per word of the configuration, the way "display
+// set" spells it: "set interfaces ge-0/0/0 unit 0 description uplink" is the
+// path interfaces > ge-0/0/0 > unit > 0 > description > uplink. Nodes that are
+// the name of a list entry or the value of a statement ("ge-0/0/0", "0",
+// "uplink") are marked as keys, which is what the curly-brace, XML and JSON
+// formats need to group the words into statements again.
+//
+// Junos configurations are schema driven and the schema isn't available
+// offline, so keys are told apart from keywords with a few rules: values that
+// don't look like a keyword (addresses, interface names, numbers) are keys,
+// and so is the word following one of the statements in valueKeywords, such
+// as "read-only" in "authorization read-only". The syslog facilities of a
+// "facility level" statement, "any notice", are keys too: they name the
+// <contents> entries of a syslog destination, the level being a keyword.
+package configtree
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Node is a word of the configuration with the statements below it.
+//
+// Group is the configuration group a statement was inherited from, as shown
+// by "display inheritance". It is informative only, Diff ignores it.
+type Node struct {
+	Name     string  `json:"name"`
+	Key      bool    `json:"key,omitempty"`
+	Inactive bool    `json:"inactive,omitempty"`
+	Protect  bool    `json:"protect,omitempty"`
+	Group    string  `json:"group,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// New returns an empty configuration.
+func New() *Node {
+	return &Node{}
+}
+
+// valueKeywords lists the statements always followed by a value, or by the
+// name of a list entry, even when that looks like a keyword ("user admin").
+var valueKeywords = map[string]bool{
+	"address":             true,
+	"apply-groups":        true,
+	"apply-groups-except": true,
+	"area":                true,
+	"as-path":             true,
+	"authentication-key":  true,
+	"authorization":       true,
+	"bridge-domains":      true,
+	"class":               true,
+	"community":           true,
+	"contact":             true,
+	"description":         true,
+	"domain-name":         true,
+	"encapsulation":       true,
+	"encrypted-password":  true,
+	"export":              true,
+	"file":                true,
+	"from-zone":           true,
+	"group":               true,
+	"groups":              true,
+	"host":                true,
+	"host-name":           true,
+	"import":              true,
+	"interface":           true,
+	"interface-mode":      true,
+	"interface-range":     true,
+	"interfaces":          true,
+	"label-switched-path": true,
+	"link-mode":           true,
+	"local-address":       true,
+	"local-as":            true,
+	"location":            true,
+	"logical-systems":     true,
+	"members":             true,
+	"name-server":         true,
+	"neighbor":            true,
+	"next-hop":            true,
+	"path":                true,
+	"peer-as":             true,
+	"periodic":            true,
+	"permissions":         true,
+	"policy":              true,
+	"policy-statement":    true,
+	"port-mode":           true,
+	"prefix-list":         true,
+	"protocol":            true,
+	"root-login":          true,
+	"routing-instances":   true,
+	"secret":              true,
+	"security-zone":       true,
+	"server":              true,
+	"source-address":      true,
+	"term":                true,
+	"to-zone":             true,
+	"type":                true,
+	"user":                true,
+	"version":             true,
+	"vlan":                true,
+	"vlan-id":             true,
+	"vlans":               true,
+}
+
+// syslogFacilities and syslogLevels are the words of the "facility level"
+// statements of a syslog destination, "any notice".
+var (
+	syslogFacilities = map[string]bool{
+		"any": true, "authorization": true, "change-log": true, "conflict-log": true,
+		"daemon": true, "dfc": true, "external": true, "firewall": true, "ftp": true,
+		"interactive-commands": true, "kernel": true, "ntp": true, "pfe": true,
+		"security": true, "user": true,
+	}
+	syslogLevels = map[string]bool{
+		"alert": true, "any": true, "critical": true, "emergency": true, "error": true,
+		"info": true, "none": true, "notice": true, "warning": true,
+	}
+)
+
+// orderedKeywords lists the statements whose entries are evaluated in order,
+// reordering them is a change.
+var orderedKeywords = map[string]bool{
+	"apply-groups": true,
+	"export":       true,
+	"import":       true,
+	"policy":       true,
+	"term":         true,
+}
+
+var keywordPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// isKeyword reports whether word looks like a configuration keyword.
+func isKeyword(word string) bool {
+	return keywordPattern.MatchString(word)
+}
+
+// Child returns the child named name, or nil.
+func (n *Node) Child(name string) *Node {
+	for _, child := range n.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// Lookup returns the node at the end of path, or nil.
+func (n *Node) Lookup(path ...string) *Node {
+	node := n
+	for _, name := range path {
+		if node = node.Child(name); node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Add returns the child named name, appending it when it doesn't exist.
+func (n *Node) Add(name string, key bool) *Node {
+	if child := n.Child(name); child != nil {
+		return child
+	}
+	child := &Node{Name: name, Key: key}
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// Remove deletes the child named name and reports whether it existed.
+func (n *Node) Remove(name string) bool {
+	for i, child := range n.Children {
+		if child.Name == name {
+			n.Children = append(n.Children[:i], n.Children[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Insert adds the words of a statement below n, telling keys from keywords,
+// and returns the node of the last word. A statement of valueKeywords
+// following another one is a keyword, "vlan members" is a container and a
+// statement rather than a VLAN named members.
+func (n *Node) Insert(words ...string) *Node {
+	node := n
+	for i, word := range words {
+		key := !isKeyword(word) || node.takesValue() && !valueKeywords[word]
+		if syslogFacilities[word] && i+1 < len(words) && syslogLevels[words[i+1]] {
+			key = true
+		}
+		node = node.Add(word, key)
+	}
+	return node
+}
+
+// IsFacility reports whether n is the facility of a syslog destination, the
+// entry "any" of "any notice", whose level is a keyword below it.
+func (n *Node) IsFacility() bool {
+	return n.Key && syslogFacilities[n.Name]
+}
+
+// TakesValue reports whether the statement keyword is always followed by a
+// value or the name of a list entry.
+func TakesValue(keyword string) bool {
+	return valueKeywords[keyword]
+}
+
+// takesValue reports whether the word following n is a value.
+func (n *Node) takesValue() bool {
+	return !n.Key && valueKeywords[n.Name]
+}
+
+// IsLeaf reports whether n has no statements below it.
+func (n *Node) IsLeaf() bool {
+	return len(n.Children) == 0
+}
+
+// Walk calls fn for every node below n with its path, parents first. A node
+// is skipped with its children when fn returns false.
+func (n *Node) Walk(fn func(path []string, node *Node) bool) {
+	n.walk(nil, fn)
+}
+
+func (n *Node) walk(path []string, fn func(path []string, node *Node) bool) {
+	for _, child := range n.Children {
+		childPath := append(append([]string(nil), path...), child.Name)
+		if fn(childPath, child) {
+			child.walk(childPath, fn)
+		}
+	}
+}
+
+// Paths returns the path of every leaf below n, in "display set" order.
+func (n *Node) Paths() [][]string {
+	var paths [][]string
+	n.Walk(func(path []string, node *Node) bool {
+		if node.IsLeaf() {
+			paths = append(paths, path)
+		}
+		return true
+	})
+	return paths
+}
+
+// Copy returns a deep copy of n.
+func (n *Node) Copy() *Node {
+	c := *n
+	c.Children = make([]*Node, len(n.Children))
+	for i, child := range n.Children {
+		c.Children[i] = child.Copy()
+	}
+	return &c
+}
+
+// Quote returns word quoted when the configuration syntax requires it.
+func Quote(word string) string {
+	if word != "" && !strings.ContainsAny(word, " \t\n\"{}[];#$\\") {
+		return word
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+}
+
+// PathString returns the path as it is written in a set command.
+func PathString(path []string) string {
+	quoted := make([]string, len(path))
+	for i, word := range path {
+		quoted[i] = Quote(word)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,124 @@
+// Package convert renders Junos configurations, retrieved in any of the
+// curly-brace text, "display set", XML or JSON formats, in any of the others
+// without going back to the device.
+//
+// Configurations are parsed with configtree, so a conversion keeps the
+// inactive: and protect: annotations and the configuration groups statements
+// were inherited from. The Junos schema isn't available offline: the element
+// names XML and JSON need for statements the text format doesn't spell out
+// are only known for the usual ones (interfaces, vlans, prefix lists...).
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+// Formats lists the configuration formats Convert reads and writes.
+var Formats = configtree.Formats
+
+// listKeywords lists the statements whose values are the names of list
+// entries, written one per line in the text format and as <name> elements in
+// XML, rather than as a leaf holding one or more values.
+var listKeywords = map[string]bool{
+	"address":             true,
+	"area":                true,
+	"file":                true,
+	"group":               true,
+	"host":                true,
+	"interface":           true,
+	"interface-range":     true,
+	"label-switched-path": true,
+	"name-server":         true,
+	"neighbor":            true,
+	"policy":              true,
+	"policy-statement":    true,
+	"prefix-list":         true,
+	"route":               true,
+	"security-zone":       true,
+	"server":              true,
+	"term":                true,
+	"unit":                true,
+	"user":                true,
+}
+
+// oneLiners lists the statements written on the line of their only
+// statement, "then accept;", and as a block otherwise.
+var oneLiners = map[string]bool{
+	"from": true,
+	"then": true,
+	"to":   true,
+}
+
+// leafItems lists the statements whose implicit items are written as leaves
+// in XML and JSON, <path>regex</path>, instead of list entries.
+var leafItems = map[string]bool{
+	"as-path": true,
+}
+
+// Convert converts a configuration from one format to another. An empty from
+// format is detected from the content.
+func Convert(data []byte, from, to string) ([]byte, error) {
+	root, err := configtree.Parse(data, from)
+	if err != nil {
+		return nil, err
+	}
+	return Render(root, to)
+}
+
+// Render renders a parsed configuration in the given format.
+func Render(root *configtree.Node, format string) ([]byte, error) {
+	switch format {
+	case "text":
+		return Text(root), nil
+	case "set":
+		return Set(root), nil
+	case "xml":
+		return XML(root), nil
+	case "json":
+		return JSON(root), nil
+	}
+	return nil, fmt.Errorf("unsupported configuration format %q", format)
+}
+
+// Set renders the configuration as "display set" does, the deactivate and
+// protect commands following the set commands.
+func Set(root *configtree.Node) []byte {
+	if root.IsLeaf() {
+		return nil
+	}
+	return []byte(strings.Join(configtree.StatementCommands(nil, root), "\n") + "\n")
+}
+
+// container returns the element name of the values below node written without
+// a keyword in the text format, or "". above is the name of the node above node.
+func container(node *configtree.Node, above string) string {
+	if node.Key {
+		return configtree.ImplicitItem(above)
+	}
+	return configtree.ImplicitList(node.Name)
+}
+
+// split separates the keys below node from the keywords.
+func split(node *configtree.Node) (keys, keywords []*configtree.Node) {
+	for _, child := range node.Children {
+		if child.Key {
+			keys = append(keys, child)
+		} else {
+			keywords = append(keywords, child)
+		}
+	}
+	return keys, keywords
+}
+
+// leaves reports whether the nodes are values without anything below them.
+func leaves(nodes []*configtree.Node) bool {
+	for _, n := range nodes {
+		if !n.IsLeaf() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package convert
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// configFiles maps the formats to the captured configuration of edge-1, shown
+// with "show configuration" and its display set, xml and json pipes.
+var configFiles = map[string]string{
+	"text": "../testdata/junos/config.txt",
+	"set":  "../testdata/junos/config.set",
+	"xml":  "../testdata/junos/config.xml",
+	"json": "../testdata/junos/config.json",
+}
+
+func readConfig(t *testing.T, format string) string {
+	t.Helper()
+	data, err := os.ReadFile(configFiles[format])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func convert(t *testing.T, data, from, to string) string {
+	t.Helper()
+	out, err := Convert([]byte(data), from, to)
+	if err != nil {
+		t.Fatalf("Convert(%s, %s): %s", from, to, err)
+	}
+	return string(out)
+}
+
+func TestConvert(t *testing.T) {
+	for _, from := range []string{"text", "set", "xml", "json"} {
+		for _, to := range []string{"text", "set", "xml", "json"} {
+			if got, want := convert(t, readConfig(t, from), from, to), readConfig(t, to); got != want {
+				t.Errorf("Convert(%s, %s) =\n%s\nwant\n%s", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	text := readConfig(t, "text")
+	for _, via := range []string{"set", "xml", "json"} {
+		if got := convert(t, convert(t, text, "text", via), via, "text"); got != text {
+			t.Errorf("text -> %s -> text =\n%s\nwant\n%s", via, got, text)
+		}
+	}
+}
+
+// TestKeywordValues checks the statements whose value looks like a keyword
+// are written as values, not as containers.
+func TestKeywordValues(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"text", []string{
+			"authorization read-only;",
+			"root-login deny;",
+			"permissions [ view view-configuration ];",
+			"any notice;",
+			"interactive-commands any;",
+			"then accept;",
+			"family iso;",
+		}},
+		{"xml", []string{
+			"<authorization>read-only</authorization>",
+			"<root-login>deny</root-login>",
+			"<permissions>view-configuration</permissions>",
+			"<contents>\n                    <name>any</name>\n                    <notice/>\n                </contents>",
+			"<contents>\n                    <name>interactive-commands</name>\n                    <any/>\n                </contents>",
+		}},
+		{"json", []string{
+			`"authorization" : "read-only"`,
+			`"root-login" : "deny"`,
+			`"permissions" : ["view", "view-configuration"]`,
+			"\"contents\" : [{\n                        \"name\" : \"any\",\n                        \"notice\" : [null]",
+		}},
+	}
+	text := readConfig(t, "text")
+	for _, tt := range tests {
+		got := convert(t, text, "text", tt.format)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("Convert(text, %s) lacks %q", tt.format, want)
+			}
+		}
+	}
+}
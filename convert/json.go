@@ -0,0 +1,182 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+// member is a member of a JSON object, objects keep their members in order.
+type member struct {
+	key   string
+	value interface{}
+}
+
+type object []member
+
+// JSON renders the configuration as "display json" does. Statements without
+// value are [null], attributes are in the "@" member of an object or the
+// "@<name>" member next to a leaf.
+func JSON(root *configtree.Node) []byte {
+	var b bytes.Buffer
+	writeJSON(&b, object{{"configuration", jsonObject(root, "")}}, 0)
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+// jsonObject returns the statements below node, a list entry, an implicit
+// list or the root. above is the name of the node above node.
+func jsonObject(node *configtree.Node, above string) object {
+
+	var o object
+	var entries []interface{}
+	at := -1
+
+	element := container(node, above)
+	for _, child := range node.Children {
+		switch {
+		case !child.Key:
+			o = appendStatement(o, child)
+			continue
+		case element != "" && child.IsLeaf() && leafItems[above]:
+			entries = append(entries, child.Name)
+		case element != "":
+			entries = append(entries, jsonEntry(child, node.Name))
+		default:
+			// A value below a list entry whose element name is unknown, written
+			// as another name of the entry.
+			o = append(o, member{"name", child.Name})
+			o = append(o, jsonObject(child, node.Name)...)
+			continue
+		}
+		if at < 0 {
+			at = len(o)
+		}
+	}
+
+	if at >= 0 {
+		var value interface{} = entries
+		if len(entries) == 1 && leafItems[above] {
+			value = entries[0]
+		}
+		o = append(o[:at], append(object{{element, value}}, o[at:]...)...)
+	}
+	return o
+}
+
+// appendStatement appends the statement kw with its values or entries to o.
+func appendStatement(o object, kw *configtree.Node) object {
+
+	if kw.IsLeaf() {
+		return appendLeaf(o, kw.Name, []interface{}{nil}, kw)
+	}
+	if configtree.ImplicitList(kw.Name) != "" {
+		return append(o, member{kw.Name, withAttrs(kw, jsonObject(kw, ""))})
+	}
+
+	keys, keywords := split(kw)
+	switch {
+	case len(keys) == 0:
+	case leaves(keys) && !listKeywords[kw.Name] && len(keys) == 1:
+		o = appendLeaf(o, kw.Name, keys[0].Name, keys[0])
+	case leaves(keys) && !listKeywords[kw.Name]:
+		values := make([]interface{}, len(keys))
+		for i, key := range keys {
+			values[i] = key.Name
+		}
+		o = append(o, member{kw.Name, values})
+	default:
+		entries := make([]interface{}, len(keys))
+		for i, key := range keys {
+			entries[i] = jsonEntry(key, kw.Name)
+		}
+		o = append(o, member{kw.Name, entries})
+	}
+
+	if len(keywords) > 0 {
+		var body object
+		for _, child := range keywords {
+			body = appendStatement(body, child)
+		}
+		o = append(o, member{kw.Name, withAttrs(kw, body)})
+	}
+	return o
+}
+
+// jsonEntry returns the list entry named by key. above is the name of the
+// node above key.
+func jsonEntry(key *configtree.Node, above string) object {
+	return withAttrs(key, append(object{{"name", key.Name}}, jsonObject(key, above)...))
+}
+
+// appendLeaf appends a leaf and the "@<name>" member holding the attributes
+// of node.
+func appendLeaf(o object, name string, value interface{}, node *configtree.Node) object {
+	o = append(o, member{name, value})
+	if attrs := jsonAttrs(node); attrs != nil {
+		o = append(o, member{"@" + name, attrs})
+	}
+	return o
+}
+
+// withAttrs returns o with the "@" member holding the attributes of node.
+func withAttrs(node *configtree.Node, o object) object {
+	if attrs := jsonAttrs(node); attrs != nil {
+		return append(object{{"@", attrs}}, o...)
+	}
+	return o
+}
+
+func jsonAttrs(node *configtree.Node) object {
+	var attrs object
+	if node.Inactive {
+		attrs = append(attrs, member{"inactive", true})
+	}
+	if node.Protect {
+		attrs = append(attrs, member{"protect", true})
+	}
+	if node.Group != "" {
+		attrs = append(attrs, member{"junos:group", node.Group})
+	}
+	return attrs
+}
+
+// writeJSON writes v indented, keeping the members of objects in order.
+func writeJSON(b *bytes.Buffer, v interface{}, depth int) {
+
+	indent := strings.Repeat("    ", depth)
+	switch v := v.(type) {
+	case object:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{\n")
+		for i, m := range v {
+			b.WriteString(indent + "    ")
+			writeJSON(b, m.key, depth+1)
+			b.WriteString(" : ")
+			writeJSON(b, m.value, depth+1)
+			if i < len(v)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "}")
+	case []interface{}:
+		b.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeJSON(b, item, depth)
+		}
+		b.WriteString("]")
+	default:
+		// Strings, booleans and null can't fail to encode.
+		encoded, _ := json.Marshal(v)
+		b.Write(encoded)
+	}
+}
@@ -0,0 +1,128 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+// Text renders the configuration in the curly-brace format of "show
+// configuration". Statements inherited from a group are preceded by the
+// comment "display inheritance" writes.
+func Text(root *configtree.Node) []byte {
+	var b bytes.Buffer
+	for _, child := range root.Children {
+		writeText(&b, []*configtree.Node{child}, "", 0)
+	}
+	return b.Bytes()
+}
+
+// writeText writes the statement made of words and the ones below it. above
+// is the name of the node above the first word.
+func writeText(b *bytes.Buffer, words []*configtree.Node, above string, depth int) {
+
+	node := words[len(words)-1]
+	up := above
+	if len(words) > 1 {
+		up = words[len(words)-2].Name
+	}
+
+	var inline, block []*configtree.Node
+	for _, child := range node.Children {
+		if sameLine(node, up, child) {
+			inline = append(inline, child)
+		} else {
+			block = append(block, child)
+		}
+	}
+
+	switch {
+	case node.IsLeaf():
+		writeLine(b, words, ";", depth)
+		return
+	case len(block) == 0 && leafList(node, inline):
+		values := make([]string, len(inline))
+		for i, child := range inline {
+			values[i] = child.Name
+		}
+		writeLine(b, words, " [ "+configtree.PathString(values)+" ];", depth)
+		return
+	}
+
+	if len(block) > 0 {
+		writeLine(b, words, " {", depth)
+		for _, child := range block {
+			writeText(b, []*configtree.Node{child}, node.Name, depth+1)
+		}
+		b.WriteString(strings.Repeat("    ", depth) + "}\n")
+	}
+	for _, child := range inline {
+		writeText(b, append(words[:len(words):len(words)], child), above, depth)
+	}
+}
+
+// sameLine reports whether child continues the statement of node on the same
+// line, as values do ("host-name r1", "unit 0"), and so do the level of a
+// syslog facility ("any notice"), the families ("family inet") and the single
+// condition or action of a term ("then accept").
+func sameLine(node *configtree.Node, above string, child *configtree.Node) bool {
+	switch {
+	case child.Key && node.Key:
+		// A value below a list entry: "as-path NAME regex", but the prefixes
+		// of a prefix list are a block.
+		return len(node.Children) == 1 && child.IsLeaf() && (container(node, above) == "" || leafItems[above])
+	case child.Key:
+		return container(node, above) == ""
+	case node.IsFacility():
+		return child.IsLeaf()
+	case node.Key:
+		return child.Name == "to-zone"
+	case node.Name == "family":
+		return true
+	case oneLiners[node.Name]:
+		return len(node.Children) == 1
+	}
+	return false
+}
+
+// leafList reports whether the values below node are written as a list,
+// "members [ v10 v20 ];".
+func leafList(node *configtree.Node, values []*configtree.Node) bool {
+	if len(values) < 2 || node.Key || listKeywords[node.Name] || !leaves(values) {
+		return false
+	}
+	for _, v := range values {
+		if !v.Key || v.Inactive || v.Protect || v.Group != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func writeLine(b *bytes.Buffer, words []*configtree.Node, end string, depth int) {
+
+	indent := strings.Repeat("    ", depth)
+	last := words[len(words)-1]
+	if last.Group != "" {
+		fmt.Fprintf(b, "%s##\n%s## '%s' was inherited from group '%s'\n%s##\n", indent, indent, last.Name, last.Group, indent)
+	}
+
+	var inactive, protect bool
+	names := make([]string, len(words))
+	for i, w := range words {
+		names[i] = w.Name
+		inactive = inactive || w.Inactive
+		protect = protect || w.Protect
+	}
+
+	b.WriteString(indent)
+	if protect {
+		b.WriteString("protect: ")
+	}
+	if inactive {
+		b.WriteString("inactive: ")
+	}
+	b.WriteString(configtree.PathString(names) + end + "\n")
+}
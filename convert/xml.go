@@ -0,0 +1,107 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi/configtree"
+)
+
+// XML renders the configuration as "display xml" does, without the
+// <rpc-reply> around it.
+func XML(root *configtree.Node) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<configuration xmlns:junos="http://xml.juniper.net/junos/*/junos">` + "\n")
+	writeXMLChildren(&b, root, "", 1)
+	b.WriteString("</configuration>\n")
+	return b.Bytes()
+}
+
+// writeXMLChildren writes the statements below node, a list entry, an
+// implicit list or the root. above is the name of the node above node.
+func writeXMLChildren(b *bytes.Buffer, node *configtree.Node, above string, depth int) {
+
+	element := container(node, above)
+	for _, child := range node.Children {
+		switch {
+		case !child.Key:
+			writeXMLStatement(b, child, depth)
+		case element != "" && child.IsLeaf() && leafItems[above]:
+			writeXMLLeaf(b, element, child, child.Name, depth)
+		case element != "":
+			writeXMLEntry(b, element, node.Name, child, depth)
+		default:
+			// A value below a list entry whose element name is unknown, written
+			// as another name of the entry.
+			writeXMLLeaf(b, "name", child, child.Name, depth)
+			writeXMLChildren(b, child, node.Name, depth)
+		}
+	}
+}
+
+// writeXMLStatement writes the statement kw with its values or entries.
+func writeXMLStatement(b *bytes.Buffer, kw *configtree.Node, depth int) {
+
+	indent := strings.Repeat("    ", depth)
+	if kw.IsLeaf() {
+		fmt.Fprintf(b, "%s<%s%s/>\n", indent, kw.Name, xmlAttrs(kw))
+		return
+	}
+	if configtree.ImplicitList(kw.Name) != "" {
+		fmt.Fprintf(b, "%s<%s%s>\n", indent, kw.Name, xmlAttrs(kw))
+		writeXMLChildren(b, kw, "", depth+1)
+		fmt.Fprintf(b, "%s</%s>\n", indent, kw.Name)
+		return
+	}
+
+	keys, keywords := split(kw)
+	for _, key := range keys {
+		if key.IsLeaf() && !listKeywords[kw.Name] {
+			writeXMLLeaf(b, kw.Name, key, key.Name, depth)
+		} else {
+			writeXMLEntry(b, kw.Name, kw.Name, key, depth)
+		}
+	}
+	if len(keywords) > 0 {
+		fmt.Fprintf(b, "%s<%s%s>\n", indent, kw.Name, xmlAttrs(kw))
+		for _, child := range keywords {
+			writeXMLStatement(b, child, depth+1)
+		}
+		fmt.Fprintf(b, "%s</%s>\n", indent, kw.Name)
+	}
+}
+
+// writeXMLEntry writes the list entry named by key. above is the name of the
+// node above key.
+func writeXMLEntry(b *bytes.Buffer, element, above string, key *configtree.Node, depth int) {
+	indent := strings.Repeat("    ", depth)
+	fmt.Fprintf(b, "%s<%s%s>\n", indent, element, xmlAttrs(key))
+	writeXMLLeaf(b, "name", &configtree.Node{}, key.Name, depth+1)
+	writeXMLChildren(b, key, above, depth+1)
+	fmt.Fprintf(b, "%s</%s>\n", indent, element)
+}
+
+func writeXMLLeaf(b *bytes.Buffer, element string, node *configtree.Node, text string, depth int) {
+	fmt.Fprintf(b, "%s<%s%s>", strings.Repeat("    ", depth), element, xmlAttrs(node))
+	xml.EscapeText(b, []byte(text))
+	fmt.Fprintf(b, "</%s>\n", element)
+}
+
+// xmlAttrs returns the inactive, protect and junos:group attributes of node.
+func xmlAttrs(node *configtree.Node) string {
+	var attrs strings.Builder
+	if node.Inactive {
+		attrs.WriteString(` inactive="inactive"`)
+	}
+	if node.Protect {
+		attrs.WriteString(` protect="protect"`)
+	}
+	if node.Group != "" {
+		attrs.WriteString(` junos:group="`)
+		xml.EscapeText(&attrs, []byte(node.Group))
+		attrs.WriteString(`"`)
+	}
+	return attrs.String()
+}
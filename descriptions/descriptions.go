@@ -0,0 +1,305 @@
+// Package descriptions checks interface descriptions against the LLDP
+// neighbors actually seen on the interfaces.
+//
+// Descriptions are expected to name the neighbor, "to-core-1-ge-0/0/1" by
+// default. The pattern extracts the host and port from a description, the
+// template writes the description of a neighbor, both are configurable.
+package descriptions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kgrvamsi/networkapi"
+	"github.com/kgrvamsi/networkapi/configtree"
+	"github.com/kgrvamsi/networkapi/topology"
+)
+
+// Defaults of Options.
+const (
+	DefaultPattern    = `^to-(?P<host>.+)-(?P<port>[a-z]+-?[0-9]+(/[0-9]+)*(:[0-9]+)?)$`
+	DefaultTemplate   = "to-{host}-{port}"
+	DefaultInterfaces = `^(ge|xe|et|mge|fe)-`
+)
+
+// Options holds the description convention.
+type Options struct {
+	// Pattern extracts the neighbor from a description, with the named
+	// groups host and port.
+	Pattern string
+	// Template is the description of a neighbor, {host} and {port} are
+	// replaced with the neighbor hostname, without domain, and port.
+	Template string
+	// Interfaces selects the interfaces checked by name, the physical ones
+	// by default.
+	Interfaces string
+}
+
+// Kinds of findings.
+const (
+	// Mismatch is a description naming another neighbor than LLDP sees.
+	Mismatch = "mismatch"
+	// Missing is an up interface without description.
+	Missing = "missing"
+	// Nonconforming is a description not following the pattern on an
+	// interface with a neighbor.
+	Nonconforming = "nonconforming"
+	// NoNeighbor is a description naming a neighbor on an interface where
+	// LLDP sees none.
+	NoNeighbor = "no-neighbor"
+	// Error is a device that couldn't be checked.
+	Error = "error"
+)
+
+// Finding is an interface whose description doesn't match the cabling.
+// Command is the set command correcting it, when the interface has a single
+// neighbor.
+type Finding struct {
+	Host        string `json:"host"`
+	Interface   string `json:"interface,omitempty"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Neighbor    string `json:"neighbor,omitempty"`
+	Message     string `json:"message"`
+	Command     string `json:"command,omitempty"`
+}
+
+// Interface is an interface of a device.
+type Interface struct {
+	Name        string
+	OperStatus  string
+	Description string
+}
+
+// remote is a neighbor seen on a port, by short hostname and physical port.
+type remote struct {
+	host, port string
+}
+
+func contains(remotes []remote, r remote) bool {
+	for _, o := range remotes {
+		if o == r {
+			return true
+		}
+	}
+	return false
+}
+
+type auditor struct {
+	pattern    *regexp.Regexp
+	template   string
+	interfaces *regexp.Regexp
+}
+
+func newAuditor(opts Options) (*auditor, error) {
+
+	if opts.Pattern == "" {
+		opts.Pattern = DefaultPattern
+	}
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+	if opts.Interfaces == "" {
+		opts.Interfaces = DefaultInterfaces
+	}
+
+	a := &auditor{template: opts.Template}
+	var err error
+	if a.pattern, err = regexp.Compile(opts.Pattern); err != nil {
+		return nil, fmt.Errorf("invalid description pattern: %s", err)
+	}
+	if a.pattern.SubexpIndex("host") < 0 || a.pattern.SubexpIndex("port") < 0 {
+		return nil, fmt.Errorf("description pattern has no host or port group")
+	}
+	if a.interfaces, err = regexp.Compile(opts.Interfaces); err != nil {
+		return nil, fmt.Errorf("invalid interfaces pattern: %s", err)
+	}
+	return a, nil
+}
+
+// Parse returns the host and port named by a description following the
+// pattern of opts.
+func Parse(description string, opts Options) (host, port string, ok bool, err error) {
+	a, err := newAuditor(opts)
+	if err != nil {
+		return "", "", false, err
+	}
+	host, port, ok = a.parse(description)
+	return host, port, ok, nil
+}
+
+func (a *auditor) parse(description string) (string, string, bool) {
+	m := a.pattern.FindStringSubmatch(strings.TrimSpace(description))
+	if m == nil {
+		return "", "", false
+	}
+	return m[a.pattern.SubexpIndex("host")], m[a.pattern.SubexpIndex("port")], true
+}
+
+func (a *auditor) describe(host, port string) string {
+	return strings.NewReplacer("{host}", host, "{port}", port).Replace(a.template)
+}
+
+// Check returns the findings on the interfaces of a device.
+func Check(host string, interfaces []Interface, neighbors []networkapi.LLDPNeighbor, opts Options) ([]Finding, error) {
+
+	a, err := newAuditor(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A port can see several neighbors, through a hub or a media converter.
+	// Neighbors without a system name can't be described and are skipped.
+	seen := make(map[string][]remote)
+	for _, n := range neighbors {
+		r := remote{networkapi.ShortHostname(n.RemoteSystemName), topology.Port(n.RemotePort())}
+		if r.host == "" {
+			continue
+		}
+		local := topology.Port(n.LocalPort())
+		if !contains(seen[local], r) {
+			seen[local] = append(seen[local], r)
+		}
+	}
+
+	var findings []Finding
+	for _, intf := range interfaces {
+		name := strings.TrimSpace(intf.Name)
+		if !a.interfaces.MatchString(name) {
+			continue
+		}
+		description := strings.TrimSpace(intf.Description)
+		f := Finding{Host: host, Interface: name, Description: description}
+
+		remotes := seen[name]
+		hasNeighbor := len(remotes) > 0
+		if hasNeighbor {
+			names := make([]string, len(remotes))
+			for i, r := range remotes {
+				names[i] = r.host + " " + r.port
+			}
+			f.Neighbor = strings.Join(names, ", ")
+		}
+		if len(remotes) == 1 {
+			f.Command = "set " + configtree.PathString([]string{"interfaces", name, "description", a.describe(remotes[0].host, remotes[0].port)})
+		}
+
+		descHost, descPort, parsed := a.parse(description)
+		switch {
+		case description == "" && strings.EqualFold(strings.TrimSpace(intf.OperStatus), "up"):
+			f.Type, f.Message = Missing, "up interface without description"
+		case description == "":
+			continue
+		case !parsed && hasNeighbor:
+			f.Type, f.Message = Nonconforming, fmt.Sprintf("description doesn't follow the convention, LLDP sees %s", f.Neighbor)
+		case !parsed:
+			continue
+		case !hasNeighbor:
+			f.Type, f.Message = NoNeighbor, fmt.Sprintf("description names %s %s, LLDP sees no neighbor", descHost, descPort)
+		case !contains(remotes, remote{networkapi.ShortHostname(descHost), topology.Port(descPort)}):
+			f.Type, f.Message = Mismatch, fmt.Sprintf("description names %s %s, LLDP sees %s", descHost, descPort, f.Neighbor)
+		default:
+			continue
+		}
+		findings = append(findings, f)
+	}
+
+	return findings, nil
+}
+
+// Audit checks the interfaces of the devices. Findings are sorted by host and
+// interface.
+func Audit(inventory *networkapi.Inventory, devices []networkapi.Device, opts Options, parallel int) ([]Finding, error) {
+
+	if _, err := newAuditor(opts); err != nil {
+		return nil, err
+	}
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		findings []Finding
+	)
+
+	sem := make(chan struct{}, parallel)
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d networkapi.Device) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			interfaces, neighbors, err := collect(inventory.Client(d))
+			var found []Finding
+			if err == nil {
+				found, err = Check(d.Hostname, interfaces, neighbors, opts)
+			}
+			if err != nil {
+				found = []Finding{{Host: d.Hostname, Type: Error, Message: err.Error()}}
+			}
+
+			mu.Lock()
+			findings = append(findings, found...)
+			mu.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Host != findings[j].Host {
+			return findings[i].Host < findings[j].Host
+		}
+		return findings[i].Interface < findings[j].Interface
+	})
+	return findings, nil
+}
+
+// collect reads the physical interfaces, with the ones without description,
+// and the LLDP neighbors of the device.
+func collect(c *networkapi.Client) ([]Interface, []networkapi.LLDPNeighbor, error) {
+
+	defer c.DisconnectSSH()
+
+	session, err := c.ConnectSSH()
+	if err != nil {
+		return nil, nil, err
+	}
+	stats, err := c.GetInterfacesStatisticsSSH(session)
+	c.CloseSSH(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err = c.ConnectSSH()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.CloseSSH(session)
+	neighbors, err := c.GetLLDPNeighborsInfoSSH(session)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	interfaces := make([]Interface, len(stats.PhysicalInterface))
+	for i, intf := range stats.PhysicalInterface {
+		interfaces[i] = Interface{Name: intf.Name, OperStatus: intf.Operstatus, Description: intf.Description}
+	}
+	return interfaces, neighbors, nil
+}
+
+// Commands returns the set commands correcting the findings.
+func Commands(findings []Finding) []string {
+	var commands []string
+	for _, f := range findings {
+		if f.Command != "" {
+			commands = append(commands, f.Command)
+		}
+	}
+	return commands
+}
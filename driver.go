@@ -0,0 +1,237 @@
+package networkapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Platforms of the drivers.
+const (
+	PlatformJunos = "junos"
+	PlatformIOSXR = "iosxr"
+	PlatformNXOS  = "nxos"
+	PlatformEOS   = "eos"
+)
+
+// ErrNotSupported is returned by the drivers for the operations a platform has no command for.
+var ErrNotSupported = errors.New("not supported by the platform")
+
+//Runner ... Runs a CLI command on the device and returns its output
+//
+// Client.RunSSH and TelnetSession.Run are runners.
+type Runner func(command string) (string, error)
+
+//Driver ... Maps the logical operations to the commands and output parsers of a platform
+//
+// All drivers return the same normalized types, whatever the platform.
+type Driver interface {
+	Platform() string
+	GetConfig(run Runner) (string, error)
+	GetInterfaces(run Runner) ([]Interface, error)
+	GetBGPSummary(run Runner) ([]BGPPeer, error)
+	GetLLDPNeighbors(run Runner) ([]LLDPNeighbor, error)
+	GetUptime(run Runner) (time.Duration, error)
+	GetOptics(run Runner) ([]Optics, error)
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = map[string]Driver{
+		PlatformJunos: junosDriver{},
+		PlatformIOSXR: iosxrDriver{},
+		PlatformNXOS:  nxosDriver{},
+		PlatformEOS:   eosDriver{},
+	}
+)
+
+//RegisterDriver ... Adds the driver of a platform, or replaces the one shipped with the library
+func RegisterDriver(driver Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	drivers[driver.Platform()] = driver
+}
+
+//GetDriver ... Returns the driver of a platform, Junos when the platform is empty
+func GetDriver(platform string) (Driver, error) {
+	if platform == "" {
+		platform = PlatformJunos
+	}
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	driver, ok := drivers[strings.ToLower(platform)]
+	if !ok {
+		return nil, fmt.Errorf("no driver for platform %q", platform)
+	}
+	return driver, nil
+}
+
+//Driver ... Returns the driver of the platform of the device, or of the detected platform when none is set
+func (c *Client) Driver() (Driver, error) {
+	platform := c.Platform
+	if facts := c.Facts(); platform == "" && facts != nil {
+		platform = facts.Platform
+	}
+	return GetDriver(platform)
+}
+
+//RunSSH ... Runs a command in a new SSH session and returns its output
+func (c *Client) RunSSH(command string) (string, error) {
+	return c.RunSSHContext(context.Background(), command)
+}
+
+//RunSSHContext ... Runs a command like RunSSH, closing its session to abort the command when the context is done
+func (c *Client) RunSSHContext(ctx context.Context, command string) (string, error) {
+
+	session, err := c.ConnectSSH()
+	if err != nil {
+		return "", err
+	}
+	defer c.CloseSSH(session)
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.Close()
+		case <-finished:
+		}
+	}()
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	session.Stdout = &stdoutBuf
+	session.Stderr = &stderrBuf
+	err = session.Run(command)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil && stdoutBuf.Len() == 0 {
+		if message := strings.TrimSpace(stderrBuf.String()); message != "" {
+			return "", fmt.Errorf("%s: %s", command, message)
+		}
+		return "", fmt.Errorf("%s: %s", command, err)
+	}
+	return stdoutBuf.String(), nil
+}
+
+var speedPattern = regexp.MustCompile(`(?i)^([0-9.]+)\s*([kmgt]?)(bps|b/s|bit/s)?$`)
+
+// parseSpeed returns the bits per second of a speed such as 10Gbps, 1000mbps
+// or "10 Gb/s", 0 when it isn't a speed, e.g. Auto or Unlimited.
+func parseSpeed(speed string) uint64 {
+	m := speedPattern.FindStringSubmatch(strings.TrimSpace(speed))
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(m[2]) {
+	case "k":
+		value *= 1e3
+	case "m":
+		value *= 1e6
+	case "g":
+		value *= 1e9
+	case "t":
+		value *= 1e12
+	}
+	return uint64(value)
+}
+
+// NoLight is the power in dBm reported for optics receiving or sending no light.
+const NoLight = -40
+
+// parsePower returns the power in dBm of a reading, NoLight for "- Inf" and
+// other readings that aren't numbers.
+func parsePower(power string) float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(power), "dBm")), 64)
+	if err != nil || value < NoLight {
+		return NoLight
+	}
+	return value
+}
+
+func parseFloat(s string) float64 {
+	value, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return value
+}
+
+func parseInt(s string) int {
+	value, _ := strconv.Atoi(strings.TrimSpace(s))
+	return value
+}
+
+var (
+	durationPattern = regexp.MustCompile(`(?i)([0-9]+)\s*(year|week|day|hour|minute|second|[ywdhms]\b)`)
+	clockPattern    = regexp.MustCompile(`([0-9]+):([0-9]{2})(:([0-9]{2}))?`)
+)
+
+// junosDuration returns the duration of a Junos time, from its seconds
+// attribute when there is one.
+func junosDuration(t JunosTime) time.Duration {
+	if t.Seconds != "" {
+		return time.Duration(parseInt(t.Seconds)) * time.Second
+	}
+	return parseUptime(t.Value)
+}
+
+// parseUptime returns the duration of an uptime such as "1 week, 2 days, 3
+// hours, 4 minutes" or "1w2d 03:04:05".
+func parseUptime(uptime string) time.Duration {
+
+	var d time.Duration
+	if m := clockPattern.FindStringSubmatch(uptime); m != nil {
+		d += time.Duration(parseInt(m[1]))*time.Hour + time.Duration(parseInt(m[2]))*time.Minute + time.Duration(parseInt(m[4]))*time.Second
+		uptime = strings.Replace(uptime, m[0], "", 1)
+	}
+	for _, m := range durationPattern.FindAllStringSubmatch(uptime, -1) {
+		n := time.Duration(parseInt(m[1]))
+		switch strings.ToLower(m[2][:1]) {
+		case "y":
+			d += n * 365 * 24 * time.Hour
+		case "w":
+			d += n * 7 * 24 * time.Hour
+		case "d":
+			d += n * 24 * time.Hour
+		case "h":
+			d += n * time.Hour
+		case "m":
+			d += n * time.Minute
+		case "s":
+			d += n * time.Second
+		}
+	}
+	return d
+}
+
+func sortInterfaces(interfaces []Interface) {
+	sort.SliceStable(interfaces, func(i, j int) bool { return interfaces[i].Name < interfaces[j].Name })
+}
+
+func sortPeers(peers []BGPPeer) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		if peers[i].Address != peers[j].Address {
+			return peers[i].Address < peers[j].Address
+		}
+		return peers[i].VRF < peers[j].VRF
+	})
+}
+
+func sortOptics(optics []Optics) {
+	sort.SliceStable(optics, func(i, j int) bool {
+		if optics[i].Interface != optics[j].Interface {
+			return optics[i].Interface < optics[j].Interface
+		}
+		return optics[i].Lane < optics[j].Lane
+	})
+}
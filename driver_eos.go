@@ -0,0 +1,185 @@
+package networkapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// eosDriver decodes the output of the commands piped to json.
+type eosDriver struct{}
+
+func (eosDriver) Platform() string {
+	return PlatformEOS
+}
+
+func (eosDriver) GetConfig(run Runner) (string, error) {
+	return run("show running-config")
+}
+
+// GetInterfaces maps the interface status, connected, notconnect, disabled or
+// errdisabled, to the admin status and the line protocol to the oper status.
+func (eosDriver) GetInterfaces(run Runner) ([]Interface, error) {
+	var reply struct {
+		Interfaces map[string]struct {
+			Name               string  `json:"name"`
+			InterfaceStatus    string  `json:"interfaceStatus"`
+			LineProtocolStatus string  `json:"lineProtocolStatus"`
+			Description        string  `json:"description"`
+			Bandwidth          float64 `json:"bandwidth"`
+		} `json:"interfaces"`
+	}
+	if err := eosJSON(run, "show interfaces", &reply); err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]Interface, 0, len(reply.Interfaces))
+	for name, intf := range reply.Interfaces {
+		i := Interface{
+			Name:        name,
+			AdminStatus: "up",
+			OperStatus:  "down",
+			Description: intf.Description,
+			Speed:       uint64(intf.Bandwidth),
+		}
+		if intf.InterfaceStatus == "disabled" {
+			i.AdminStatus = "down"
+		}
+		if intf.LineProtocolStatus == "up" {
+			i.OperStatus = "up"
+		}
+		interfaces = append(interfaces, i)
+	}
+	sortInterfaces(interfaces)
+	return interfaces, nil
+}
+
+// GetBGPSummary returns the IPv4 unicast peers of every VRF.
+func (eosDriver) GetBGPSummary(run Runner) ([]BGPPeer, error) {
+	var reply struct {
+		VRFs map[string]struct {
+			Peers map[string]struct {
+				PeerState      string      `json:"peerState"`
+				ASN            interface{} `json:"asn"`
+				PrefixReceived int         `json:"prefixReceived"`
+				PrefixAccepted int         `json:"prefixAccepted"`
+			} `json:"peers"`
+		} `json:"vrfs"`
+	}
+	if err := eosJSON(run, "show ip bgp summary vrf all", &reply); err != nil {
+		return nil, err
+	}
+
+	var peers []BGPPeer
+	for name, vrf := range reply.VRFs {
+		for address, peer := range vrf.Peers {
+			peers = append(peers, BGPPeer{
+				VRF:              name,
+				Address:          address,
+				AS:               strings.TrimSuffix(fmt.Sprint(peer.ASN), ".0"),
+				State:            peer.PeerState,
+				PrefixesReceived: peer.PrefixReceived,
+				PrefixesAccepted: peer.PrefixAccepted,
+			})
+		}
+	}
+	sortPeers(peers)
+	return peers, nil
+}
+
+func (eosDriver) GetLLDPNeighbors(run Runner) ([]LLDPNeighbor, error) {
+	var reply struct {
+		LLDPNeighbors map[string]struct {
+			LLDPNeighborInfo []struct {
+				ChassisIDType         string `json:"chassisIdType"`
+				ChassisID             string `json:"chassisId"`
+				SystemName            string `json:"systemName"`
+				NeighborInterfaceInfo struct {
+					InterfaceIDType      string `json:"interfaceIdType"`
+					InterfaceID          string `json:"interfaceId"`
+					InterfaceDescription string `json:"interfaceDescription"`
+				} `json:"neighborInterfaceInfo"`
+			} `json:"lldpNeighborInfo"`
+		} `json:"lldpNeighbors"`
+	}
+	if err := eosJSON(run, "show lldp neighbors detail", &reply); err != nil {
+		return nil, err
+	}
+
+	var neighbors []LLDPNeighbor
+	for local, info := range reply.LLDPNeighbors {
+		for _, n := range info.LLDPNeighborInfo {
+			neighbors = append(neighbors, LLDPNeighbor{
+				LocalInterface:         local,
+				RemoteChassisIDSubtype: n.ChassisIDType,
+				RemoteChassisID:        n.ChassisID,
+				RemotePortIDSubtype:    n.NeighborInterfaceInfo.InterfaceIDType,
+				// The interface ID is quoted, "\"Ethernet1\"".
+				RemotePortID:          strings.Trim(n.NeighborInterfaceInfo.InterfaceID, `"`),
+				RemotePortDescription: n.NeighborInterfaceInfo.InterfaceDescription,
+				RemoteSystemName:      n.SystemName,
+			})
+		}
+	}
+	sort.SliceStable(neighbors, func(i, j int) bool { return neighbors[i].LocalInterface < neighbors[j].LocalInterface })
+	return neighbors, nil
+}
+
+func (eosDriver) GetUptime(run Runner) (time.Duration, error) {
+	var reply struct {
+		Uptime float64 `json:"uptime"`
+	}
+	if err := eosJSON(run, "show version", &reply); err != nil {
+		return 0, err
+	}
+	return time.Duration(reply.Uptime * float64(time.Second)), nil
+}
+
+// GetOptics returns the transceivers reporting their power, EOS reports a
+// single value per interface.
+func (eosDriver) GetOptics(run Runner) ([]Optics, error) {
+	var reply struct {
+		Interfaces map[string]struct {
+			Temperature *float64 `json:"temperature"`
+			TxPower     *float64 `json:"txPower"`
+			RxPower     *float64 `json:"rxPower"`
+		} `json:"interfaces"`
+	}
+	if err := eosJSON(run, "show interfaces transceiver", &reply); err != nil {
+		return nil, err
+	}
+
+	var optics []Optics
+	for name, intf := range reply.Interfaces {
+		if intf.TxPower == nil && intf.RxPower == nil {
+			continue
+		}
+		o := Optics{Interface: name, TxPower: NoLight, RxPower: NoLight}
+		if intf.Temperature != nil {
+			o.Temperature = *intf.Temperature
+		}
+		if intf.TxPower != nil && *intf.TxPower > NoLight {
+			o.TxPower = *intf.TxPower
+		}
+		if intf.RxPower != nil && *intf.RxPower > NoLight {
+			o.RxPower = *intf.RxPower
+		}
+		optics = append(optics, o)
+	}
+	sortOptics(optics)
+	return optics, nil
+}
+
+// eosJSON runs a command piped to json and decodes its output into v.
+func eosJSON(run Runner, command string, v interface{}) error {
+	output, err := run(command + " | json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(output), v); err != nil {
+		return fmt.Errorf("%s: %s", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package networkapi
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// iosxrDriver parses the text output of IOS-XR, which has no JSON display.
+type iosxrDriver struct{}
+
+func (iosxrDriver) Platform() string {
+	return PlatformIOSXR
+}
+
+func (iosxrDriver) GetConfig(run Runner) (string, error) {
+	return run("show running-config")
+}
+
+// GetInterfaces reads the table of show interfaces description:
+//
+//	Interface          Status      Protocol    Description
+//	Gi0/0/0/0          up          up          to-core-1
+func (iosxrDriver) GetInterfaces(run Runner) ([]Interface, error) {
+	output, err := run("show interfaces description")
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []Interface
+	header := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if !header {
+			header = len(fields) >= 3 && fields[0] == "Interface" && fields[1] == "Status"
+			continue
+		}
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "---") {
+			continue
+		}
+		intf := Interface{
+			Name:        fields[0],
+			AdminStatus: "up",
+			OperStatus:  iosxrStatus(fields[2]),
+		}
+		if fields[1] == "admin-down" {
+			intf.AdminStatus = "down"
+		}
+		if len(fields) > 3 {
+			intf.Description = strings.Join(fields[3:], " ")
+		}
+		interfaces = append(interfaces, intf)
+	}
+	return interfaces, nil
+}
+
+func iosxrStatus(status string) string {
+	if status == "up" {
+		return "up"
+	}
+	return "down"
+}
+
+// GetBGPSummary reads the neighbor table of show bgp summary, the peers of the
+// default VRF. The last column
+// is the prefixes received by the established peers and the state of the
+// others:
+//
+//	Neighbor        Spk    AS MsgRcvd MsgSent   TblVer  InQ OutQ  Up/Down  St/PfxRcd
+//	10.0.0.2          0 65002     100     100       10    0    0 01:02:03          5
+func (iosxrDriver) GetBGPSummary(run Runner) ([]BGPPeer, error) {
+	output, err := run("show bgp summary")
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []BGPPeer
+	table, pending := false, ""
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if !table {
+			table = len(fields) > 0 && fields[0] == "Neighbor" && strings.Contains(scanner.Text(), "St/PfxRcd")
+			continue
+		}
+		// Long IPv6 addresses are alone on their line.
+		if len(fields) == 1 {
+			pending = fields[0]
+			continue
+		}
+		if pending != "" {
+			fields = append([]string{pending}, fields...)
+			pending = ""
+		}
+		if len(fields) < 10 {
+			continue
+		}
+		peer := BGPPeer{VRF: "default", Address: fields[0], AS: fields[2]}
+		last := fields[len(fields)-1]
+		if received, err := strconv.Atoi(last); err == nil {
+			peer.State = "Established"
+			peer.PrefixesReceived = received
+			peer.PrefixesAccepted = received
+		} else {
+			peer.State = last
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+// GetLLDPNeighbors reads the blocks of show lldp neighbors detail.
+func (iosxrDriver) GetLLDPNeighbors(run Runner) ([]LLDPNeighbor, error) {
+	output, err := run("show lldp neighbors detail")
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []LLDPNeighbor
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := iosxrField(scanner.Text())
+		if !ok {
+			continue
+		}
+		if key == "Local Interface" {
+			neighbors = append(neighbors, LLDPNeighbor{LocalInterface: value})
+			continue
+		}
+		if len(neighbors) == 0 {
+			continue
+		}
+		n := &neighbors[len(neighbors)-1]
+		switch key {
+		case "Chassis id":
+			n.RemoteChassisID = value
+		case "Port id":
+			n.RemotePortID = value
+		case "Port Description":
+			n.RemotePortDescription = value
+		case "System Name":
+			n.RemoteSystemName = value
+		}
+	}
+	return neighbors, nil
+}
+
+// iosxrField splits a "Key: value" line.
+func iosxrField(line string) (string, string, bool) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]), true
+}
+
+var iosxrUptime = regexp.MustCompile(`uptime is (.+)`)
+
+// GetUptime reads "System uptime is 2 weeks, 3 days, 5 hours, 10 minutes" in show version.
+func (iosxrDriver) GetUptime(run Runner) (time.Duration, error) {
+	output, err := run("show version")
+	if err != nil {
+		return 0, err
+	}
+	m := iosxrUptime.FindStringSubmatch(output)
+	if m == nil {
+		return 0, fmt.Errorf("no uptime in show version")
+	}
+	return parseUptime(m[1]), nil
+}
+
+// GetOptics isn't supported, show controllers optics takes an interface.
+func (iosxrDriver) GetOptics(run Runner) ([]Optics, error) {
+	return nil, fmt.Errorf("optics: %w", ErrNotSupported)
+}
@@ -0,0 +1,137 @@
+package networkapi
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+	"time"
+)
+
+// junosDriver runs the commands with their output as XML, decoded into the
+// same types as the SSH methods.
+type junosDriver struct{}
+
+func (junosDriver) Platform() string {
+	return PlatformJunos
+}
+
+func (junosDriver) GetConfig(run Runner) (string, error) {
+	return run("show configuration")
+}
+
+func (junosDriver) GetInterfaces(run Runner) ([]Interface, error) {
+	var reply InterfacesStatisticsSSH
+	if err := junosXML(run, "show interfaces", &reply); err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]Interface, 0, len(reply.PhysicalInterface))
+	for _, physical := range reply.PhysicalInterface {
+		interfaces = append(interfaces, Interface{
+			Name:        strings.TrimSpace(physical.Name),
+			AdminStatus: strings.ToLower(strings.TrimSpace(physical.Adminstatus)),
+			OperStatus:  strings.ToLower(strings.TrimSpace(physical.Operstatus)),
+			Description: strings.TrimSpace(physical.Description),
+			Speed:       parseSpeed(physical.Speed),
+		})
+	}
+	return interfaces, nil
+}
+
+func (junosDriver) GetBGPSummary(run Runner) ([]BGPPeer, error) {
+	var reply RPCReplyBgp
+	if err := junosXML(run, "show bgp summary", &reply); err != nil {
+		return nil, err
+	}
+
+	peers := make([]BGPPeer, 0, len(reply.Bgpinformation.Bgppeer))
+	for _, peer := range reply.Bgpinformation.Bgppeer {
+		p := BGPPeer{
+			VRF:     peer.Instance(),
+			Address: strings.TrimSpace(peer.Peeraddress),
+			AS:      strings.TrimSpace(peer.Peeras),
+			State:   strings.TrimSpace(peer.Peerstate),
+		}
+		for _, rib := range peer.Bgprib {
+			p.PrefixesReceived += parseInt(rib.Receivedprefixcount)
+			p.PrefixesAccepted += parseInt(rib.Acceptedprefixcount)
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+func (junosDriver) GetLLDPNeighbors(run Runner) ([]LLDPNeighbor, error) {
+	var reply LLDPNeighborsInfoSSH
+	err := junosXML(run, "show lldp neighbors", &reply)
+	return reply.Neighbors, err
+}
+
+// GetUptime returns the uptime of re0, or of the first routing engine.
+func (junosDriver) GetUptime(run Runner) (time.Duration, error) {
+	var reply SystemUptimeSSH
+	if err := junosXML(run, "show system uptime", &reply); err != nil {
+		return 0, err
+	}
+
+	res := uptimeByRE(reply)
+	names := make([]string, 0, len(res))
+	for name := range res {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return 0, nil
+	}
+	sort.Strings(names)
+
+	uptime := res[names[0]].SystemUptime
+	if uptime.Seconds != "" {
+		return time.Duration(parseInt(uptime.Seconds)) * time.Second, nil
+	}
+	return parseUptime(uptime.Value), nil
+}
+
+func (junosDriver) GetOptics(run Runner) ([]Optics, error) {
+	var reply InterfacesDiagnosticsSSH
+	if err := junosXML(run, "show interfaces diagnostics optics", &reply); err != nil {
+		return nil, err
+	}
+
+	var optics []Optics
+	for _, physical := range reply.InterfaceInformation.PhysicalInterface {
+		diagnostics := physical.OpticsDiagnostics
+		name := strings.TrimSpace(physical.Name)
+		temperature := parseFloat(diagnostics.ModuleTemperature.Celsius)
+		if len(diagnostics.OpticsDiagnosticsLaneValues) == 0 {
+			optics = append(optics, Optics{
+				Interface:   name,
+				Temperature: temperature,
+				TxPower:     parsePower(diagnostics.LaserOutputPowerDbm),
+				RxPower:     parsePower(diagnostics.RxSignalAvgOpticalPowerDbm),
+			})
+			continue
+		}
+		for _, lane := range diagnostics.OpticsDiagnosticsLaneValues {
+			optics = append(optics, Optics{
+				Interface:   name,
+				Lane:        parseInt(lane.LaneIndex),
+				Temperature: temperature,
+				TxPower:     parsePower(lane.LaserOutputPowerDbm),
+				RxPower:     parsePower(lane.LaserRxOpticalPowerDbm),
+			})
+		}
+	}
+	return optics, nil
+}
+
+// junosXML runs a command with its output as XML and decodes it into v.
+func junosXML(run Runner, command string, v interface{}) error {
+	output, err := run(displayCommand(command, "xml"))
+	if err != nil {
+		return err
+	}
+	if i := strings.Index(output, "<"); i > 0 {
+		output = output[i:]
+	}
+	return xml.Unmarshal([]byte(output), v)
+}
@@ -0,0 +1,216 @@
+package networkapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nxosDriver decodes the output of the commands piped to json.
+//
+// NX-OS nests rows in TABLE_x and ROW_x members, a ROW_x being an object when
+// there is a single row and an array otherwise. Numbers are often strings.
+type nxosDriver struct{}
+
+type nxosRow map[string]interface{}
+
+func (nxosDriver) Platform() string {
+	return PlatformNXOS
+}
+
+func (nxosDriver) GetConfig(run Runner) (string, error) {
+	return run("show running-config")
+}
+
+func (nxosDriver) GetInterfaces(run Runner) ([]Interface, error) {
+	reply, err := nxosJSON(run, "show interface")
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []Interface
+	for _, row := range reply.rows("TABLE_interface", "ROW_interface") {
+		intf := Interface{
+			Name:        row.str("interface"),
+			AdminStatus: strings.ToLower(row.str("admin_state")),
+			OperStatus:  strings.ToLower(row.str("state")),
+			Description: row.str("desc"),
+		}
+		if bw := row.str("eth_bw"); bw != "" {
+			intf.Speed = uint64(parseInt(bw)) * 1000
+		} else {
+			intf.Speed = parseSpeed(row.str("eth_speed"))
+		}
+		interfaces = append(interfaces, intf)
+	}
+	return interfaces, nil
+}
+
+// GetBGPSummary returns the IPv4 unicast neighbors of every VRF.
+func (nxosDriver) GetBGPSummary(run Runner) ([]BGPPeer, error) {
+	reply, err := nxosJSON(run, "show ip bgp summary vrf all")
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []BGPPeer
+	for _, vrf := range reply.find("ROW_vrf") {
+		for _, row := range vrf.find("ROW_neighbor") {
+			received := parseInt(row.str("prefixreceived"))
+			peers = append(peers, BGPPeer{
+				VRF:              vrf.str("vrf-name-out"),
+				Address:          row.str("neighborid"),
+				AS:               row.str("neighboras"),
+				State:            row.str("state"),
+				PrefixesReceived: received,
+				PrefixesAccepted: received,
+			})
+		}
+	}
+	sortPeers(peers)
+	return peers, nil
+}
+
+func (nxosDriver) GetLLDPNeighbors(run Runner) ([]LLDPNeighbor, error) {
+	reply, err := nxosJSON(run, "show lldp neighbors detail")
+	if err != nil {
+		return nil, err
+	}
+
+	var neighbors []LLDPNeighbor
+	for _, row := range reply.rows("TABLE_nbor_detail", "ROW_nbor_detail") {
+		neighbors = append(neighbors, LLDPNeighbor{
+			LocalInterface:         row.str("l_port_id"),
+			RemoteChassisIDSubtype: row.str("chassis_type"),
+			RemoteChassisID:        row.str("chassis_id"),
+			RemotePortIDSubtype:    row.str("port_type"),
+			RemotePortID:           row.str("port_id"),
+			RemotePortDescription:  row.str("port_desc"),
+			RemoteSystemName:       row.str("sys_name"),
+		})
+	}
+	return neighbors, nil
+}
+
+func (nxosDriver) GetUptime(run Runner) (time.Duration, error) {
+	reply, err := nxosJSON(run, "show system uptime")
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parseInt(reply.str("sys_up_days")))*24*time.Hour +
+		time.Duration(parseInt(reply.str("sys_up_hrs")))*time.Hour +
+		time.Duration(parseInt(reply.str("sys_up_mins")))*time.Minute +
+		time.Duration(parseInt(reply.str("sys_up_secs")))*time.Second, nil
+}
+
+// GetOptics returns the lanes of the transceivers present, older releases
+// report a single lane in the interface row itself.
+func (nxosDriver) GetOptics(run Runner) ([]Optics, error) {
+	reply, err := nxosJSON(run, "show interface transceiver details")
+	if err != nil {
+		return nil, err
+	}
+
+	var optics []Optics
+	for _, row := range reply.rows("TABLE_interface", "ROW_interface") {
+		if row.str("sfp") != "present" {
+			continue
+		}
+		lanes := row.rows("TABLE_lane", "ROW_lane")
+		if len(lanes) == 0 {
+			lanes = []nxosRow{row}
+		}
+		for _, lane := range lanes {
+			if lane.str("tx_pwr") == "" && lane.str("rx_pwr") == "" {
+				continue
+			}
+			optics = append(optics, Optics{
+				Interface:   row.str("interface"),
+				Lane:        parseInt(lane.str("lane_number")),
+				Temperature: parseFloat(lane.str("temperature")),
+				TxPower:     parsePower(lane.str("tx_pwr")),
+				RxPower:     parsePower(lane.str("rx_pwr")),
+			})
+		}
+	}
+	sortOptics(optics)
+	return optics, nil
+}
+
+// nxosJSON runs a command piped to json and decodes its output.
+func nxosJSON(run Runner, command string) (nxosRow, error) {
+	output, err := run(command + " | json")
+	if err != nil {
+		return nil, err
+	}
+	var reply nxosRow
+	if err := json.Unmarshal([]byte(output), &reply); err != nil {
+		return nil, fmt.Errorf("%s: %s", command, err)
+	}
+	return reply, nil
+}
+
+// str returns a member as a string, numbers included.
+func (r nxosRow) str(key string) string {
+	switch v := r[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// rows returns the rows of a table of the row.
+func (r nxosRow) rows(table, row string) []nxosRow {
+	t, ok := r[table].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return nxosRows(t[row])
+}
+
+// find returns the rows of every table, however deep, holding rows named row.
+func (r nxosRow) find(row string) []nxosRow {
+	var found []nxosRow
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, member := range v {
+				if key == row {
+					found = append(found, nxosRows(member)...)
+				} else {
+					walk(member)
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(map[string]interface{}(r))
+	return found
+}
+
+func nxosRows(v interface{}) []nxosRow {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []nxosRow{v}
+	case []interface{}:
+		rows := make([]nxosRow, 0, len(v))
+		for _, item := range v {
+			if row, ok := item.(map[string]interface{}); ok {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package networkapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// driverFixtures maps the commands run by the drivers to the captured
+// output under testdata/<platform>.
+var driverFixtures = map[string]map[string]string{
+	PlatformJunos: {
+		"show interfaces | display xml":                                 "interfaces.xml",
+		"show bgp summary | display xml":                                "bgp.xml",
+		"show lldp neighbors | display xml":                             "lldp.xml",
+		"show system uptime | display xml":                              "uptime.xml",
+		"show interfaces diagnostics optics | display xml":              "optics.xml",
+		"show route detail | display xml":                               "route-detail.xml",
+		"show arp no-resolve expiration-time | display xml":             "arp.xml",
+		"show ipv6 neighbors | display xml":                             "ipv6-neighbors.xml",
+		"show ospf neighbor detail | display xml":                       "ospf-neighbor.xml",
+		"show ospf3 neighbor detail | display xml":                      "ospf3-neighbor.xml",
+		"show ospf interface detail | display xml":                      "ospf-interface.xml",
+		"show isis adjacency | display xml":                             "isis-adjacency.xml",
+		"show isis database | display xml":                              "isis-database.xml",
+		"show mpls lsp extensive | display xml":                         "mpls-lsp.xml",
+		"show rsvp session | display xml":                               "rsvp-session.xml",
+		"show ldp neighbor | display xml":                               "ldp-neighbor.xml",
+		"show ldp session detail | display xml":                         "ldp-session.xml",
+		"show ldp database | display xml":                               "ldp-database.xml",
+		"show chassis alarms | display xml":                             "chassis-alarms.xml",
+		"show system alarms | display xml":                              "system-alarms.xml",
+		"show chassis environment | display xml":                        "environment.xml",
+		"show chassis routing-engine | display xml":                     "routing-engine.xml",
+		"show interfaces ae* | display xml":                             "interfaces-ae.xml",
+		"show lacp interfaces extensive | display xml":                  "lacp.xml",
+		"show firewall | display xml":                                   "firewall.xml",
+		"show firewall filter protect-re | display xml":                 "firewall-filter.xml",
+		"show firewall filter customer-a-in-ge-0/0/5.0-i | display xml": "firewall-filter-interface.xml",
+		"ping 10.255.0.3 count 5 rapid | display xml":                   "ping.xml",
+		"ping 192.0.2.99 count 5 rapid | display xml":                   "ping-unreachable.xml",
+		"traceroute 10.255.0.3 no-resolve | display xml":                "traceroute.xml",
+	},
+	PlatformIOSXR: {
+		"show interfaces description": "interfaces.txt",
+		"show bgp summary":            "bgp.txt",
+		"show lldp neighbors detail":  "lldp.txt",
+		"show version":                "version.txt",
+	},
+	PlatformNXOS: {
+		"show interface | json":                     "interfaces.json",
+		"show ip bgp summary vrf all | json":        "bgp.json",
+		"show lldp neighbors detail | json":         "lldp.json",
+		"show system uptime | json":                 "uptime.json",
+		"show interface transceiver details | json": "optics.json",
+	},
+	PlatformEOS: {
+		"show interfaces | json":             "interfaces.json",
+		"show ip bgp summary vrf all | json": "bgp.json",
+		"show lldp neighbors detail | json":  "lldp.json",
+		"show version | json":                "version.json",
+		"show interfaces transceiver | json": "optics.json",
+	},
+}
+
+// fixtureRunner returns a runner answering the commands of the platform
+// with their captured output.
+func fixtureRunner(t testing.TB, platform string) Runner {
+	return func(command string) (string, error) {
+		name, ok := driverFixtures[platform][command]
+		if !ok {
+			return "", fmt.Errorf("%s: no fixture for %q", platform, command)
+		}
+		output, err := os.ReadFile(filepath.Join("testdata", platform, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output), nil
+	}
+}
+
+// fileRunner returns a runner answering command with the captured output in
+// testdata/<platform>/<name>, for commands answered differently by releases.
+func fileRunner(t testing.TB, platform, command, name string) Runner {
+	return func(c string) (string, error) {
+		if c != command {
+			return "", fmt.Errorf("%s: no fixture for %q", platform, c)
+		}
+		output, err := os.ReadFile(filepath.Join("testdata", platform, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output), nil
+	}
+}
+
+func testDriver(t *testing.T, platform string) Driver {
+	t.Helper()
+	driver, err := GetDriver(platform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return driver
+}
+
+func TestGetInterfaces(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     []Interface
+	}{
+		{PlatformJunos, []Interface{
+			{Name: "ge-0/0/0", AdminStatus: "up", OperStatus: "up", Description: "to-core-1", Speed: 1e9},
+			{Name: "ge-0/0/1", AdminStatus: "down", OperStatus: "down", Speed: 1e9},
+			{Name: "xe-0/1/0", AdminStatus: "up", OperStatus: "down", Description: "spare", Speed: 1e10},
+			{Name: "lo0", AdminStatus: "up", OperStatus: "up"},
+		}},
+		{PlatformIOSXR, []Interface{
+			{Name: "Lo0", AdminStatus: "up", OperStatus: "up", Description: "router-id"},
+			{Name: "Mg0/RP0/CPU0/0", AdminStatus: "up", OperStatus: "up", Description: "oob"},
+			{Name: "Gi0/0/0/0", AdminStatus: "up", OperStatus: "up", Description: "to core-1 ge-0/0/0"},
+			{Name: "Gi0/0/0/1", AdminStatus: "down", OperStatus: "down"},
+			{Name: "Te0/0/0/2", AdminStatus: "up", OperStatus: "down", Description: "spare"},
+		}},
+		{PlatformNXOS, []Interface{
+			{Name: "mgmt0", AdminStatus: "up", OperStatus: "up", Speed: 1e9},
+			{Name: "Ethernet1/1", AdminStatus: "up", OperStatus: "up", Description: "to-core-1", Speed: 1e10},
+			{Name: "Ethernet1/2", AdminStatus: "down", OperStatus: "down"},
+		}},
+		{PlatformEOS, []Interface{
+			{Name: "Ethernet1", AdminStatus: "up", OperStatus: "up", Description: "to-core-1", Speed: 1e10},
+			{Name: "Ethernet2", AdminStatus: "down", OperStatus: "down", Speed: 1e10},
+			{Name: "Ethernet49/1", AdminStatus: "up", OperStatus: "down", Description: "spare", Speed: 1e11},
+			{Name: "Management1", AdminStatus: "up", OperStatus: "up", Description: "oob", Speed: 1e9},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, err := testDriver(t, tt.platform).GetInterfaces(fixtureRunner(t, tt.platform))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetInterfaces() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBGPSummary(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     []BGPPeer
+	}{
+		{PlatformJunos, []BGPPeer{
+			{VRF: "default", Address: "10.0.0.2", AS: "65002", State: "Established", PrefixesReceived: 19, PrefixesAccepted: 18},
+			{VRF: "default", Address: "10.0.0.6", AS: "65003", State: "Active"},
+			{VRF: "customer-a", Address: "192.0.2.2", AS: "64512", State: "Established", PrefixesReceived: 3, PrefixesAccepted: 3},
+		}},
+		{PlatformIOSXR, []BGPPeer{
+			{VRF: "default", Address: "10.0.0.2", AS: "65002", State: "Established", PrefixesReceived: 15, PrefixesAccepted: 15},
+			{VRF: "default", Address: "10.0.0.6", AS: "65003", State: "Active"},
+			{VRF: "default", Address: "2001:db8:ffff:ffff:ffff:ffff:ffff:1", AS: "65004", State: "Established", PrefixesReceived: 3, PrefixesAccepted: 3},
+		}},
+		{PlatformNXOS, []BGPPeer{
+			{VRF: "default", Address: "10.0.0.2", AS: "65002", State: "Established", PrefixesReceived: 15, PrefixesAccepted: 15},
+			{VRF: "default", Address: "10.0.0.6", AS: "65003", State: "Idle"},
+			{VRF: "customer-a", Address: "192.0.2.2", AS: "64512", State: "Established", PrefixesReceived: 3, PrefixesAccepted: 3},
+		}},
+		{PlatformEOS, []BGPPeer{
+			{VRF: "default", Address: "10.0.0.2", AS: "65002", State: "Established", PrefixesReceived: 15, PrefixesAccepted: 14},
+			{VRF: "default", Address: "10.0.0.6", AS: "65003", State: "Active"},
+			{VRF: "customer-a", Address: "192.0.2.2", AS: "64512", State: "Established", PrefixesReceived: 3, PrefixesAccepted: 3},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, err := testDriver(t, tt.platform).GetBGPSummary(fixtureRunner(t, tt.platform))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBGPSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLLDPNeighbors(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     []LLDPNeighbor
+	}{
+		{PlatformJunos, []LLDPNeighbor{
+			{LocalPortID: "ge-0/0/0", LocalParentInterface: "-", RemoteChassisIDSubtype: "Mac address", RemoteChassisID: "2c:6b:f5:a1:00:c0",
+				RemotePortIDSubtype: "Interface name", RemotePortID: "ge-0/0/3", RemoteSystemName: "core-1"},
+			{LocalPortID: "xe-0/1/0", LocalParentInterface: "ae0", RemoteChassisIDSubtype: "Mac address", RemoteChassisID: "28:8a:1c:e4:3f:00",
+				RemotePortIDSubtype: "Locally assigned", RemotePortID: "531", RemotePortDescription: "xe-0/0/1", RemoteSystemName: "access-2"},
+		}},
+		{PlatformIOSXR, []LLDPNeighbor{
+			{LocalInterface: "GigabitEthernet0/0/0/0", RemoteChassisID: "2c6b.f5a1.00c0", RemotePortID: "ge-0/0/3",
+				RemotePortDescription: "to-pe-1", RemoteSystemName: "core-1"},
+			{LocalInterface: "TenGigE0/0/0/2", RemoteChassisID: "288a.1ce4.3f00", RemotePortID: "Ethernet49/1", RemoteSystemName: "access-2"},
+		}},
+		{PlatformNXOS, []LLDPNeighbor{
+			{LocalInterface: "Eth1/1", RemoteChassisIDSubtype: "Mac Address", RemoteChassisID: "2c6b.f5a1.00c0",
+				RemotePortIDSubtype: "Interface Name", RemotePortID: "ge-0/0/3", RemotePortDescription: "to-leaf-1", RemoteSystemName: "core-1"},
+			{LocalInterface: "Eth1/49", RemoteChassisIDSubtype: "Mac Address", RemoteChassisID: "288a.1ce4.3f00",
+				RemotePortIDSubtype: "Interface Name", RemotePortID: "Ethernet49/1", RemoteSystemName: "access-2"},
+		}},
+		{PlatformEOS, []LLDPNeighbor{
+			{LocalInterface: "Ethernet1", RemoteChassisIDSubtype: "macAddress", RemoteChassisID: "2c6b.f5a1.00c0",
+				RemotePortIDSubtype: "interfaceName", RemotePortID: "ge-0/0/3", RemotePortDescription: "to-leaf-1", RemoteSystemName: "core-1"},
+			{LocalInterface: "Ethernet2", RemoteChassisIDSubtype: "macAddress", RemoteChassisID: "288a.1ce4.3f00",
+				RemotePortIDSubtype: "interfaceName", RemotePortID: "Ethernet49/1", RemoteSystemName: "access-2"},
+			{LocalInterface: "Ethernet2", RemoteChassisIDSubtype: "macAddress", RemoteChassisID: "288a.1ce4.3f01",
+				RemotePortIDSubtype: "interfaceName", RemotePortID: "Ethernet49/1", RemoteSystemName: "access-3"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, err := testDriver(t, tt.platform).GetLLDPNeighbors(fixtureRunner(t, tt.platform))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLLDPNeighbors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUptime(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		platform string
+		want     time.Duration
+	}{
+		// re0 is reported, not the first routing engine of the output.
+		{PlatformJunos, 1306677 * time.Second},
+		{PlatformIOSXR, 17*day + 5*time.Hour + 10*time.Minute},
+		{PlatformNXOS, 15*day + 2*time.Hour + 57*time.Minute + 57*time.Second},
+		{PlatformEOS, 1306677*time.Second + 250*time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, err := testDriver(t, tt.platform).GetUptime(fixtureRunner(t, tt.platform))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("GetUptime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOptics(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     []Optics
+		err      error
+	}{
+		{platform: PlatformJunos, want: []Optics{
+			{Interface: "ge-0/0/0", Temperature: 34, TxPower: -5.61, RxPower: -6.28},
+			{Interface: "et-0/0/2", Lane: 0, Temperature: 41.5, TxPower: -0.61, RxPower: -1.10},
+			{Interface: "et-0/0/2", Lane: 1, Temperature: 41.5, TxPower: NoLight, RxPower: NoLight},
+		}},
+		{platform: PlatformIOSXR, err: ErrNotSupported},
+		{platform: PlatformNXOS, want: []Optics{
+			{Interface: "Ethernet1/1", Temperature: 34.25, TxPower: -2.41, RxPower: -3.08},
+			{Interface: "Ethernet1/49", Lane: 1, Temperature: 41.5, TxPower: -0.61, RxPower: -1.10},
+			{Interface: "Ethernet1/49", Lane: 2, Temperature: 41.5, TxPower: -0.72, RxPower: NoLight},
+		}},
+		{platform: PlatformEOS, want: []Optics{
+			{Interface: "Ethernet1", Temperature: 34.25, TxPower: -2.41, RxPower: -3.08},
+			{Interface: "Ethernet2", Temperature: 30, TxPower: -30, RxPower: NoLight},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got, err := testDriver(t, tt.platform).GetOptics(fixtureRunner(t, tt.platform))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("GetOptics() error = %v, want %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOptics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDriverRunError(t *testing.T) {
+	failed := errors.New("connection reset")
+	run := func(command string) (string, error) { return "", failed }
+
+	for _, platform := range []string{PlatformJunos, PlatformIOSXR, PlatformNXOS, PlatformEOS} {
+		t.Run(platform, func(t *testing.T) {
+			driver := testDriver(t, platform)
+			if _, err := driver.GetInterfaces(run); !errors.Is(err, failed) {
+				t.Errorf("GetInterfaces() error = %v", err)
+			}
+			if _, err := driver.GetBGPSummary(run); !errors.Is(err, failed) {
+				t.Errorf("GetBGPSummary() error = %v", err)
+			}
+			if _, err := driver.GetLLDPNeighbors(run); !errors.Is(err, failed) {
+				t.Errorf("GetLLDPNeighbors() error = %v", err)
+			}
+			if _, err := driver.GetUptime(run); !errors.Is(err, failed) {
+				t.Errorf("GetUptime() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGetDriver(t *testing.T) {
+	driver, err := GetDriver("")
+	if err != nil || driver.Platform() != PlatformJunos {
+		t.Errorf("GetDriver(\"\") = %v, %v, want the junos driver", driver, err)
+	}
+	driver, err = GetDriver("NXOS")
+	if err != nil || driver.Platform() != PlatformNXOS {
+		t.Errorf("GetDriver(\"NXOS\") = %v, %v, want the nxos driver", driver, err)
+	}
+	if _, err := GetDriver("vyos"); err == nil {
+		t.Error("GetDriver(\"vyos\") returned no error")
+	}
+}
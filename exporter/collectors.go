@@ -0,0 +1,285 @@
+package exporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// collectorFunc collects the metrics of a device. Junos devices are read
+// with the Junos readers, which report counters and thresholds the drivers
+// don't, and the other platforms with their driver.
+type collectorFunc func(driver networkapi.Driver, run networkapi.Runner, reg *registry) error
+
+// Collectors lists the collectors run by default, they can be picked per
+// scrape with the collect parameter.
+var Collectors = []string{"interfaces", "bgp", "optics", "uptime"}
+
+var collectors = map[string]collectorFunc{
+	"interfaces": collectInterfaces,
+	"bgp":        collectBGP,
+	"optics":     collectOptics,
+	"uptime":     collectUptime,
+	"firewall":   collectFirewall,
+}
+
+var bgpStates = map[string]float64{
+	"idle":        1,
+	"connect":     2,
+	"active":      3,
+	"opensent":    4,
+	"openconfirm": 5,
+	"established": 6,
+}
+
+func collectInterfaces(driver networkapi.Driver, run networkapi.Runner, reg *registry) error {
+
+	if driver.Platform() != networkapi.PlatformJunos {
+		interfaces, err := driver.GetInterfaces(run)
+		if err != nil {
+			return err
+		}
+		for _, intf := range interfaces {
+			interfaceStatus(reg, intf.Name, intf.Description, intf.AdminStatus, intf.OperStatus)
+		}
+		return nil
+	}
+
+	interfaces, err := networkapi.ReadInterfacesStatistics(run)
+	if err != nil {
+		return err
+	}
+
+	for _, intf := range interfaces.PhysicalInterface {
+		name := strings.TrimSpace(intf.Name)
+		interfaceStatus(reg, name, strings.TrimSpace(intf.Description), strings.TrimSpace(intf.Adminstatus), strings.TrimSpace(intf.Operstatus))
+
+		stats := intf.TrafficStatistics
+		reg.counter("networkapi_interface_receive_bytes_total", "Bytes received on the interface.", float64(stats.InputBytes), "interface", name)
+		reg.counter("networkapi_interface_transmit_bytes_total", "Bytes sent on the interface.", float64(stats.OutputBytes), "interface", name)
+		reg.counter("networkapi_interface_receive_packets_total", "Packets received on the interface.", float64(stats.InputPackets), "interface", name)
+		reg.counter("networkapi_interface_transmit_packets_total", "Packets sent on the interface.", float64(stats.OutputPackets), "interface", name)
+		reg.counter("networkapi_interface_receive_errors_total", "Input errors of the interface.", float64(intf.InputErrors.InputErrors), "interface", name)
+		reg.counter("networkapi_interface_transmit_errors_total", "Output errors of the interface.", float64(intf.OutputErrors.OutputErrors), "interface", name)
+		reg.counter("networkapi_interface_receive_drops_total", "Input drops of the interface.", float64(intf.InputErrors.InputDrops), "interface", name)
+		reg.counter("networkapi_interface_transmit_drops_total", "Output drops of the interface.", float64(intf.OutputErrors.OutputDrops), "interface", name)
+	}
+
+	return nil
+}
+
+func interfaceStatus(reg *registry, name, description, admin, oper string) {
+	reg.gauge("networkapi_interface_admin_up", "Administrative status of the interface, 1 when up.",
+		boolValue(admin == "up"), "interface", name, "description", description)
+	reg.gauge("networkapi_interface_oper_up", "Operational status of the interface, 1 when up.",
+		boolValue(oper == "up"), "interface", name, "description", description)
+}
+
+// collectBGP exports the state of the peers, labelled with their VRF, with
+// their prefix counts per table on Junos and summed over the tables on the
+// other platforms.
+func collectBGP(driver networkapi.Driver, run networkapi.Runner, reg *registry) error {
+
+	if driver.Platform() != networkapi.PlatformJunos {
+		peers, err := driver.GetBGPSummary(run)
+		if err != nil {
+			return err
+		}
+		for _, peer := range peers {
+			peerState(reg, peer.VRF, peer.Address, peer.AS, peer.State)
+			reg.gauge("networkapi_bgp_peer_prefixes", "Prefixes of the BGP peer per table.",
+				float64(peer.PrefixesReceived), "vrf", peer.VRF, "peer", peer.Address, "peer_as", peer.AS, "table", "", "type", "received")
+			reg.gauge("networkapi_bgp_peer_prefixes", "Prefixes of the BGP peer per table.",
+				float64(peer.PrefixesAccepted), "vrf", peer.VRF, "peer", peer.Address, "peer_as", peer.AS, "table", "", "type", "accepted")
+		}
+		return nil
+	}
+
+	bgp, err := networkapi.ReadBGPSummary(run)
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range bgp.Bgpinformation.Bgppeer {
+		address := strings.TrimSpace(peer.Peeraddress)
+		as := strings.TrimSpace(peer.Peeras)
+		vrf := peer.Instance()
+		peerState(reg, vrf, address, as, strings.TrimSpace(peer.Peerstate))
+		if flaps, err := strconv.ParseFloat(strings.TrimSpace(peer.Flapcount), 64); err == nil {
+			reg.counter("networkapi_bgp_peer_flaps_total", "Number of times the BGP session flapped.", flaps, "vrf", vrf, "peer", address, "peer_as", as)
+		}
+
+		for _, rib := range peer.Bgprib {
+			table := strings.TrimSpace(rib.Name)
+			counts := []struct{ kind, value string }{
+				{"received", rib.Receivedprefixcount},
+				{"accepted", rib.Acceptedprefixcount},
+				{"active", rib.Activeprefixcount},
+				{"suppressed", rib.Suppressedprefixcount},
+			}
+			for _, count := range counts {
+				if value, ok := parseFloat(count.value); ok {
+					reg.gauge("networkapi_bgp_peer_prefixes", "Prefixes of the BGP peer per table.",
+						value, "vrf", vrf, "peer", address, "peer_as", as, "table", table, "type", count.kind)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func peerState(reg *registry, vrf, address, as, state string) {
+	reg.gauge("networkapi_bgp_peer_up", "1 when the BGP session is established.",
+		boolValue(strings.EqualFold(state, "Established")), "vrf", vrf, "peer", address, "peer_as", as)
+	reg.gauge("networkapi_bgp_peer_state", "State of the BGP session: 1 idle, 2 connect, 3 active, 4 opensent, 5 openconfirm, 6 established.",
+		bgpStates[strings.ToLower(state)], "vrf", vrf, "peer", address, "peer_as", as)
+}
+
+// collectOptics exports the power and temperature of the optics, with the
+// voltage, bias current and thresholds on Junos.
+func collectOptics(driver networkapi.Driver, run networkapi.Runner, reg *registry) error {
+
+	if driver.Platform() != networkapi.PlatformJunos {
+		optics, err := driver.GetOptics(run)
+		if err != nil {
+			return err
+		}
+		// The lanes of a module share its temperature, the optics being
+		// sorted by interface its first lane reports it.
+		for i, o := range optics {
+			lane := strconv.Itoa(o.Lane)
+			if i == 0 || optics[i-1].Interface != o.Interface {
+				reg.gauge("networkapi_optics_temperature_celsius", "Temperature of the optic module.", o.Temperature, "interface", o.Interface)
+			}
+			reg.gauge("networkapi_optics_tx_power_dbm", "Transmit power of the optic lane.", o.TxPower, "interface", o.Interface, "lane", lane)
+			reg.gauge("networkapi_optics_rx_power_dbm", "Receive power of the optic lane.", o.RxPower, "interface", o.Interface, "lane", lane)
+		}
+		return nil
+	}
+
+	diagnostics, err := networkapi.ReadInterfacesDiagnostics(run)
+	if err != nil {
+		return err
+	}
+
+	for _, intf := range diagnostics.InterfaceInformation.PhysicalInterface {
+		name := strings.TrimSpace(intf.Name)
+		optics := intf.OpticsDiagnostics
+
+		if value, ok := parseFloat(optics.ModuleTemperature.Celsius); ok {
+			reg.gauge("networkapi_optics_temperature_celsius", "Temperature of the optic module.", value, "interface", name)
+		}
+		if value, ok := parseFloat(optics.ModuleVoltage); ok {
+			reg.gauge("networkapi_optics_voltage_volts", "Supply voltage of the optic module.", value, "interface", name)
+		}
+
+		thresholds := []struct {
+			metric, help, level, direction, value string
+		}{
+			{"networkapi_optics_temperature_threshold_celsius", "Temperature thresholds of the optic module.", "alarm", "high", optics.ModuleTemperatureHighAlarmThreshold.Celsius},
+			{"networkapi_optics_temperature_threshold_celsius", "Temperature thresholds of the optic module.", "alarm", "low", optics.ModuleTemperatureLowAlarmThreshold.Celsius},
+			{"networkapi_optics_temperature_threshold_celsius", "Temperature thresholds of the optic module.", "warning", "high", optics.ModuleTemperatureHighWarnThreshold.Celsius},
+			{"networkapi_optics_temperature_threshold_celsius", "Temperature thresholds of the optic module.", "warning", "low", optics.ModuleTemperatureLowWarnThreshold.Celsius},
+			{"networkapi_optics_rx_power_threshold_dbm", "Receive power thresholds of the optic.", "alarm", "high", optics.LaserRxPowerHighAlarmThresholdDbm},
+			{"networkapi_optics_rx_power_threshold_dbm", "Receive power thresholds of the optic.", "alarm", "low", optics.LaserRxPowerLowAlarmThresholdDbm},
+			{"networkapi_optics_rx_power_threshold_dbm", "Receive power thresholds of the optic.", "warning", "high", optics.LaserRxPowerHighWarnThresholdDbm},
+			{"networkapi_optics_rx_power_threshold_dbm", "Receive power thresholds of the optic.", "warning", "low", optics.LaserRxPowerLowWarnThresholdDbm},
+			{"networkapi_optics_tx_power_threshold_dbm", "Transmit power thresholds of the optic.", "alarm", "high", optics.LaserTxPowerHighAlarmThresholdDbm},
+			{"networkapi_optics_tx_power_threshold_dbm", "Transmit power thresholds of the optic.", "alarm", "low", optics.LaserTxPowerLowAlarmThresholdDbm},
+			{"networkapi_optics_tx_power_threshold_dbm", "Transmit power thresholds of the optic.", "warning", "high", optics.LaserTxPowerHighWarnThresholdDbm},
+			{"networkapi_optics_tx_power_threshold_dbm", "Transmit power thresholds of the optic.", "warning", "low", optics.LaserTxPowerLowWarnThresholdDbm},
+			{"networkapi_optics_bias_current_threshold_milliamperes", "Laser bias current thresholds of the optic.", "alarm", "high", optics.LaserBiasCurrentHighAlarmThreshold},
+			{"networkapi_optics_bias_current_threshold_milliamperes", "Laser bias current thresholds of the optic.", "alarm", "low", optics.LaserBiasCurrentLowAlarmThreshold},
+			{"networkapi_optics_bias_current_threshold_milliamperes", "Laser bias current thresholds of the optic.", "warning", "high", optics.LaserBiasCurrentHighWarnThreshold},
+			{"networkapi_optics_bias_current_threshold_milliamperes", "Laser bias current thresholds of the optic.", "warning", "low", optics.LaserBiasCurrentLowWarnThreshold},
+		}
+		for _, t := range thresholds {
+			if value, ok := parseFloat(t.value); ok {
+				reg.gauge(t.metric, t.help, value, "interface", name, "level", t.level, "direction", t.direction)
+			}
+		}
+
+		// Single lane optics report their values outside of the lane list.
+		if len(optics.OpticsDiagnosticsLaneValues) == 0 {
+			laneMetrics(reg, name, "0", optics.LaserBiasCurrent, optics.LaserOutputPowerDbm, optics.RxSignalAvgOpticalPowerDbm)
+		}
+		for _, lane := range optics.OpticsDiagnosticsLaneValues {
+			laneMetrics(reg, name, strings.TrimSpace(lane.LaneIndex), lane.LaserBiasCurrent, lane.LaserOutputPowerDbm, lane.LaserRxOpticalPowerDbm)
+		}
+	}
+
+	return nil
+}
+
+func laneMetrics(reg *registry, name, lane, bias, txPower, rxPower string) {
+	if value, ok := parseFloat(bias); ok {
+		reg.gauge("networkapi_optics_bias_current_milliamperes", "Laser bias current of the optic lane.", value, "interface", name, "lane", lane)
+	}
+	if value, ok := parseFloat(txPower); ok {
+		reg.gauge("networkapi_optics_tx_power_dbm", "Transmit power of the optic lane.", value, "interface", name, "lane", lane)
+	}
+	if value, ok := parseFloat(rxPower); ok {
+		reg.gauge("networkapi_optics_rx_power_dbm", "Receive power of the optic lane.", value, "interface", name, "lane", lane)
+	}
+}
+
+// collectUptime exports the uptime of every routing engine on Junos, and of
+// the device, as re0, on the other platforms.
+func collectUptime(driver networkapi.Driver, run networkapi.Runner, reg *registry) error {
+
+	if driver.Platform() != networkapi.PlatformJunos {
+		uptime, err := driver.GetUptime(run)
+		if err != nil {
+			return err
+		}
+		reg.gauge("networkapi_re_uptime_seconds", "Time since the routing engine booted.", uptime.Seconds(), "re", "re0")
+		return nil
+	}
+
+	engines, err := networkapi.ReadSystemUptimeInfo(run)
+	if err != nil {
+		return err
+	}
+
+	for re, uptime := range engines {
+		if value, ok := parseFloat(uptime.SystemUptime.Seconds); ok {
+			reg.gauge("networkapi_re_uptime_seconds", "Time since the routing engine booted.", value, "re", re)
+		}
+		if value, ok := parseFloat(uptime.SystemBootedTime.Seconds); ok {
+			reg.gauge("networkapi_re_boot_timestamp_seconds", "Unix time the routing engine booted.", value, "re", re)
+		}
+		if value, ok := parseFloat(uptime.LastConfiguredTime.Seconds); ok {
+			reg.gauge("networkapi_re_last_configured_timestamp_seconds", "Unix time of the last commit.", value, "re", re)
+		}
+	}
+
+	return nil
+}
+
+// collectFirewall exports the counters of the firewall filters, the rate of
+// which gives the packets and bytes per second matched by each term.
+func collectFirewall(driver networkapi.Driver, run networkapi.Runner, reg *registry) error {
+
+	if driver.Platform() != networkapi.PlatformJunos {
+		return fmt.Errorf("firewall counters: %w", networkapi.ErrNotSupported)
+	}
+	filters, err := networkapi.ReadFirewallCounters(run, "")
+	if err != nil {
+		return err
+	}
+
+	for _, filter := range filters {
+		for _, counter := range filter.Counters {
+			reg.counter("networkapi_firewall_counter_packets_total", "Packets counted by the term of the firewall filter.", float64(counter.Packets), "filter", filter.Name, "counter", counter.Name)
+			reg.counter("networkapi_firewall_counter_bytes_total", "Bytes counted by the term of the firewall filter.", float64(counter.Bytes), "filter", filter.Name, "counter", counter.Name)
+		}
+		for _, policer := range filter.Policers {
+			reg.counter("networkapi_firewall_policer_packets_total", "Packets dropped by the policer of the firewall filter.", float64(policer.Packets), "filter", filter.Name, "policer", policer.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// fixtures maps the commands run by the collectors to the captured output
+// under testdata/<platform>.
+var fixtures = map[string]map[string]string{
+	networkapi.PlatformJunos: {
+		"show bgp summary | display xml":                   "bgp.xml",
+		"show interfaces diagnostics optics | display xml": "optics.xml",
+	},
+	networkapi.PlatformNXOS: {
+		"show ip bgp summary vrf all | json":        "bgp.json",
+		"show interface transceiver details | json": "optics.json",
+	},
+	networkapi.PlatformEOS: {
+		"show ip bgp summary vrf all | json": "bgp.json",
+		"show interfaces transceiver | json": "optics.json",
+	},
+}
+
+func fixtureRunner(t *testing.T, platform string) networkapi.Runner {
+	return func(command string) (string, error) {
+		name, ok := fixtures[platform][command]
+		if !ok {
+			return "", fmt.Errorf("no fixture for %q", command)
+		}
+		data, err := os.ReadFile(filepath.Join("..", "testdata", platform, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data), nil
+	}
+}
+
+// collect runs the collector on the fixtures of the platform and returns the
+// exposition lines of the metric.
+func collect(t *testing.T, platform string, collector collectorFunc, metric string) []string {
+	t.Helper()
+	driver, err := networkapi.GetDriver(platform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := newRegistry()
+	if err := collector(driver, fixtureRunner(t, platform), reg); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := reg.write(&b); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if strings.HasPrefix(line, metric+"{") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestCollectOptics(t *testing.T) {
+	for platform := range fixtures {
+		t.Run(platform, func(t *testing.T) {
+			lines := collect(t, platform, collectOptics, "networkapi_optics_temperature_celsius")
+			if len(lines) == 0 {
+				t.Fatal("no temperature")
+			}
+			seen := make(map[string]bool)
+			for _, line := range lines {
+				series := line[:strings.LastIndex(line, " ")]
+				if seen[series] {
+					t.Errorf("duplicate series %s", series)
+				}
+				seen[series] = true
+			}
+		})
+	}
+}
+
+func TestCollectBGP(t *testing.T) {
+	for platform := range fixtures {
+		t.Run(platform, func(t *testing.T) {
+			lines := collect(t, platform, collectBGP, "networkapi_bgp_peer_up")
+			want := map[string]bool{
+				`networkapi_bgp_peer_up{vrf="default",peer="10.0.0.2",peer_as="65002"} 1`:     true,
+				`networkapi_bgp_peer_up{vrf="customer-a",peer="192.0.2.2",peer_as="64512"} 1`: true,
+			}
+			for _, line := range lines {
+				delete(want, line)
+			}
+			for line := range want {
+				t.Errorf("missing %s in %q", line, lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package exporter serves device metrics in the Prometheus text format.
+//
+// It follows the multi-target pattern: Prometheus scrapes
+// /metrics?target=<host> for every device, where host is the name of a device
+// of the inventory. Without target the exporter reports its own metrics.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// Exporter collects the metrics of the inventory devices on every scrape.
+type Exporter struct {
+	inventory *networkapi.Inventory
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*networkapi.Client
+	scrapes map[string]float64
+	errors  map[string]float64
+}
+
+// New returns an exporter for the devices of the inventory. A scrape is
+// aborted after timeout, or earlier when Prometheus asks for it.
+func New(inventory *networkapi.Inventory, timeout time.Duration) *Exporter {
+	return &Exporter{
+		inventory: inventory,
+		timeout:   timeout,
+		clients:   make(map[string]*networkapi.Client),
+		scrapes:   make(map[string]float64),
+		errors:    make(map[string]float64),
+	}
+}
+
+// Close closes the connections to the devices.
+func (e *Exporter) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, client := range e.clients {
+		client.DisconnectSSH()
+	}
+}
+
+// ServeHTTP serves a scrape of the target device, or the metrics of the
+// exporter itself when no target is given.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	reg := newRegistry()
+	target := r.URL.Query().Get("target")
+
+	if target == "" {
+		e.selfMetrics(reg)
+	} else {
+		device, ok := e.inventory.Lookup(target)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown target %q", target), http.StatusNotFound)
+			return
+		}
+
+		names := Collectors
+		if collect := r.URL.Query().Get("collect"); collect != "" {
+			names = strings.Split(collect, ",")
+		}
+		for _, name := range names {
+			if _, ok := collectors[name]; !ok {
+				http.Error(w, fmt.Sprintf("unknown collector %q", name), http.StatusBadRequest)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), e.scrapeTimeout(r))
+		defer cancel()
+		e.scrape(ctx, e.client(device), device.Hostname, names, reg)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	reg.write(w)
+}
+
+// scrapeTimeout returns the timeout of the exporter, lowered to the one sent
+// by Prometheus if that is shorter.
+func (e *Exporter) scrapeTimeout(r *http.Request) time.Duration {
+	timeout := e.timeout
+	if header := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); header != "" {
+		if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+			if t := time.Duration(seconds * float64(time.Second)); t > 0 && t < timeout {
+				timeout = t
+			}
+		}
+	}
+	return timeout
+}
+
+func (e *Exporter) client(device networkapi.Device) *networkapi.Client {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	client, ok := e.clients[device.Hostname]
+	if !ok {
+		client = e.inventory.Client(device)
+		e.clients[device.Hostname] = client
+	}
+	return client
+}
+
+// scrape runs the collectors in parallel, each over its own session.
+func (e *Exporter) scrape(ctx context.Context, c *networkapi.Client, host string, names []string, reg *registry) {
+
+	type outcome struct {
+		name     string
+		reg      *registry
+		err      error
+		duration time.Duration
+	}
+
+	results := make(chan outcome, len(names))
+	for _, name := range names {
+		go func(name string) {
+			start := time.Now()
+			sub := newRegistry()
+			err := e.collect(ctx, c, collectors[name], sub)
+			results <- outcome{name, sub, err, time.Since(start)}
+		}(name)
+	}
+
+	failed := false
+	for range names {
+		out := <-results
+		reg.gauge("networkapi_collector_success", "1 when the collector succeeded.", boolValue(out.err == nil), "collector", out.name)
+		reg.gauge("networkapi_collector_duration_seconds", "Time the collector took.", out.duration.Seconds(), "collector", out.name)
+		if out.err != nil {
+			failed = true
+			continue
+		}
+		reg.merge(out.reg)
+	}
+
+	e.mu.Lock()
+	e.scrapes[host]++
+	if failed {
+		e.errors[host]++
+	}
+	e.mu.Unlock()
+}
+
+// collect runs one collector with the driver of the device, each command in
+// a session of its own. The session is closed when the context is done, which
+// aborts the command running on the device.
+func (e *Exporter) collect(ctx context.Context, c *networkapi.Client, collector collectorFunc, reg *registry) error {
+
+	driver, err := c.Driver()
+	if err != nil {
+		return err
+	}
+	run := func(command string) (string, error) {
+		return c.RunSSHContext(ctx, command)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- collector(driver, run, reg)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Exporter) selfMetrics(reg *registry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for host, count := range e.scrapes {
+		reg.counter("networkapi_exporter_scrapes_total", "Scrapes of the target.", count, "target", host)
+		reg.counter("networkapi_exporter_scrape_errors_total", "Scrapes of the target with a failed collector.", e.errors[host], "target", host)
+	}
+}
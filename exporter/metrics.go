@@ -0,0 +1,128 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+// registry collects the samples of one scrape and writes them in the
+// Prometheus text exposition format.
+type registry struct {
+	families map[string]*family
+}
+
+func newRegistry() *registry {
+	return &registry{families: make(map[string]*family)}
+}
+
+// gauge adds a gauge sample. Labels are given as name, value pairs.
+func (r *registry) gauge(name, help string, value float64, labels ...string) {
+	r.add("gauge", name, help, value, labels)
+}
+
+// counter adds a counter sample. Labels are given as name, value pairs.
+func (r *registry) counter(name, help string, value float64, labels ...string) {
+	r.add("counter", name, help, value, labels)
+}
+
+func (r *registry) add(kind, name, help string, value float64, labels []string) {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: kind}
+		r.families[name] = f
+	}
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// merge adds the samples of other to r.
+func (r *registry) merge(other *registry) {
+	for _, f := range other.families {
+		for _, s := range f.samples {
+			r.add(f.kind, f.name, f.help, s.value, s.labels)
+		}
+	}
+}
+
+func (r *registry) write(w io.Writer) error {
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := r.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+		for _, s := range f.samples {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(s.labels), formatValue(s.value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// parseFloat parses a value displayed by the device, such as "0.5120",
+// "- Inf" for an optic without light, or "1.23 mA".
+func parseFloat(s string) (float64, bool) {
+	s = strings.Join(strings.Fields(s), "")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "mA"), "dBm")
+	if s == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	return value, err == nil
+}
+
+func boolValue(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+package networkapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	junos "github.com/kgrvamsi/go-junos"
+)
+
+//DetectFacts ... Detects the platform, model and version of the device over SSH and caches them
+//
+// Driver uses the detected platform when the client has none set. A failed
+// detection clears the facts cached before.
+func (c *Client) DetectFacts() (*DeviceFacts, error) {
+	c.mu.Lock()
+	c.detecting++
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.detecting--
+		c.mu.Unlock()
+	}()
+
+	facts, err := DetectFacts(c.RunSSH)
+	if err != nil {
+		c.setFacts(nil)
+		return nil, err
+	}
+	c.setFacts(facts)
+	return facts, nil
+}
+
+// detectOnConnect detects the facts with AutoDetect when none are cached and
+// no detection is running, the connections of its commands being left alone.
+func (c *Client) detectOnConnect() error {
+	c.mu.Lock()
+	detect := c.AutoDetect && c.facts == nil && c.detecting == 0
+	c.mu.Unlock()
+	if !detect {
+		return nil
+	}
+	_, err := c.DetectFacts()
+	return err
+}
+
+//Facts ... Returns the facts detected on connect, nil when none were
+func (c *Client) Facts() *DeviceFacts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.facts
+}
+
+func (c *Client) setFacts(facts *DeviceFacts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.facts = facts
+}
+
+//DetectFacts ... Detects the facts of a device from the output of show version
+//
+// The platform is told by the text of show version, as are the model and
+// version of the platforms other than Junos. On Junos they are read from show
+// version | display xml, whose routing engines, virtual chassis members and
+// cluster nodes each have their items, and the serial number from show
+// chassis hardware.
+func DetectFacts(run Runner) (*DeviceFacts, error) {
+
+	output, err := run("show version")
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := detectPlatform(output)
+	if err != nil {
+		return nil, err
+	}
+	facts := &DeviceFacts{Platform: platform}
+	if platform == PlatformJunos {
+		if err := junosFacts(run, facts); err != nil {
+			return nil, err
+		}
+		return facts, nil
+	}
+	textFacts(output, facts)
+	return facts, nil
+}
+
+func detectPlatform(version string) (string, error) {
+	switch {
+	case strings.Contains(version, "IOS XR") || strings.Contains(version, "IOS-XR"):
+		return PlatformIOSXR, nil
+	case strings.Contains(version, "NX-OS") || strings.Contains(version, "Nexus"):
+		return PlatformNXOS, nil
+	case strings.Contains(version, "Arista"):
+		return PlatformEOS, nil
+	case strings.Contains(strings.ToLower(version), "junos"):
+		return PlatformJunos, nil
+	}
+	return "", fmt.Errorf("unknown platform, show version names none of Junos, IOS XR, NX-OS or EOS")
+}
+
+func junosFacts(run Runner, facts *DeviceFacts) error {
+
+	var version SoftwareInformationSSH
+	if err := junosXML(run, "show version", &version); err != nil {
+		return err
+	}
+
+	// The facts of the first item stand for those of the device.
+	software := version.SoftwareInformation
+	routingEngines := 0
+	for i, item := range version.MultiRoutingEngineItem {
+		name := strings.TrimSpace(item.ReName)
+		facts.RoutingEngines = append(facts.RoutingEngines, RoutingEngineFacts{Name: name})
+		switch {
+		case strings.HasPrefix(name, "re"):
+			routingEngines++
+		case strings.HasPrefix(name, "fpc"):
+			facts.VirtualChassis = true
+		case strings.HasPrefix(name, "node"):
+			facts.Cluster = true
+		}
+		if software == nil {
+			software = &version.MultiRoutingEngineItem[i].SoftwareInformation
+		}
+	}
+	facts.MultiRE = routingEngines > 1
+	if software == nil {
+		return fmt.Errorf("show version: no software information")
+	}
+	facts.Hostname = strings.TrimSpace(software.HostName)
+	facts.Model = strings.TrimSpace(software.ProductModel)
+	facts.Version = software.Version()
+
+	// The serial number is left out when the chassis can't be read.
+	if inventory, err := ReadChassisInventory(run); err == nil && len(inventory) > 0 {
+		facts.SerialNumber = strings.TrimSpace(inventory[0].Chassis.SerialNumber)
+		if facts.Model == "" {
+			facts.Model = strings.TrimSpace(inventory[0].Chassis.Description)
+		}
+	}
+	return nil
+}
+
+var textFactPatterns = map[string]map[string]*regexp.Regexp{
+	PlatformIOSXR: {
+		"version": regexp.MustCompile(`Version\s+([\w.()-]+)`),
+		"model":   regexp.MustCompile(`(?im)^cisco\s+(\S+(?: Series)?)\s.*processor`),
+	},
+	PlatformNXOS: {
+		"version":  regexp.MustCompile(`(?m)^\s*(?:NXOS|system):\s+version\s+(\S+)`),
+		"model":    regexp.MustCompile(`(?m)^\s*cisco\s+(.+?)\s+[Cc]hassis`),
+		"hostname": regexp.MustCompile(`Device name:\s+(\S+)`),
+		"serial":   regexp.MustCompile(`Processor Board ID\s+(\S+)`),
+	},
+	PlatformEOS: {
+		"version": regexp.MustCompile(`Software image version:\s+(\S+)`),
+		"model":   regexp.MustCompile(`Arista\s+(\S+)`),
+		"serial":  regexp.MustCompile(`Serial number:\s+(\S+)`),
+	},
+}
+
+func textFacts(output string, facts *DeviceFacts) {
+	fact := func(name string) string {
+		pattern := textFactPatterns[facts.Platform][name]
+		if pattern == nil {
+			return ""
+		}
+		if m := pattern.FindStringSubmatch(output); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+		return ""
+	}
+	facts.Version = fact("version")
+	facts.Model = fact("model")
+	facts.Hostname = fact("hostname")
+	facts.SerialNumber = fact("serial")
+}
+
+// netconfFacts returns the facts go-junos reads on connect and the
+// capabilities of the NETCONF hello.
+func netconfFacts(session *junos.Junos) *DeviceFacts {
+	facts := &DeviceFacts{
+		Platform: PlatformJunos,
+		Hostname: session.Hostname,
+		MultiRE:  session.RoutingEngines > 1,
+	}
+	if len(session.Platform) > 0 {
+		facts.Model = session.Platform[0].Model
+		facts.Version = session.Platform[0].Version
+	}
+	if session.Session != nil {
+		facts.Capabilities = session.Session.ServerCapabilities
+	}
+	return facts
+}
+
+//NetconfRunner ... Returns a Runner running the commands over the NETCONF session
+//
+// The XML of "| display xml" is asked for with the command RPC.
+func NetconfRunner(session *junos.Junos) Runner {
+	return func(command string) (string, error) {
+		if strings.HasSuffix(command, " | display xml") {
+			output, err := session.Command(strings.TrimSuffix(command, " | display xml"), "xml")
+			return string(rpcReply(output)), err
+		}
+		return session.Command(command, "text")
+	}
+}
+
+var versionNumbers = regexp.MustCompile(`[0-9]+`)
+
+//VersionAtLeast ... Reports whether the version is the given one or a later one, e.g. "18.1" or "18.1R3"
+//
+// Versions are compared number by number, 18.1R3-S2 is 18, 1, 3 and 2.
+func (f *DeviceFacts) VersionAtLeast(min string) bool {
+	have, want := versionNumbers.FindAllString(f.Version, -1), versionNumbers.FindAllString(min, -1)
+	for i := range want {
+		if i >= len(have) {
+			return false
+		}
+		h, _ := strconv.Atoi(have[i])
+		w, _ := strconv.Atoi(want[i])
+		if h != w {
+			return h > w
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package networkapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// filesRunner returns a runner answering the commands with the captured
+// output in the files under testdata, the other commands failing.
+func filesRunner(t *testing.T, files map[string]string) Runner {
+	return func(command string) (string, error) {
+		name, ok := files[command]
+		if !ok {
+			return "", fmt.Errorf("%s: error: syntax error", command)
+		}
+		output, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output), nil
+	}
+}
+
+func TestDetectFacts(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  *DeviceFacts
+	}{
+		{"junos", map[string]string{
+			"show version":                        "junos/version.txt",
+			"show version | display xml":          "junos/version.xml",
+			"show chassis hardware | display xml": "junos/chassis-hardware.xml",
+		}, &DeviceFacts{Platform: PlatformJunos, Hostname: "edge-1", Model: "mx204", Version: "21.4R3.15", SerialNumber: "WS3718AF0123"}},
+		// The version is the one of the packages before 15.1, the serial
+		// number is left out when the chassis can't be read.
+		{"junos 12.3", map[string]string{
+			"show version":               "junos/version.txt",
+			"show version | display xml": "junos/version-12.3.xml",
+		}, &DeviceFacts{Platform: PlatformJunos, Hostname: "legacy-1", Model: "mx80", Version: "12.3R6.6"}},
+		{"virtual chassis", map[string]string{
+			"show version":               "junos/version-vc.txt",
+			"show version | display xml": "junos/version-vc.xml",
+		}, &DeviceFacts{
+			Platform: PlatformJunos, Hostname: "access-1", Model: "ex4300-48t", Version: "21.4R3.15",
+			RoutingEngines: []RoutingEngineFacts{{Name: "fpc0"}, {Name: "fpc1"}},
+			VirtualChassis: true,
+		}},
+		{"iosxr", map[string]string{
+			"show version": "iosxr/version.txt",
+		}, &DeviceFacts{Platform: PlatformIOSXR, Model: "NCS-5500", Version: "7.5.2"}},
+	}
+	for _, tt := range tests {
+		got, err := DetectFacts(filesRunner(t, tt.files))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DetectFacts() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	errors := map[string]map[string]string{
+		"unknown platform": {"show version": "iosxr/bgp.txt"},
+		"no xml":           {"show version": "junos/version.txt"},
+		"no software":      {"show version": "junos/version.txt", "show version | display xml": "junos/lldp.xml"},
+	}
+	for name, files := range errors {
+		if facts, err := DetectFacts(filesRunner(t, files)); err == nil {
+			t.Errorf("%s: DetectFacts() = %+v, want an error", name, facts)
+		}
+	}
+}
+
+func TestDetectFactsOnConnect(t *testing.T) {
+	srv, c := testSSH(t)
+	c.AutoDetect = true
+	read := func(name string) string {
+		output, err := os.ReadFile(filepath.Join("testdata", "junos", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output)
+	}
+
+	// The detection fails on the first connection, and is retried on the
+	// next one over the same connection.
+	srv.Handle("show version", read("version.txt"))
+	if _, err := c.RunSSH("show system uptime"); err == nil {
+		t.Fatal("RunSSH() succeeded without the facts")
+	}
+	if facts := c.Facts(); facts != nil {
+		t.Fatalf("Facts() = %+v after a failed detection", facts)
+	}
+	srv.Handle("show version | display xml", read("version.xml"))
+	srv.Handle("show system uptime", "Current time: 2023-10-19 10:40:42 UTC\n")
+	if _, err := c.RunSSH("show system uptime"); err != nil {
+		t.Fatal(err)
+	}
+	if facts := c.Facts(); facts == nil || facts.Hostname != "edge-1" || facts.Version != "21.4R3.15" {
+		t.Fatalf("Facts() = %+v, want those of edge-1", facts)
+	}
+
+	// A failed detection clears the facts.
+	srv.Handle("show version", "")
+	if _, err := c.DetectFacts(); err == nil {
+		t.Fatal("DetectFacts() succeeded")
+	}
+	if facts := c.Facts(); facts != nil {
+		t.Errorf("Facts() = %+v after a failed detection", facts)
+	}
+}
@@ -0,0 +1,82 @@
+package networkapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+type firewallCounterXML struct {
+	Name    string `xml:"counter-name"`
+	Packets uint64 `xml:"packet-count"`
+	Bytes   uint64 `xml:"byte-count"`
+}
+
+type firewallSSH struct {
+	Filters []struct {
+		Name     string               `xml:"filter-name"`
+		Counters []firewallCounterXML `xml:"counter"`
+		Policers []struct {
+			Name    string `xml:"policer-name"`
+			Packets uint64 `xml:"packet-count"`
+			Bytes   uint64 `xml:"byte-count"`
+		} `xml:"policer"`
+	} `xml:"firewall-information>filter-information"`
+}
+
+// filterName matches the names of firewall filters, those Junos gives
+// interface-specific filters holding the interface, "police-in-ge-0/0/5.0-i".
+var filterName = regexp.MustCompile(`^[\w./:-]+$`)
+
+//GetFirewallCountersSSH ...Returns the counters and policers of the firewall filters, of every filter when filter is empty
+func (c *Client) GetFirewallCountersSSH(session *ssh.Session, filter string) ([]FirewallFilter, error) {
+	return ReadFirewallCounters(sessionRunner(session), filter)
+}
+
+//GetFirewallCounters ...Returns the counters and policers of the firewall filters, of every filter when filter is empty
+func (c *Client) GetFirewallCounters(session *junos.Junos, filter string) ([]FirewallFilter, error) {
+	return ReadFirewallCounters(NetconfRunner(session), filter)
+}
+
+//ReadFirewallCounters ... Returns the counters and policers of the firewall filters of a Junos device
+//
+// The counters are read from show firewall, or show firewall filter when a
+// filter is named. The policers count the packets they dropped.
+func ReadFirewallCounters(run Runner, filter string) ([]FirewallFilter, error) {
+
+	command := "show firewall"
+	if filter != "" {
+		if !filterName.MatchString(filter) {
+			return nil, fmt.Errorf("invalid firewall filter %q", filter)
+		}
+		command += " filter " + filter
+	}
+	var reply firewallSSH
+	if err := junosXML(run, command, &reply); err != nil {
+		return nil, err
+	}
+
+	filters := make([]FirewallFilter, 0, len(reply.Filters))
+	for _, f := range reply.Filters {
+		counters := FirewallFilter{Name: strings.TrimSpace(f.Name)}
+		for _, c := range f.Counters {
+			counters.Counters = append(counters.Counters, FirewallCounter{
+				Name:    strings.TrimSpace(c.Name),
+				Packets: c.Packets,
+				Bytes:   c.Bytes,
+			})
+		}
+		for _, p := range f.Policers {
+			counters.Policers = append(counters.Policers, FirewallCounter{
+				Name:    strings.TrimSpace(p.Name),
+				Packets: p.Packets,
+				Bytes:   p.Bytes,
+			})
+		}
+		filters = append(filters, counters)
+	}
+	return filters, nil
+}
@@ -0,0 +1,60 @@
+package networkapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadFirewallCounters(t *testing.T) {
+	protectRE := FirewallFilter{
+		Name: "protect-re",
+		Counters: []FirewallCounter{
+			{Name: "bgp-accepted", Packets: 482117, Bytes: 36897441},
+			{Name: "denied", Packets: 1532, Bytes: 91920},
+			{Name: "ssh-accepted", Packets: 15234, Bytes: 1823456},
+		},
+		Policers: []FirewallCounter{
+			{Name: "icmp-1m-icmp", Packets: 12, Bytes: 1008},
+		},
+	}
+	customerA := FirewallFilter{
+		Name:     "customer-a-in-ge-0/0/5.0-i",
+		Counters: []FirewallCounter{{Name: "customer-a-in-ge-0/0/5.0-i", Packets: 8589934600, Bytes: 11630771392512}},
+		Policers: []FirewallCounter{{Name: "police-100m-ge-0/0/5.0-inet-i", Packets: 264068, Bytes: 396103040}},
+	}
+	tests := []struct {
+		filter string
+		want   []FirewallFilter
+		err    bool
+	}{
+		{"", []FirewallFilter{
+			{Name: "__default_bpdu_filter__"},
+			protectRE,
+			customerA,
+		}, false},
+		{"protect-re", []FirewallFilter{protectRE}, false},
+		// Interface-specific filters are named after the interface.
+		{"customer-a-in-ge-0/0/5.0-i", []FirewallFilter{customerA}, false},
+		{"protect-re | save /var/tmp/x", nil, true},
+	}
+	for _, tt := range tests {
+		var commands []string
+		run := func(command string) (string, error) {
+			commands = append(commands, command)
+			return fixtureRunner(t, PlatformJunos)(command)
+		}
+		got, err := ReadFirewallCounters(run, tt.filter)
+		if tt.err {
+			if err == nil || len(commands) > 0 {
+				t.Errorf("ReadFirewallCounters(%q) = %+v, %v running %q, want an error", tt.filter, got, err, commands)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ReadFirewallCounters(%q): %v", tt.filter, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadFirewallCounters(%q) = %+v, want %+v", tt.filter, got, tt.want)
+		}
+	}
+}
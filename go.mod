@@ -1,12 +1,18 @@
 module github.com/kgrvamsi/networkapi
 
-go 1.15
+go 1.18
 
 require (
-	github.com/Juniper/go-netconf v0.1.1 // indirect
 	github.com/kgrvamsi/go-junos v0.0.0-20190905233430-8639bb458d4e
+	github.com/pkg/sftp v1.13.9
+	github.com/ziutek/telnet v0.0.0-20180329124119-c3b780dc415b
+	golang.org/x/crypto v0.31.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/Juniper/go-netconf v0.1.1 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/scottdware/go-rested v0.0.0-20160313143639-93e152ef32a6 // indirect
-	github.com/ziutek/telnet v0.0.0-20180329124119-c3b780dc415b // indirect
-	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
-	golang.org/x/sys v0.0.0-20210124154548-22da62e12c0c // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -0,0 +1,242 @@
+package networkapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+//AlarmSeverity ... Severity of an alarm, a major alarm being more severe than a minor one
+type AlarmSeverity int
+
+// Severities of the alarms, as Junos classes them.
+const (
+	AlarmUnknown AlarmSeverity = iota
+	AlarmMinor
+	AlarmMajor
+)
+
+var alarmSeverities = map[AlarmSeverity]string{
+	AlarmUnknown: "unknown",
+	AlarmMinor:   "minor",
+	AlarmMajor:   "major",
+}
+
+func (s AlarmSeverity) String() string {
+	if name, ok := alarmSeverities[s]; ok {
+		return name
+	}
+	return "AlarmSeverity(" + strconv.Itoa(int(s)) + ")"
+}
+
+//MarshalText ... Marshals the severity as its name, "major"
+func (s AlarmSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+//UnmarshalText ... Unmarshals the name of a severity, without case
+func (s *AlarmSeverity) UnmarshalText(text []byte) error {
+	for severity, name := range alarmSeverities {
+		if strings.EqualFold(string(text), name) {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown alarm severity %q", text)
+}
+
+type alarmInformationSSH struct {
+	Alarms []struct {
+		Time        JunosTime `xml:"alarm-time"`
+		Class       string    `xml:"alarm-class"`
+		Description string    `xml:"alarm-description"`
+		Type        string    `xml:"alarm-type"`
+	} `xml:"alarm-information>alarm-detail"`
+}
+
+type environmentSSH struct {
+	Items []struct {
+		Name        string           `xml:"name"`
+		Class       string           `xml:"class"`
+		Status      string           `xml:"status"`
+		Temperature JunosTemperature `xml:"temperature"`
+		Comment     string           `xml:"comment"`
+	} `xml:"environment-information>environment-item"`
+}
+
+//GetChassisAlarmsSSH ...Returns the active chassis alarms
+func (c *Client) GetChassisAlarmsSSH(session *ssh.Session) ([]Alarm, error) {
+	return ReadChassisAlarms(sessionRunner(session))
+}
+
+//GetChassisAlarms ...Returns the active chassis alarms
+func (c *Client) GetChassisAlarms(session *junos.Junos) ([]Alarm, error) {
+	return ReadChassisAlarms(NetconfRunner(session))
+}
+
+//ReadChassisAlarms ... Returns the active chassis alarms of a Junos device
+func ReadChassisAlarms(run Runner) ([]Alarm, error) {
+	return readAlarms(run, "show chassis alarms")
+}
+
+//GetSystemAlarmsSSH ...Returns the active system alarms, such as a missing rescue configuration
+func (c *Client) GetSystemAlarmsSSH(session *ssh.Session) ([]Alarm, error) {
+	return ReadSystemAlarms(sessionRunner(session))
+}
+
+//GetSystemAlarms ...Returns the active system alarms, such as a missing rescue configuration
+func (c *Client) GetSystemAlarms(session *junos.Junos) ([]Alarm, error) {
+	return ReadSystemAlarms(NetconfRunner(session))
+}
+
+//ReadSystemAlarms ... Returns the active system alarms of a Junos device
+func ReadSystemAlarms(run Runner) ([]Alarm, error) {
+	return readAlarms(run, "show system alarms")
+}
+
+func readAlarms(run Runner, command string) ([]Alarm, error) {
+	var reply alarmInformationSSH
+	if err := junosXML(run, command, &reply); err != nil {
+		return nil, err
+	}
+
+	var alarms []Alarm
+	for _, a := range reply.Alarms {
+		alarm := Alarm{
+			Severity:    alarmSeverity(a.Class),
+			Type:        strings.TrimSpace(a.Type),
+			Description: strings.TrimSpace(a.Description),
+		}
+		if seconds := strings.TrimSpace(a.Time.Seconds); seconds != "" {
+			alarm.Time = time.Unix(int64(parseInt(seconds)), 0).UTC()
+		}
+		alarms = append(alarms, alarm)
+	}
+	return alarms, nil
+}
+
+func alarmSeverity(class string) AlarmSeverity {
+	switch strings.ToLower(strings.TrimSpace(class)) {
+	case "major":
+		return AlarmMajor
+	case "minor":
+		return AlarmMinor
+	}
+	return AlarmUnknown
+}
+
+//GetEnvironmentSSH ...Returns the status of the temperature sensors, fans and power supplies
+func (c *Client) GetEnvironmentSSH(session *ssh.Session) ([]EnvironmentItem, error) {
+	return ReadEnvironment(sessionRunner(session))
+}
+
+//GetEnvironment ...Returns the status of the temperature sensors, fans and power supplies
+func (c *Client) GetEnvironment(session *junos.Junos) ([]EnvironmentItem, error) {
+	return ReadEnvironment(NetconfRunner(session))
+}
+
+//ReadEnvironment ... Returns the status of the temperature sensors, fans and power supplies of a Junos device
+//
+// Junos only names the class, Temp, Fans or Power, on the first item of each
+// class, the items after it are given the same class.
+func ReadEnvironment(run Runner) ([]EnvironmentItem, error) {
+	var reply environmentSSH
+	if err := junosXML(run, "show chassis environment", &reply); err != nil {
+		return nil, err
+	}
+
+	var items []EnvironmentItem
+	class := ""
+	for _, i := range reply.Items {
+		if c := strings.TrimSpace(i.Class); c != "" {
+			class = c
+		}
+		items = append(items, EnvironmentItem{
+			Name:        strings.TrimSpace(i.Name),
+			Class:       class,
+			Status:      strings.TrimSpace(i.Status),
+			Temperature: celsius(i.Temperature),
+			Comment:     strings.TrimSpace(i.Comment),
+		})
+	}
+	return items, nil
+}
+
+// celsius returns the temperature in degrees Celsius, from its attribute or
+// the text "34 degrees C / 93 degrees F".
+func celsius(t JunosTemperature) float64 {
+	if t.Celsius != "" {
+		return parseFloat(t.Celsius)
+	}
+	if fields := strings.Fields(t.Value); len(fields) > 0 {
+		return parseFloat(fields[0])
+	}
+	return 0
+}
+
+//GetRoutingEnginesSSH ...Returns the CPU, memory and temperature of the routing engines
+func (c *Client) GetRoutingEnginesSSH(session *ssh.Session) ([]RoutingEngineStatus, error) {
+	return ReadRoutingEngines(sessionRunner(session))
+}
+
+//GetRoutingEngines ...Returns the CPU, memory and temperature of the routing engines
+func (c *Client) GetRoutingEngines(session *junos.Junos) ([]RoutingEngineStatus, error) {
+	return ReadRoutingEngines(NetconfRunner(session))
+}
+
+//ReadRoutingEngines ... Returns the CPU, memory and temperature of the routing engines of a Junos device
+//
+// Routing engines are named re0 and re1 after their slot, and after the
+// member or node of virtual chassis and clusters.
+func ReadRoutingEngines(run Runner) ([]RoutingEngineStatus, error) {
+	var reply RouteEngineInformationSSH
+	if err := junosXML(run, "show chassis routing-engine", &reply); err != nil {
+		return nil, err
+	}
+
+	var engines []RoutingEngineStatus
+	for _, engine := range reply.RouteEngine {
+		engines = append(engines, routingEngineStatus("re"+strings.TrimSpace(engine.Slot), engine))
+	}
+	for _, item := range reply.MultiRoutingEngineItem {
+		for _, engine := range item.RouteEngine {
+			engines = append(engines, routingEngineStatus(strings.TrimSpace(item.ReName), engine))
+		}
+	}
+	return engines, nil
+}
+
+func routingEngineStatus(name string, engine RouteEngine) RoutingEngineStatus {
+	status := RoutingEngineStatus{
+		Name:              name,
+		Model:             strings.TrimSpace(engine.Model),
+		Status:            strings.TrimSpace(engine.Status),
+		Mastership:        strings.ToLower(strings.TrimSpace(engine.MastershipState)),
+		CPUUser:           parseInt(engine.CPUUser),
+		CPUBackground:     parseInt(engine.CPUBackground),
+		CPUSystem:         parseInt(engine.CPUSystem),
+		CPUInterrupt:      parseInt(engine.CPUInterrupt),
+		CPUIdle:           parseInt(engine.CPUIdle),
+		MemoryUtilization: parseInt(engine.MemoryBufferUtilization),
+		Temperature:       celsius(engine.Temperature),
+		CPUTemperature:    celsius(engine.CPUTemperature),
+		LoadAverage: [3]float64{
+			parseFloat(engine.LoadAverageOne),
+			parseFloat(engine.LoadAverageFive),
+			parseFloat(engine.LoadAverageFifteen),
+		},
+		Uptime: junosDuration(engine.UpTime),
+	}
+	if strings.TrimSpace(engine.CPUIdle) != "" {
+		status.CPUUtilization = 100 - status.CPUIdle
+	}
+	// The DRAM size is "16384 MB" or "16384".
+	if fields := strings.Fields(engine.MemoryDRAMSize); len(fields) > 0 {
+		status.MemoryTotal = parseInt(fields[0])
+	}
+	return status
+}
@@ -0,0 +1,118 @@
+package networkapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadAlarms(t *testing.T) {
+	tests := []struct {
+		name string
+		read func(Runner) ([]Alarm, error)
+		run  Runner
+		want []Alarm
+	}{
+		{"chassis", ReadChassisAlarms, fixtureRunner(t, PlatformJunos), []Alarm{
+			{Time: time.Unix(1697712042, 0).UTC(), Severity: AlarmMajor, Type: "Chassis", Description: "PEM 1 Not OK"},
+			{Time: time.Unix(1697530325, 0).UTC(), Severity: AlarmMinor, Type: "Chassis", Description: "Backup RE Active"},
+		}},
+		{"system", ReadSystemAlarms, fixtureRunner(t, PlatformJunos), []Alarm{
+			{Time: time.Unix(1697530325, 0).UTC(), Severity: AlarmMinor, Type: "Configuration", Description: "Rescue configuration is not set"},
+		}},
+		{"none", ReadChassisAlarms, fileRunner(t, PlatformJunos, "show chassis alarms | display xml", "alarms-none.xml"), nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.read(tt.run)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: alarms = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlarmSeverityText(t *testing.T) {
+	for _, severity := range []AlarmSeverity{AlarmUnknown, AlarmMinor, AlarmMajor} {
+		text, err := severity.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got AlarmSeverity
+		if err := got.UnmarshalText(text); err != nil || got != severity {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, severity)
+		}
+	}
+	var s AlarmSeverity
+	if err := s.UnmarshalText([]byte("critical")); err == nil {
+		t.Error(`UnmarshalText("critical") succeeded`)
+	}
+}
+
+func TestReadEnvironment(t *testing.T) {
+	want := []EnvironmentItem{
+		{Name: "PEM 0", Class: "Power", Status: "OK"},
+		{Name: "PEM 1", Class: "Power", Status: "Check", Comment: "No input power"},
+		{Name: "Routing Engine 0", Class: "Temp", Status: "OK", Temperature: 38},
+		{Name: "Routing Engine 0 CPU", Class: "Temp", Status: "OK", Temperature: 45},
+		{Name: "Routing Engine 1", Class: "Temp", Status: "Absent"},
+		{Name: "Fan Tray 0 Fan 1", Class: "Fans", Status: "OK", Comment: "Spinning at normal speed"},
+		{Name: "Fan Tray 0 Fan 2", Class: "Fans", Status: "OK", Comment: "Spinning at high speed"},
+	}
+	got, err := ReadEnvironment(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadEnvironment() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadRoutingEngines(t *testing.T) {
+	tests := []struct {
+		name string
+		run  Runner
+		want []RoutingEngineStatus
+	}{
+		{"dual", fixtureRunner(t, PlatformJunos), []RoutingEngineStatus{
+			{
+				Name: "re0", Model: "RE-S-1800x4", Status: "OK", Mastership: "master",
+				CPUUser: 3, CPUSystem: 2, CPUInterrupt: 1, CPUIdle: 94, CPUUtilization: 6,
+				MemoryTotal: 16384, MemoryUtilization: 21, Temperature: 38, CPUTemperature: 45,
+				LoadAverage: [3]float64{0.12, 0.08, 0.05}, Uptime: 181717 * time.Second,
+			},
+			{
+				Name: "re1", Model: "RE-S-1800x4", Status: "OK", Mastership: "backup",
+				CPUSystem: 1, CPUIdle: 99, CPUUtilization: 1,
+				MemoryTotal: 16384, MemoryUtilization: 12, Temperature: 36, CPUTemperature: 41,
+				Uptime: 181741 * time.Second,
+			},
+		}},
+		{"cluster", fileRunner(t, PlatformJunos, "show chassis routing-engine | display xml", "routing-engine-cluster.xml"), []RoutingEngineStatus{
+			{
+				Name: "node0", Model: "RE-SRX345", Status: "OK",
+				CPUUser: 9, CPUSystem: 6, CPUInterrupt: 1, CPUIdle: 84, CPUUtilization: 16,
+				MemoryTotal: 4096, MemoryUtilization: 63, Temperature: 42, CPUTemperature: 47,
+				LoadAverage: [3]float64{0.31, 0.27, 0.25}, Uptime: 181717 * time.Second,
+			},
+			{
+				Name: "node1", Model: "RE-SRX345", Status: "OK",
+				CPUUser: 2, CPUSystem: 3, CPUIdle: 95, CPUUtilization: 5,
+				MemoryTotal: 4096, MemoryUtilization: 58, Temperature: 40, CPUTemperature: 44,
+				LoadAverage: [3]float64{0.08, 0.06, 0.04}, Uptime: 181741 * time.Second,
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadRoutingEngines(tt.run)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadRoutingEngines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package networkapi
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+type ospfNeighborXML struct {
+	Address       string    `xml:"neighbor-address"`
+	Interface     string    `xml:"interface-name"`
+	State         string    `xml:"ospf-neighbor-state"`
+	RouterID      string    `xml:"neighbor-id"`
+	Priority      string    `xml:"neighbor-priority"`
+	ActivityTimer string    `xml:"activity-timer"`
+	Area          string    `xml:"ospf-area"`
+	DRAddress     string    `xml:"dr-address"`
+	BDRAddress    string    `xml:"bdr-address"`
+	DRID          string    `xml:"dr-id"`
+	BDRID         string    `xml:"bdr-id"`
+	UpTime        JunosTime `xml:"neighbor-up-time"`
+}
+
+type ospfNeighborsSSH struct {
+	Neighbors  []ospfNeighborXML `xml:"ospf-neighbor-information>ospf-neighbor"`
+	Neighbors3 []ospfNeighborXML `xml:"ospf3-neighbor-information>ospf3-neighbor"`
+}
+
+type ospfInterfaceXML struct {
+	Name       string    `xml:"interface-name"`
+	State      string    `xml:"ospf-interface-state"`
+	Area       string    `xml:"ospf-area"`
+	Type       string    `xml:"interface-type"`
+	DRID       string    `xml:"dr-id"`
+	BDRID      string    `xml:"bdr-id"`
+	DRAddress  string    `xml:"dr-address"`
+	BDRAddress string    `xml:"bdr-address"`
+	Neighbors  string    `xml:"neighbor-count"`
+	Cost       string    `xml:"interface-cost"`
+	Passive    *struct{} `xml:"passive"`
+}
+
+type ospfInterfacesSSH struct {
+	Interfaces  []ospfInterfaceXML `xml:"ospf-interface-information>ospf-interface"`
+	Interfaces3 []ospfInterfaceXML `xml:"ospf3-interface-information>ospf3-interface"`
+}
+
+type isisAdjacencySSH struct {
+	Adjacencies []struct {
+		Interface string `xml:"interface-name"`
+		System    string `xml:"system-name"`
+		Level     string `xml:"level"`
+		State     string `xml:"adjacency-state"`
+		HoldTime  string `xml:"holdtime"`
+		SNPA      string `xml:"snpa"`
+	} `xml:"isis-adjacency-information>isis-adjacency"`
+}
+
+type isisDatabaseSSH struct {
+	Databases []struct {
+		Level   string `xml:"level"`
+		Entries []struct {
+			LSPID      string `xml:"lsp-id"`
+			Sequence   string `xml:"sequence-number"`
+			Checksum   string `xml:"checksum"`
+			Lifetime   string `xml:"remaining-lifetime"`
+			Attributes string `xml:"lsp-attributes"`
+		} `xml:"isis-database-entry"`
+	} `xml:"isis-database-information>isis-database"`
+}
+
+// ospfCommand returns the command of OSPF, version 2, or OSPFv3, version 3.
+func ospfCommand(version int, command string) (string, error) {
+	switch version {
+	case 2:
+		return "show ospf " + command, nil
+	case 3:
+		return "show ospf3 " + command, nil
+	}
+	return "", fmt.Errorf("unknown OSPF version %d", version)
+}
+
+//GetOSPFNeighborsSSH ...Returns the OSPF neighbors, version is 2 for OSPF and 3 for OSPFv3
+func (c *Client) GetOSPFNeighborsSSH(session *ssh.Session, version int) ([]OSPFNeighbor, error) {
+	return ReadOSPFNeighbors(sessionRunner(session), version)
+}
+
+//GetOSPFNeighbors ...Returns the OSPF neighbors, version is 2 for OSPF and 3 for OSPFv3
+func (c *Client) GetOSPFNeighbors(session *junos.Junos, version int) ([]OSPFNeighbor, error) {
+	return ReadOSPFNeighbors(NetconfRunner(session), version)
+}
+
+//ReadOSPFNeighbors ... Returns the OSPF neighbors of a Junos device, version is 2 for OSPF and 3 for OSPFv3
+func ReadOSPFNeighbors(run Runner, version int) ([]OSPFNeighbor, error) {
+
+	command, err := ospfCommand(version, "neighbor detail")
+	if err != nil {
+		return nil, err
+	}
+	var reply ospfNeighborsSSH
+	if err := junosXML(run, command, &reply); err != nil {
+		return nil, err
+	}
+
+	var neighbors []OSPFNeighbor
+	for _, n := range append(reply.Neighbors, reply.Neighbors3...) {
+		neighbor := OSPFNeighbor{
+			RouterID:  strings.TrimSpace(n.RouterID),
+			Interface: strings.TrimSpace(n.Interface),
+			State:     strings.TrimSpace(n.State),
+			Area:      strings.TrimSpace(n.Area),
+			Priority:  parseInt(n.Priority),
+			DR:        designatedRouter(n.DRAddress, n.DRID),
+			BDR:       designatedRouter(n.BDRAddress, n.BDRID),
+			DeadTime:  time.Duration(parseInt(n.ActivityTimer)) * time.Second,
+			Uptime:    junosDuration(n.UpTime),
+		}
+		neighbor.Address, _ = netip.ParseAddr(strings.TrimSpace(n.Address))
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
+
+//GetOSPFInterfacesSSH ...Returns the interfaces running OSPF, version is 2 for OSPF and 3 for OSPFv3
+func (c *Client) GetOSPFInterfacesSSH(session *ssh.Session, version int) ([]OSPFInterface, error) {
+	return ReadOSPFInterfaces(sessionRunner(session), version)
+}
+
+//GetOSPFInterfaces ...Returns the interfaces running OSPF, version is 2 for OSPF and 3 for OSPFv3
+func (c *Client) GetOSPFInterfaces(session *junos.Junos, version int) ([]OSPFInterface, error) {
+	return ReadOSPFInterfaces(NetconfRunner(session), version)
+}
+
+//ReadOSPFInterfaces ... Returns the interfaces running OSPF of a Junos device, version is 2 for OSPF and 3 for OSPFv3
+func ReadOSPFInterfaces(run Runner, version int) ([]OSPFInterface, error) {
+
+	command, err := ospfCommand(version, "interface detail")
+	if err != nil {
+		return nil, err
+	}
+	var reply ospfInterfacesSSH
+	if err := junosXML(run, command, &reply); err != nil {
+		return nil, err
+	}
+
+	var interfaces []OSPFInterface
+	for _, i := range append(reply.Interfaces, reply.Interfaces3...) {
+		interfaces = append(interfaces, OSPFInterface{
+			Name:      strings.TrimSpace(i.Name),
+			State:     strings.TrimSpace(i.State),
+			Area:      strings.TrimSpace(i.Area),
+			Type:      strings.TrimSpace(i.Type),
+			DR:        designatedRouter(i.DRAddress, i.DRID),
+			BDR:       designatedRouter(i.BDRAddress, i.BDRID),
+			Neighbors: parseInt(i.Neighbors),
+			Cost:      parseInt(i.Cost),
+			Passive:   i.Passive != nil,
+		})
+	}
+	return interfaces, nil
+}
+
+// designatedRouter returns the address of the DR or BDR, or its router ID
+// when the address isn't known, and "" on the links without one, which Junos
+// shows as 0.0.0.0.
+func designatedRouter(address, id string) string {
+	if dr := firstField(address, id); dr != "0.0.0.0" {
+		return dr
+	}
+	return ""
+}
+
+// firstField returns the first of the values that isn't empty.
+func firstField(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+//GetISISAdjacenciesSSH ...Returns the IS-IS adjacencies
+func (c *Client) GetISISAdjacenciesSSH(session *ssh.Session) ([]ISISAdjacency, error) {
+	return ReadISISAdjacencies(sessionRunner(session))
+}
+
+//GetISISAdjacencies ...Returns the IS-IS adjacencies
+func (c *Client) GetISISAdjacencies(session *junos.Junos) ([]ISISAdjacency, error) {
+	return ReadISISAdjacencies(NetconfRunner(session))
+}
+
+//ReadISISAdjacencies ... Returns the IS-IS adjacencies of a Junos device
+func ReadISISAdjacencies(run Runner) ([]ISISAdjacency, error) {
+	var reply isisAdjacencySSH
+	if err := junosXML(run, "show isis adjacency", &reply); err != nil {
+		return nil, err
+	}
+
+	var adjacencies []ISISAdjacency
+	for _, a := range reply.Adjacencies {
+		adjacencies = append(adjacencies, ISISAdjacency{
+			Interface: strings.TrimSpace(a.Interface),
+			System:    strings.TrimSpace(a.System),
+			Level:     parseInt(a.Level),
+			State:     strings.TrimSpace(a.State),
+			SNPA:      strings.TrimSpace(a.SNPA),
+			HoldTime:  time.Duration(parseInt(a.HoldTime)) * time.Second,
+		})
+	}
+	return adjacencies, nil
+}
+
+//GetISISDatabaseSSH ...Returns the summary of the link state PDUs of the IS-IS database
+func (c *Client) GetISISDatabaseSSH(session *ssh.Session) ([]ISISLSP, error) {
+	return ReadISISDatabase(sessionRunner(session))
+}
+
+//GetISISDatabase ...Returns the summary of the link state PDUs of the IS-IS database
+func (c *Client) GetISISDatabase(session *junos.Junos) ([]ISISLSP, error) {
+	return ReadISISDatabase(NetconfRunner(session))
+}
+
+//ReadISISDatabase ... Returns the summary of the link state PDUs of the IS-IS database of a Junos device
+func ReadISISDatabase(run Runner) ([]ISISLSP, error) {
+	var reply isisDatabaseSSH
+	if err := junosXML(run, "show isis database", &reply); err != nil {
+		return nil, err
+	}
+
+	var lsps []ISISLSP
+	for _, database := range reply.Databases {
+		for _, entry := range database.Entries {
+			// The sequence number and checksum are hexadecimal, 0x2f.
+			sequence, _ := strconv.ParseUint(strings.TrimSpace(entry.Sequence), 0, 64)
+			checksum, _ := strconv.ParseUint(strings.TrimSpace(entry.Checksum), 0, 64)
+			lsps = append(lsps, ISISLSP{
+				Level:      parseInt(database.Level),
+				LSPID:      strings.TrimSpace(entry.LSPID),
+				Sequence:   sequence,
+				Checksum:   checksum,
+				Lifetime:   time.Duration(parseInt(entry.Lifetime)) * time.Second,
+				Attributes: strings.TrimSpace(entry.Attributes),
+			})
+		}
+	}
+	return lsps, nil
+}
@@ -0,0 +1,82 @@
+package networkapi
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadOSPFNeighbors(t *testing.T) {
+	tests := []struct {
+		version int
+		want    []OSPFNeighbor
+	}{
+		{2, []OSPFNeighbor{
+			{Address: netip.MustParseAddr("10.0.0.1"), RouterID: "10.255.0.2", Interface: "ge-0/0/0.0", State: "Full", Area: "0.0.0.0", Priority: 128, DeadTime: 34 * time.Second, Uptime: 8831 * time.Second},
+			{Address: netip.MustParseAddr("10.0.2.2"), RouterID: "10.255.0.4", Interface: "ge-0/0/2.0", State: "Full", Area: "0.0.0.1", Priority: 1, DR: "10.0.2.1", BDR: "10.0.2.2", DeadTime: 38 * time.Second, Uptime: 93771 * time.Second},
+			{Address: netip.MustParseAddr("10.0.0.5"), RouterID: "10.255.0.3", Interface: "ge-0/0/1.0", State: "ExStart", Area: "0.0.0.0", Priority: 128, DeadTime: 31 * time.Second},
+		}},
+		{3, []OSPFNeighbor{
+			{Address: netip.MustParseAddr("fe80::250:56ff:fe8b:1234"), RouterID: "10.255.0.2", Interface: "ge-0/0/0.0", State: "Full", Area: "0.0.0.0", Priority: 128, DeadTime: 33 * time.Second, Uptime: 8820 * time.Second},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ReadOSPFNeighbors(fixtureRunner(t, PlatformJunos), tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadOSPFNeighbors(%d) = %+v, want %+v", tt.version, got, tt.want)
+		}
+	}
+
+	if _, err := ReadOSPFNeighbors(fixtureRunner(t, PlatformJunos), 4); err == nil {
+		t.Error("ReadOSPFNeighbors(4) succeeded")
+	}
+}
+
+func TestReadOSPFInterfaces(t *testing.T) {
+	want := []OSPFInterface{
+		{Name: "ge-0/0/0.0", State: "PtToPt", Area: "0.0.0.0", Type: "P2P", Neighbors: 1, Cost: 10},
+		{Name: "ge-0/0/2.0", State: "DR", Area: "0.0.0.1", Type: "LAN", DR: "10.0.2.1", BDR: "10.0.2.2", Neighbors: 1, Cost: 100},
+		{Name: "lo0.0", State: "DR", Area: "0.0.0.0", Type: "LAN", DR: "10.255.0.1", Passive: true},
+	}
+	got, err := ReadOSPFInterfaces(fixtureRunner(t, PlatformJunos), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadOSPFInterfaces() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadISISAdjacencies(t *testing.T) {
+	want := []ISISAdjacency{
+		{Interface: "ge-0/0/0.0", System: "core-1", Level: 2, State: "Up", HoldTime: 24 * time.Second},
+		{Interface: "ge-0/0/2.0", System: "core-2", Level: 3, State: "Up", SNPA: "0:50:56:8b:56:78", HoldTime: 7 * time.Second},
+		{Interface: "ge-0/0/1.0", System: "0102.5500.0003", Level: 2, State: "Initializing", HoldTime: 26 * time.Second},
+	}
+	got, err := ReadISISAdjacencies(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadISISAdjacencies() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadISISDatabase(t *testing.T) {
+	want := []ISISLSP{
+		{Level: 1, LSPID: "edge-1.00-00", Sequence: 0x2f, Checksum: 0x8a4c, Lifetime: 1023 * time.Second, Attributes: "L1 L2 Attached"},
+		{Level: 2, LSPID: "edge-1.00-00", Sequence: 0x31, Checksum: 0x1b2d, Lifetime: 1101 * time.Second, Attributes: "L1 L2"},
+		{Level: 2, LSPID: "core-1.00-00", Sequence: 0x1a4, Checksum: 0xf3e1, Lifetime: 655 * time.Second, Attributes: "L1 L2"},
+	}
+	got, err := ReadISISDatabase(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadISISDatabase() = %+v, want %+v", got, want)
+	}
+}
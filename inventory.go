@@ -0,0 +1,171 @@
+package networkapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//Device ... Single device entry of the inventory
+type Device struct {
+	Hostname string   `json:"hostname" yaml:"hostname"`
+	Address  string   `json:"address,omitempty" yaml:"address,omitempty"`
+	Username string   `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty"`
+	Site     string   `json:"site,omitempty" yaml:"site,omitempty"`
+	Role     string   `json:"role,omitempty" yaml:"role,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Platform string   `json:"platform,omitempty" yaml:"platform,omitempty"`
+}
+
+//Inventory ... List of devices with the default credentials used to reach them
+type Inventory struct {
+	Username string   `json:"username,omitempty" yaml:"username,omitempty"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty"`
+	Devices  []Device `json:"devices" yaml:"devices"`
+}
+
+//LoadInventory ... Reads the inventory from a JSON or YAML file
+func LoadInventory(filename string) (*Inventory, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var inventory Inventory
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		err = json.Unmarshal(data, &inventory)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &inventory)
+	default:
+		return nil, fmt.Errorf("unsupported inventory format %q", filepath.Ext(filename))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading inventory %s - %s", filename, err)
+	}
+
+	for i, device := range inventory.Devices {
+		if device.Hostname == "" {
+			return nil, fmt.Errorf("inventory %s: device %d has no hostname", filename, i)
+		}
+	}
+
+	return &inventory, nil
+}
+
+//Select ... Returns the devices matching the selector
+//
+// A selector is a comma separated list of terms and a device is selected when
+// it matches any of them. A term is either "all", a hostname glob such as
+// "core-*", or one of "site=<name>", "role=<name>" and "tag=<name>".
+func (i *Inventory) Select(selector string) ([]Device, error) {
+
+	var terms []string
+	for _, term := range strings.Split(selector, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty inventory selector")
+	}
+
+	var selected []Device
+	for _, device := range i.Devices {
+		for _, term := range terms {
+			ok, err := device.matches(term)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				selected = append(selected, device)
+				break
+			}
+		}
+	}
+
+	return selected, nil
+}
+
+//Lookup ... Finds a device by hostname, ignoring case and the domain name
+func (i *Inventory) Lookup(hostname string) (Device, bool) {
+
+	name := ShortHostname(hostname)
+	for _, device := range i.Devices {
+		if ShortHostname(device.Hostname) == name {
+			return device, true
+		}
+	}
+
+	return Device{}, false
+}
+
+//Client ... Returns a Client for the device, falling back to the inventory credentials
+func (i *Inventory) Client(device Device) *Client {
+
+	username, password := device.Username, device.Password
+	if username == "" {
+		username = i.Username
+	}
+	if password == "" {
+		password = i.Password
+	}
+
+	client := NetworkClient(device.Target(), username, password)
+	client.Platform = device.Platform
+	return client
+}
+
+//Target ... Returns the address used to connect to the device
+func (d Device) Target() string {
+	if d.Address != "" {
+		return d.Address
+	}
+	return d.Hostname
+}
+
+func (d Device) matches(term string) (bool, error) {
+
+	if term == "all" {
+		return true, nil
+	}
+
+	if kv := strings.SplitN(term, "=", 2); len(kv) == 2 {
+		switch kv[0] {
+		case "site":
+			return d.Site == kv[1], nil
+		case "role":
+			return d.Role == kv[1], nil
+		case "tag":
+			for _, tag := range d.Tags {
+				if tag == kv[1] {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return false, fmt.Errorf("unknown selector key %q", kv[0])
+	}
+
+	return path.Match(term, d.Hostname)
+}
+
+//ShortHostname ... Returns the hostname in lower case without the domain name, IP addresses are kept whole
+func ShortHostname(hostname string) string {
+	hostname = strings.ToLower(strings.TrimSpace(hostname))
+	if net.ParseIP(hostname) != nil {
+		return hostname
+	}
+	if i := strings.Index(hostname, "."); i > 0 {
+		hostname = hostname[:i]
+	}
+	return hostname
+}
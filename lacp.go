@@ -0,0 +1,158 @@
+package networkapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	junos "github.com/kgrvamsi/go-junos"
+)
+
+type lacpInterfacesSSH struct {
+	Bundles []struct {
+		Name  string `xml:"lag-lacp-header>aggregate-name"`
+		State []struct {
+			Name         string `xml:"name"`
+			Role         string `xml:"lacp-role"`
+			Expired      string `xml:"lacp-expired"`
+			Defaulted    string `xml:"lacp-defaulted"`
+			Distributing string `xml:"lacp-distributing"`
+			Collecting   string `xml:"lacp-collecting"`
+			Synchronized string `xml:"lacp-synchronization"`
+			Timeout      string `xml:"lacp-timeout"`
+			Activity     string `xml:"lacp-activity"`
+		} `xml:"lag-lacp-state"`
+		Info []struct {
+			Name     string `xml:"name"`
+			Role     string `xml:"lacp-role"`
+			SystemID string `xml:"lacp-system-id"`
+			PortKey  string `xml:"lacp-port-key"`
+		} `xml:"lag-lacp-info"`
+		Protocol []struct {
+			Name         string `xml:"name"`
+			ReceiveState string `xml:"lacp-receive-state"`
+			MuxState     string `xml:"lacp-mux-state"`
+		} `xml:"lag-lacp-protocol"`
+	} `xml:"lacp-interface-information-list>lacp-interface-information"`
+}
+
+type aggregatedInterfacesSSH struct {
+	Interfaces []struct {
+		Name         string `xml:"name"`
+		AdminStatus  string `xml:"admin-status"`
+		OperStatus   string `xml:"oper-status"`
+		Speed        string `xml:"speed"`
+		MinimumLinks string `xml:"minimum-links-in-aggregate"`
+	} `xml:"interface-information>physical-interface"`
+}
+
+//GetLACPBundles ...Returns the aggregated ethernet interfaces with the LACP state of their members
+func (c *Client) GetLACPBundles(session *junos.Junos) ([]LACPBundle, error) {
+	return ReadLACPBundles(NetconfRunner(session))
+}
+
+//ReadLACPBundles ... Returns the aggregated ethernet interfaces of a Junos device with the LACP state of their members
+//
+// The bundles are read from show interfaces ae* and their members from show
+// lacp interfaces extensive, bundles without LACP having no members. It runs
+// both commands, so over SSH it takes client.RunSSH.
+func ReadLACPBundles(run Runner) ([]LACPBundle, error) {
+
+	var interfaces aggregatedInterfacesSSH
+	if err := junosXML(run, "show interfaces ae*", &interfaces); err != nil {
+		return nil, err
+	}
+	var lacp lacpInterfacesSSH
+	if err := junosXML(run, "show lacp interfaces extensive", &lacp); err != nil {
+		return nil, err
+	}
+
+	members := make(map[string][]LACPMember)
+	for _, b := range lacp.Bundles {
+		byName := make(map[string]*LACPMember)
+		var names []string
+		member := func(name string) *LACPMember {
+			name = strings.TrimSpace(name)
+			if byName[name] == nil {
+				byName[name] = &LACPMember{Name: name}
+				names = append(names, name)
+			}
+			return byName[name]
+		}
+		for _, s := range b.State {
+			m := member(s.Name)
+			if !strings.EqualFold(strings.TrimSpace(s.Role), "actor") {
+				m.PartnerTimeout = strings.TrimSpace(s.Timeout)
+				continue
+			}
+			m.Collecting = lacpFlag(s.Collecting)
+			m.Distributing = lacpFlag(s.Distributing)
+			m.Synchronized = lacpFlag(s.Synchronized)
+			m.Expired = lacpFlag(s.Expired)
+			m.Defaulted = lacpFlag(s.Defaulted)
+			m.Activity = strings.TrimSpace(s.Activity)
+			m.Timeout = strings.TrimSpace(s.Timeout)
+		}
+		for _, i := range b.Info {
+			m := member(i.Name)
+			if strings.EqualFold(strings.TrimSpace(i.Role), "actor") {
+				m.ActorSystemID, m.ActorKey = strings.TrimSpace(i.SystemID), parseInt(i.PortKey)
+			} else {
+				m.PartnerSystemID, m.PartnerKey = strings.TrimSpace(i.SystemID), parseInt(i.PortKey)
+			}
+		}
+		for _, p := range b.Protocol {
+			m := member(p.Name)
+			m.ReceiveState = strings.TrimSpace(p.ReceiveState)
+			m.MuxState = strings.TrimSpace(p.MuxState)
+		}
+
+		name := strings.TrimSpace(b.Name)
+		for _, n := range names {
+			members[name] = append(members[name], *byName[n])
+		}
+	}
+
+	var bundles []LACPBundle
+	for _, i := range interfaces.Interfaces {
+		bundle := LACPBundle{
+			Name:         strings.TrimSpace(i.Name),
+			AdminStatus:  strings.TrimSpace(i.AdminStatus),
+			OperStatus:   strings.TrimSpace(i.OperStatus),
+			Speed:        parseSpeed(i.Speed),
+			MinimumLinks: parseInt(i.MinimumLinks),
+		}
+		bundle.Members = members[bundle.Name]
+		bundle.check()
+		bundles = append(bundles, bundle)
+	}
+	sort.SliceStable(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+	return bundles, nil
+}
+
+// check counts the active members and flags the bundle when it is degraded
+// or its members see different partners.
+//
+// Defaulted members received no LACPDU, their partner of zeros isn't another
+// partner. Bundles without LACP have no members to count, only their status
+// tells whether they are degraded.
+func (b *LACPBundle) check() {
+
+	partners := make(map[string]bool)
+	for _, m := range b.Members {
+		if m.Collecting && m.Distributing {
+			b.ActiveMembers++
+		}
+		if m.PartnerSystemID != "" && !m.Defaulted {
+			partners[m.PartnerSystemID+"/"+strconv.Itoa(m.PartnerKey)] = true
+		}
+	}
+
+	b.PartnerMismatch = len(partners) > 1
+	b.Degraded = b.AdminStatus == "up" && (b.OperStatus != "up" ||
+		len(b.Members) > 0 && (b.ActiveMembers < len(b.Members) || b.ActiveMembers < b.MinimumLinks))
+}
+
+func lacpFlag(flag string) bool {
+	return strings.EqualFold(strings.TrimSpace(flag), "yes")
+}
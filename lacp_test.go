@@ -0,0 +1,61 @@
+package networkapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadLACPBundles(t *testing.T) {
+	active := func(name string, key int, partner string, partnerKey int) LACPMember {
+		return LACPMember{
+			Name: name, Collecting: true, Distributing: true, Synchronized: true,
+			Activity: "Active", Timeout: "Fast", PartnerTimeout: "Fast",
+			ActorSystemID: "00:50:56:8b:00:01", ActorKey: key,
+			PartnerSystemID: partner, PartnerKey: partnerKey,
+			MuxState: "Collecting distributing", ReceiveState: "Current",
+		}
+	}
+	want := []LACPBundle{
+		{
+			Name: "ae0", AdminStatus: "up", OperStatus: "up", Speed: 2e9, MinimumLinks: 1,
+			Members: []LACPMember{
+				active("ge-0/0/3", 1, "3c:61:04:11:22:00", 5),
+				active("ge-0/0/4", 1, "3c:61:04:11:22:00", 5),
+			},
+			ActiveMembers: 2,
+		},
+		// The partner of a defaulted member is all zeros, which isn't
+		// another partner.
+		{
+			Name: "ae1", AdminStatus: "up", OperStatus: "up", Speed: 1e9, MinimumLinks: 1,
+			Members: []LACPMember{
+				active("ge-0/0/5", 2, "3c:61:04:11:22:00", 6),
+				{
+					Name: "ge-0/0/6", Defaulted: true, Activity: "Active", Timeout: "Fast", PartnerTimeout: "Slow",
+					ActorSystemID: "00:50:56:8b:00:01", ActorKey: 2, PartnerSystemID: "00:00:00:00:00:00",
+					MuxState: "Detached", ReceiveState: "Defaulted",
+				},
+			},
+			ActiveMembers: 1,
+			Degraded:      true,
+		},
+		// Without LACP the bundle has no members and only its status tells.
+		{Name: "ae10", AdminStatus: "up", OperStatus: "up", Speed: 10e9, MinimumLinks: 1},
+		{
+			Name: "ae2", AdminStatus: "up", OperStatus: "up", Speed: 20e9, MinimumLinks: 2,
+			Members: []LACPMember{
+				active("xe-0/1/0", 3, "3c:61:04:11:22:00", 7),
+				active("xe-0/1/1", 3, "3c:61:04:99:88:00", 7),
+			},
+			ActiveMembers:   2,
+			PartnerMismatch: true,
+		},
+	}
+	got, err := ReadLACPBundles(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLACPBundles() = %+v, want %+v", got, want)
+	}
+}
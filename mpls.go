@@ -0,0 +1,289 @@
+package networkapi
+
+import (
+	"net/netip"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+type explicitRouteXML struct {
+	Addresses []string `xml:"address"`
+}
+
+// rsvpSessionXML is a session of show mpls lsp and show rsvp session, the
+// ingress LSPs of show mpls lsp holding their paths in mpls-lsp.
+type rsvpSessionXML struct {
+	Destination   string           `xml:"destination-address"`
+	Source        string           `xml:"source-address"`
+	State         string           `xml:"lsp-state"`
+	Name          string           `xml:"name"`
+	Style         string           `xml:"rsvp-style"`
+	LabelIn       string           `xml:"label-in"`
+	LabelOut      string           `xml:"label-out"`
+	ExplicitRoute explicitRouteXML `xml:"explicit-route"`
+	RecordRoute   explicitRouteXML `xml:"record-route"`
+	LSP           *struct {
+		Destination string `xml:"destination-address"`
+		Source      string `xml:"source-address"`
+		State       string `xml:"lsp-state"`
+		Name        string `xml:"name"`
+		ActivePath  string `xml:"active-path"`
+		Paths       []struct {
+			Name          string           `xml:"name"`
+			Active        *struct{}        `xml:"path-active"`
+			Bandwidth     string           `xml:"bandwidth"`
+			ExplicitRoute explicitRouteXML `xml:"explicit-route"`
+			ReceivedRRO   string           `xml:"received-rro"`
+			History       []struct {
+				Time string `xml:"time"`
+				Log  string `xml:"log"`
+			} `xml:"path-history"`
+		} `xml:"mpls-lsp-path"`
+	} `xml:"mpls-lsp"`
+}
+
+type rsvpSessionDataXML struct {
+	Type     string           `xml:"session-type"`
+	Sessions []rsvpSessionXML `xml:"rsvp-session"`
+}
+
+type mplsLSPSSH struct {
+	Data []rsvpSessionDataXML `xml:"mpls-lsp-information>rsvp-session-data"`
+}
+
+type rsvpSessionSSH struct {
+	Data []rsvpSessionDataXML `xml:"rsvp-session-information>rsvp-session-data"`
+}
+
+type ldpNeighborSSH struct {
+	Neighbors []struct {
+		Address       string `xml:"ldp-neighbor-address"`
+		Interface     string `xml:"ldp-neighbor-interface"`
+		LabelSpace    string `xml:"ldp-label-space-id"`
+		RemainingTime string `xml:"ldp-remaining-time"`
+	} `xml:"ldp-neighbor-information>ldp-neighbor"`
+}
+
+type ldpSessionSSH struct {
+	Sessions []struct {
+		Neighbor        string `xml:"ldp-neighbor-address"`
+		State           string `xml:"ldp-session-state"`
+		ConnectionState string `xml:"ldp-connection-state"`
+		RemainingTime   string `xml:"ldp-remaining-time"`
+	} `xml:"ldp-session-information>ldp-session"`
+}
+
+type ldpDatabaseSSH struct {
+	Databases []struct {
+		Type     string `xml:"ldp-database-type"`
+		Session  string `xml:"ldp-session-id"`
+		Bindings []struct {
+			Prefix string `xml:"ldp-prefix"`
+			Label  string `xml:"ldp-label"`
+		} `xml:"ldp-binding"`
+	} `xml:"ldp-database-information>ldp-database"`
+}
+
+//GetMPLSLSPsSSH ...Returns the LSPs the device is the ingress, a transit or the egress of
+func (c *Client) GetMPLSLSPsSSH(session *ssh.Session) ([]LSP, error) {
+	return ReadMPLSLSPs(sessionRunner(session))
+}
+
+//GetMPLSLSPs ...Returns the LSPs the device is the ingress, a transit or the egress of
+func (c *Client) GetMPLSLSPs(session *junos.Junos) ([]LSP, error) {
+	return ReadMPLSLSPs(NetconfRunner(session))
+}
+
+//ReadMPLSLSPs ... Returns the LSPs of a Junos device from show mpls lsp extensive
+func ReadMPLSLSPs(run Runner) ([]LSP, error) {
+	var reply mplsLSPSSH
+	if err := junosXML(run, "show mpls lsp extensive", &reply); err != nil {
+		return nil, err
+	}
+
+	var lsps []LSP
+	for _, data := range reply.Data {
+		role := strings.ToLower(strings.TrimSpace(data.Type))
+		for _, s := range data.Sessions {
+			lsps = append(lsps, s.lsp(role))
+		}
+	}
+	return lsps, nil
+}
+
+func (s rsvpSessionXML) lsp(role string) LSP {
+
+	lsp := LSP{
+		Name:     strings.TrimSpace(s.Name),
+		Role:     role,
+		State:    strings.TrimSpace(s.State),
+		From:     strings.TrimSpace(s.Source),
+		To:       strings.TrimSpace(s.Destination),
+		ERO:      trimFields(s.ExplicitRoute.Addresses),
+		RRO:      trimFields(s.RecordRoute.Addresses),
+		LabelIn:  strings.TrimSpace(s.LabelIn),
+		LabelOut: strings.TrimSpace(s.LabelOut),
+	}
+	if s.LSP == nil {
+		return lsp
+	}
+
+	lsp.Name = strings.TrimSpace(s.LSP.Name)
+	lsp.State = strings.TrimSpace(s.LSP.State)
+	lsp.From = strings.TrimSpace(s.LSP.Source)
+	lsp.To = strings.TrimSpace(s.LSP.Destination)
+	// The active path is shown as "primary-path (primary)".
+	lsp.ActivePath = strings.TrimSpace(strings.SplitN(s.LSP.ActivePath, "(", 2)[0])
+	for _, path := range s.LSP.Paths {
+		if path.Active == nil && strings.TrimSpace(path.Name) != lsp.ActivePath {
+			continue
+		}
+		lsp.Bandwidth = parseSpeed(strings.TrimSpace(path.Bandwidth))
+		lsp.ERO = trimFields(path.ExplicitRoute.Addresses)
+		// The received RRO lists the addresses along with flags, "10.0.0.2(flag=0x20)".
+		lsp.RRO = nil
+		for _, hop := range strings.Fields(path.ReceivedRRO) {
+			hop = strings.SplitN(hop, "(", 2)[0]
+			if _, err := netip.ParseAddr(hop); err == nil {
+				lsp.RRO = append(lsp.RRO, hop)
+			}
+		}
+		if n := len(path.History); n > 0 {
+			lsp.LastChange = strings.TrimSpace(path.History[n-1].Time)
+			lsp.LastEvent = strings.TrimSpace(path.History[n-1].Log)
+		}
+		break
+	}
+	return lsp
+}
+
+// trimFields returns the values trimmed, nil when there are none.
+func trimFields(values []string) []string {
+	var fields []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, value)
+		}
+	}
+	return fields
+}
+
+//GetRSVPSessionsSSH ...Returns the RSVP sessions
+func (c *Client) GetRSVPSessionsSSH(session *ssh.Session) ([]RSVPSession, error) {
+	return ReadRSVPSessions(sessionRunner(session))
+}
+
+//GetRSVPSessions ...Returns the RSVP sessions
+func (c *Client) GetRSVPSessions(session *junos.Junos) ([]RSVPSession, error) {
+	return ReadRSVPSessions(NetconfRunner(session))
+}
+
+//ReadRSVPSessions ... Returns the RSVP sessions of a Junos device
+func ReadRSVPSessions(run Runner) ([]RSVPSession, error) {
+	var reply rsvpSessionSSH
+	if err := junosXML(run, "show rsvp session", &reply); err != nil {
+		return nil, err
+	}
+
+	var sessions []RSVPSession
+	for _, data := range reply.Data {
+		role := strings.ToLower(strings.TrimSpace(data.Type))
+		for _, s := range data.Sessions {
+			sessions = append(sessions, RSVPSession{
+				Name:     strings.TrimSpace(s.Name),
+				Role:     role,
+				State:    strings.TrimSpace(s.State),
+				From:     strings.TrimSpace(s.Source),
+				To:       strings.TrimSpace(s.Destination),
+				Style:    strings.TrimSpace(s.Style),
+				LabelIn:  strings.TrimSpace(s.LabelIn),
+				LabelOut: strings.TrimSpace(s.LabelOut),
+			})
+		}
+	}
+	return sessions, nil
+}
+
+//GetLDPNeighborsSSH ...Returns the neighbors discovered by LDP
+func (c *Client) GetLDPNeighborsSSH(session *ssh.Session) ([]LDPNeighbor, error) {
+	return ReadLDPNeighbors(sessionRunner(session))
+}
+
+//GetLDPNeighbors ...Returns the neighbors discovered by LDP
+func (c *Client) GetLDPNeighbors(session *junos.Junos) ([]LDPNeighbor, error) {
+	return ReadLDPNeighbors(NetconfRunner(session))
+}
+
+//ReadLDPNeighbors ... Returns the neighbors discovered by LDP on a Junos device
+func ReadLDPNeighbors(run Runner) ([]LDPNeighbor, error) {
+	var reply ldpNeighborSSH
+	if err := junosXML(run, "show ldp neighbor", &reply); err != nil {
+		return nil, err
+	}
+
+	var neighbors []LDPNeighbor
+	for _, n := range reply.Neighbors {
+		neighbors = append(neighbors, LDPNeighbor{
+			Address:    strings.TrimSpace(n.Address),
+			Interface:  strings.TrimSpace(n.Interface),
+			LabelSpace: strings.TrimSpace(n.LabelSpace),
+			HoldTime:   time.Duration(parseInt(n.RemainingTime)) * time.Second,
+		})
+	}
+	return neighbors, nil
+}
+
+//GetLDPSessions ...Returns the LDP sessions with their label counts
+func (c *Client) GetLDPSessions(session *junos.Junos) ([]LDPSession, error) {
+	return ReadLDPSessions(NetconfRunner(session))
+}
+
+//ReadLDPSessions ... Returns the LDP sessions of a Junos device with their label counts
+//
+// The labels are counted in show ldp database, whose input database of a
+// session holds the labels received and output database the labels advertised.
+// It runs both commands, so over SSH it takes client.RunSSH.
+func ReadLDPSessions(run Runner) ([]LDPSession, error) {
+
+	var reply ldpSessionSSH
+	if err := junosXML(run, "show ldp session detail", &reply); err != nil {
+		return nil, err
+	}
+	var database ldpDatabaseSSH
+	if err := junosXML(run, "show ldp database", &database); err != nil {
+		return nil, err
+	}
+
+	received, advertised := make(map[string]int), make(map[string]int)
+	for _, db := range database.Databases {
+		// The session is "10.255.0.1:0--10.255.0.2:0", the neighbor being last.
+		id := strings.TrimSpace(db.Session)
+		if i := strings.LastIndex(id, "--"); i >= 0 {
+			id = id[i+2:]
+		}
+		neighbor := strings.SplitN(id, ":", 2)[0]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(db.Type), "Input"):
+			received[neighbor] += len(db.Bindings)
+		case strings.HasPrefix(strings.TrimSpace(db.Type), "Output"):
+			advertised[neighbor] += len(db.Bindings)
+		}
+	}
+
+	var sessions []LDPSession
+	for _, s := range reply.Sessions {
+		neighbor := strings.TrimSpace(s.Neighbor)
+		sessions = append(sessions, LDPSession{
+			Neighbor:         neighbor,
+			State:            strings.TrimSpace(s.State),
+			ConnectionState:  strings.TrimSpace(s.ConnectionState),
+			HoldTime:         time.Duration(parseInt(s.RemainingTime)) * time.Second,
+			LabelsReceived:   received[neighbor],
+			LabelsAdvertised: advertised[neighbor],
+		})
+	}
+	return sessions, nil
+}
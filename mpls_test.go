@@ -0,0 +1,80 @@
+package networkapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadMPLSLSPs(t *testing.T) {
+	want := []LSP{
+		{
+			Name: "to-core-3", Role: "ingress", State: "Up", From: "10.255.0.1", To: "10.255.0.3",
+			ActivePath: "primary-path", Bandwidth: 100e6,
+			ERO:        []string{"10.0.0.1", "10.0.1.1"},
+			RRO:        []string{"10.0.0.1", "10.0.1.1"},
+			LastChange: "Oct 17 10:40:01.127", LastEvent: "Selected as active path",
+		},
+		{
+			Name: "core-3-to-edge-1", Role: "egress", State: "Up", From: "10.255.0.3", To: "10.255.0.1",
+			RRO:     []string{"10.0.1.2", "10.0.0.2"},
+			LabelIn: "3", LabelOut: "-",
+		},
+		{
+			Name: "core-2-to-core-4", Role: "transit", State: "Up", From: "10.255.0.2", To: "10.255.0.4",
+			ERO:     []string{"10.0.2.2"},
+			RRO:     []string{"10.0.0.1", "10.0.2.2"},
+			LabelIn: "299808", LabelOut: "299824",
+		},
+	}
+	got, err := ReadMPLSLSPs(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadMPLSLSPs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadRSVPSessions(t *testing.T) {
+	want := []RSVPSession{
+		{Name: "to-core-3", Role: "ingress", State: "Up", From: "10.255.0.1", To: "10.255.0.3", Style: "1 FF", LabelIn: "-", LabelOut: "299792"},
+		{Name: "core-3-to-edge-1", Role: "egress", State: "Up", From: "10.255.0.3", To: "10.255.0.1", Style: "1 FF", LabelIn: "3", LabelOut: "-"},
+		{Name: "core-2-to-core-4", Role: "transit", State: "Up", From: "10.255.0.2", To: "10.255.0.4", Style: "1 SE", LabelIn: "299808", LabelOut: "299824"},
+	}
+	got, err := ReadRSVPSessions(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRSVPSessions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadLDPNeighbors(t *testing.T) {
+	want := []LDPNeighbor{
+		{Address: "10.0.0.1", Interface: "ge-0/0/0.0", LabelSpace: "10.255.0.2:0", HoldTime: 13 * time.Second},
+		{Address: "10.255.0.4", Interface: "lo0.0", LabelSpace: "10.255.0.4:0", HoldTime: 41 * time.Second},
+	}
+	got, err := ReadLDPNeighbors(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLDPNeighbors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadLDPSessions(t *testing.T) {
+	want := []LDPSession{
+		{Neighbor: "10.255.0.2", State: "Operational", ConnectionState: "Open", HoldTime: 26 * time.Second, LabelsReceived: 3, LabelsAdvertised: 2},
+		{Neighbor: "10.255.0.4", State: "Nonexistent", ConnectionState: "Closed"},
+	}
+	got, err := ReadLDPSessions(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLDPSessions() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+package networkapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+type arpTableSSH struct {
+	Entries []struct {
+		MACAddress    string `xml:"mac-address"`
+		IPAddress     string `xml:"ip-address"`
+		InterfaceName string `xml:"interface-name"`
+		TimeToExpire  string `xml:"time-to-expire"`
+	} `xml:"arp-table-information>arp-table-entry"`
+}
+
+type ipv6NeighborsSSH struct {
+	Entries []struct {
+		Address       string `xml:"ipv6-nd-neighbor-address"`
+		L2Address     string `xml:"ipv6-nd-neighbor-l2-address"`
+		State         string `xml:"ipv6-nd-state"`
+		Expire        string `xml:"ipv6-nd-expire"`
+		IsRouter      string `xml:"ipv6-nd-isrouter"`
+		InterfaceName string `xml:"ipv6-nd-interface-name"`
+	} `xml:"ipv6-nd-information>ipv6-nd-entry"`
+}
+
+// ethernetSwitchingSSH holds the tables of the ELS releases and of the
+// earlier ones, a device answering with one of them.
+type ethernetSwitchingSSH struct {
+	VLANs []struct {
+		VLANID  string `xml:"l2ng-l2-vlan-id"`
+		Entries []struct {
+			VLAN      string `xml:"l2ng-l2-mac-vlan-name"`
+			Address   string `xml:"l2ng-l2-mac-address"`
+			Flags     string `xml:"l2ng-l2-mac-flags"`
+			Age       string `xml:"l2ng-l2-mac-age"`
+			Interface string `xml:"l2ng-l2-mac-logical-interface"`
+		} `xml:"l2ng-mac-entry"`
+	} `xml:"l2ng-l2ald-rtb-macdb>l2ng-l2ald-mac-entry-vlan"`
+	Entries []struct {
+		VLAN       string   `xml:"mac-vlan"`
+		VLANTag    string   `xml:"mac-vlan-tag"`
+		Address    string   `xml:"mac-address"`
+		Type       string   `xml:"mac-type"`
+		Age        string   `xml:"mac-age"`
+		Interfaces []string `xml:"mac-interfaces-list>mac-interfaces"`
+	} `xml:"ethernet-switching-table-information>ethernet-switching-table>mac-table-entry"`
+}
+
+//GetARPTableSSH ...Returns the entries of the ARP table
+func (c *Client) GetARPTableSSH(session *ssh.Session) ([]Neighbor, error) {
+	return ReadARPTable(sessionRunner(session))
+}
+
+//GetARPTable ...Returns the entries of the ARP table
+func (c *Client) GetARPTable(session *junos.Junos) ([]Neighbor, error) {
+	return ReadARPTable(NetconfRunner(session))
+}
+
+//ReadARPTable ... Returns the entries of the ARP table of a Junos device
+//
+// The addresses aren't resolved to names, and the time left before the
+// entries expire is asked for.
+func ReadARPTable(run Runner) ([]Neighbor, error) {
+	var arp arpTableSSH
+	if err := junosXML(run, "show arp no-resolve expiration-time", &arp); err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]Neighbor, 0, len(arp.Entries))
+	for _, entry := range arp.Entries {
+		n := Neighbor{State: "reachable"}
+		n.Address, _ = netip.ParseAddr(strings.TrimSpace(entry.IPAddress))
+		n.MAC, _ = net.ParseMAC(strings.TrimSpace(entry.MACAddress))
+		n.Interface, n.Port = neighborInterface(entry.InterfaceName)
+		if expire := strings.TrimSpace(entry.TimeToExpire); expire != "" {
+			n.Expire = time.Duration(parseInt(expire)) * time.Second
+		}
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, nil
+}
+
+//GetIPv6NeighborsSSH ...Returns the entries of the IPv6 neighbor cache
+func (c *Client) GetIPv6NeighborsSSH(session *ssh.Session) ([]Neighbor, error) {
+	return ReadIPv6Neighbors(sessionRunner(session))
+}
+
+//GetIPv6Neighbors ...Returns the entries of the IPv6 neighbor cache
+func (c *Client) GetIPv6Neighbors(session *junos.Junos) ([]Neighbor, error) {
+	return ReadIPv6Neighbors(NetconfRunner(session))
+}
+
+//ReadIPv6Neighbors ... Returns the entries of the IPv6 neighbor cache of a Junos device
+func ReadIPv6Neighbors(run Runner) ([]Neighbor, error) {
+	var nd ipv6NeighborsSSH
+	if err := junosXML(run, "show ipv6 neighbors", &nd); err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]Neighbor, 0, len(nd.Entries))
+	for _, entry := range nd.Entries {
+		n := Neighbor{
+			State:  strings.TrimSpace(entry.State),
+			Router: strings.TrimSpace(entry.IsRouter) == "yes",
+			Expire: time.Duration(parseInt(entry.Expire)) * time.Second,
+		}
+		n.Address, _ = netip.ParseAddr(strings.TrimSpace(entry.Address))
+		n.MAC, _ = net.ParseMAC(strings.TrimSpace(entry.L2Address))
+		n.Interface, n.Port = neighborInterface(entry.InterfaceName)
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, nil
+}
+
+// neighborInterface splits "irb.100 [ge-0/0/1.0]" into the interface and
+// the member it was learned on.
+func neighborInterface(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name, ""
+	}
+	port := strings.TrimSpace(strings.Trim(name[i:], "[]"))
+	return strings.TrimSpace(name[:i]), port
+}
+
+//GetEthernetSwitchingTableSSH ...Returns the MAC addresses learned by the switch
+func (c *Client) GetEthernetSwitchingTableSSH(session *ssh.Session) ([]MACEntry, error) {
+	return ReadEthernetSwitchingTable(sessionRunner(session))
+}
+
+//GetEthernetSwitchingTable ...Returns the MAC addresses learned by the switch
+func (c *Client) GetEthernetSwitchingTable(session *junos.Junos) ([]MACEntry, error) {
+	return ReadEthernetSwitchingTable(NetconfRunner(session))
+}
+
+//ReadEthernetSwitchingTable ... Returns the MAC addresses learned by a Junos switch
+//
+// Entries flooding a VLAN rather than naming a MAC address are left out. The
+// type is "dynamic" or "static" when the device tells which.
+func ReadEthernetSwitchingTable(run Runner) ([]MACEntry, error) {
+	var table ethernetSwitchingSSH
+	if err := junosXML(run, "show ethernet-switching table", &table); err != nil {
+		return nil, err
+	}
+
+	var entries []MACEntry
+	for _, vlan := range table.VLANs {
+		for _, entry := range vlan.Entries {
+			mac, err := net.ParseMAC(strings.TrimSpace(entry.Address))
+			if err != nil {
+				continue
+			}
+			entries = append(entries, MACEntry{
+				MAC:       mac,
+				VLAN:      strings.TrimSpace(entry.VLAN),
+				VLANID:    parseInt(vlan.VLANID),
+				Interface: strings.TrimSpace(entry.Interface),
+				Type:      macType(entry.Flags),
+				Age:       macAge(entry.Age),
+			})
+		}
+	}
+	for _, entry := range table.Entries {
+		mac, err := net.ParseMAC(strings.TrimSpace(entry.Address))
+		if err != nil {
+			continue
+		}
+		e := MACEntry{
+			MAC:    mac,
+			VLAN:   strings.TrimSpace(entry.VLAN),
+			VLANID: parseInt(entry.VLANTag),
+			Type:   macType(entry.Type),
+			Age:    macAge(entry.Age),
+		}
+		if len(entry.Interfaces) > 0 {
+			e.Interface = strings.TrimSpace(entry.Interfaces[0])
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// macType maps the flags of the ELS table, D or S, and the types of the
+// earlier table, Learn or Static, to dynamic or static.
+func macType(flags string) string {
+	flags = strings.TrimSpace(flags)
+	switch {
+	case strings.EqualFold(flags, "learn") || strings.HasPrefix(flags, "D"):
+		return "dynamic"
+	case strings.EqualFold(flags, "static") || strings.HasPrefix(flags, "S"):
+		return "static"
+	}
+	return strings.ToLower(flags)
+}
+
+// macAge returns the age of an entry, given in seconds or as a clock, and
+// "-" when the device doesn't age it.
+func macAge(age string) time.Duration {
+	age = strings.TrimSpace(age)
+	if strings.Contains(age, ":") {
+		return parseUptime(age)
+	}
+	return time.Duration(parseInt(age)) * time.Second
+}
+
+//LocateHosts ... Returns where the neighbors are plugged in, from the switching table
+//
+// The port is the member interface the neighbor was learned on when the
+// device told it, and the interface the switching table has its MAC address
+// on otherwise. Neighbors without a MAC address are left out.
+func LocateHosts(neighbors []Neighbor, table []MACEntry) []HostLocation {
+
+	var hosts []HostLocation
+	for _, n := range neighbors {
+		if len(n.MAC) == 0 {
+			continue
+		}
+		host := HostLocation{Address: n.Address, MAC: n.MAC, Interface: n.Interface, Port: n.Port}
+		for _, entry := range table {
+			if !bytes.Equal(entry.MAC, n.MAC) {
+				continue
+			}
+			if host.Port == "" {
+				host.Port = entry.Interface
+			}
+			if host.Port == entry.Interface || host.VLAN == "" {
+				host.VLAN, host.VLANID = entry.VLAN, entry.VLANID
+			}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+//MarshalJSON ... Marshals the neighbor with its MAC address as text, net.HardwareAddr being a byte slice
+func (n Neighbor) MarshalJSON() ([]byte, error) {
+	type neighbor Neighbor
+	return json.Marshal(struct {
+		neighbor
+		MAC string `json:"mac"`
+	}{neighbor(n), n.MAC.String()})
+}
+
+//MarshalJSON ... Marshals the entry with its MAC address as text
+func (e MACEntry) MarshalJSON() ([]byte, error) {
+	type macEntry MACEntry
+	return json.Marshal(struct {
+		macEntry
+		MAC string `json:"mac"`
+	}{macEntry(e), e.MAC.String()})
+}
+
+//MarshalJSON ... Marshals the location with the MAC address of the host as text
+func (h HostLocation) MarshalJSON() ([]byte, error) {
+	type hostLocation HostLocation
+	return json.Marshal(struct {
+		hostLocation
+		MAC string `json:"mac"`
+	}{hostLocation(h), h.MAC.String()})
+}
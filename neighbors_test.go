@@ -0,0 +1,100 @@
+package networkapi
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustMAC(s string) net.HardwareAddr {
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		panic(err)
+	}
+	return mac
+}
+
+func TestReadARPTable(t *testing.T) {
+	want := []Neighbor{
+		{Address: netip.MustParseAddr("10.0.0.1"), MAC: mustMAC("00:50:56:8b:12:34"), Interface: "ge-0/0/0.0", State: "reachable", Expire: 1157 * time.Second},
+		{Address: netip.MustParseAddr("192.0.2.10"), MAC: mustMAC("3c:61:04:aa:bb:01"), Interface: "irb.100", Port: "ge-0/0/5.0", State: "reachable", Expire: 842 * time.Second},
+		{Address: netip.MustParseAddr("192.0.2.20"), MAC: mustMAC("00:11:22:33:44:55"), Interface: "irb.100", State: "reachable"},
+	}
+	got, err := ReadARPTable(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadARPTable() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadIPv6Neighbors(t *testing.T) {
+	want := []Neighbor{
+		{Address: netip.MustParseAddr("fe80::250:56ff:fe8b:1234"), MAC: mustMAC("00:50:56:8b:12:34"), Interface: "ge-0/0/0.0", State: "reachable", Router: true, Expire: 28 * time.Second},
+		{Address: netip.MustParseAddr("2001:db8:100::10"), MAC: mustMAC("3c:61:04:aa:bb:01"), Interface: "irb.100", Port: "ge-0/0/5.0", State: "stale", Expire: 1094 * time.Second},
+		{Address: netip.MustParseAddr("2001:db8:100::99"), Interface: "irb.100", State: "incomplete"},
+	}
+	got, err := ReadIPv6Neighbors(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadIPv6Neighbors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadEthernetSwitchingTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []MACEntry
+	}{
+		{"els", "ethernet-switching-els.xml", []MACEntry{
+			{MAC: mustMAC("3c:61:04:aa:bb:01"), VLAN: "users", VLANID: 100, Interface: "ge-0/0/5.0", Type: "dynamic"},
+			{MAC: mustMAC("00:11:22:33:44:55"), VLAN: "users", VLANID: 100, Interface: "ge-0/0/7.0", Type: "static"},
+			{MAC: mustMAC("00:1b:54:cc:dd:02"), VLAN: "voice", VLANID: 200, Interface: "ae0.0", Type: "dynamic"},
+		}},
+		// The earlier releases don't tell the VLAN ID and list the
+		// flooding entries, which are left out.
+		{"pre-els", "ethernet-switching.xml", []MACEntry{
+			{MAC: mustMAC("3c:61:04:aa:bb:01"), VLAN: "users", Interface: "ge-0/0/5.0", Type: "dynamic"},
+			{MAC: mustMAC("00:11:22:33:44:55"), VLAN: "users", Interface: "ge-0/0/7.0", Type: "static"},
+			{MAC: mustMAC("00:1b:54:cc:dd:02"), VLAN: "voice", Interface: "ae0.0", Type: "dynamic"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := fileRunner(t, PlatformJunos, "show ethernet-switching table | display xml", tt.fixture)
+			got, err := ReadEthernetSwitchingTable(run)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadEthernetSwitchingTable() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocateHosts(t *testing.T) {
+	neighbors, err := ReadARPTable(fixtureRunner(t, PlatformJunos))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := ReadEthernetSwitchingTable(fileRunner(t, PlatformJunos, "show ethernet-switching table | display xml", "ethernet-switching-els.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []HostLocation{
+		{Address: netip.MustParseAddr("10.0.0.1"), MAC: mustMAC("00:50:56:8b:12:34"), Interface: "ge-0/0/0.0"},
+		{Address: netip.MustParseAddr("192.0.2.10"), MAC: mustMAC("3c:61:04:aa:bb:01"), Interface: "irb.100", Port: "ge-0/0/5.0", VLAN: "users", VLANID: 100},
+		{Address: netip.MustParseAddr("192.0.2.20"), MAC: mustMAC("00:11:22:33:44:55"), Interface: "irb.100", Port: "ge-0/0/7.0", VLAN: "users", VLANID: 100},
+	}
+	if got := LocateHosts(neighbors, table); !reflect.DeepEqual(got, want) {
+		t.Errorf("LocateHosts() = %+v, want %+v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package networkapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -19,10 +20,36 @@ type NetworkAPI interface {
 	GetHostInfo(session *junos.Junos) (string, error)
 	GetLLDPNeighbors(session *junos.Junos) (string, error)
 	GetInterfaceDiagnostics(session *junos.Junos) (string, error)
+	GetChassisInventory(session *junos.Junos) ([]ChassisInventory, error)
+	GetFacts(session *junos.Junos) (*DeviceFacts, error)
+	GetRoutes(session *junos.Junos, query RouteQuery) ([]Route, error)
+	GetARPTable(session *junos.Junos) ([]Neighbor, error)
+	GetIPv6Neighbors(session *junos.Junos) ([]Neighbor, error)
+	GetEthernetSwitchingTable(session *junos.Junos) ([]MACEntry, error)
+	GetOSPFNeighbors(session *junos.Junos, version int) ([]OSPFNeighbor, error)
+	GetOSPFInterfaces(session *junos.Junos, version int) ([]OSPFInterface, error)
+	GetISISAdjacencies(session *junos.Junos) ([]ISISAdjacency, error)
+	GetISISDatabase(session *junos.Junos) ([]ISISLSP, error)
+	GetMPLSLSPs(session *junos.Junos) ([]LSP, error)
+	GetRSVPSessions(session *junos.Junos) ([]RSVPSession, error)
+	GetLDPNeighbors(session *junos.Junos) ([]LDPNeighbor, error)
+	GetLDPSessions(session *junos.Junos) ([]LDPSession, error)
+	GetChassisAlarms(session *junos.Junos) ([]Alarm, error)
+	GetSystemAlarms(session *junos.Junos) ([]Alarm, error)
+	GetEnvironment(session *junos.Junos) ([]EnvironmentItem, error)
+	GetRoutingEngines(session *junos.Junos) ([]RoutingEngineStatus, error)
+	GetLACPBundles(session *junos.Junos) ([]LACPBundle, error)
+	GetFirewallCounters(session *junos.Junos, filter string) ([]FirewallFilter, error)
+	Ping(ctx context.Context, session *junos.Junos, target string, opts PingOptions) (*PingResult, error)
+	Traceroute(ctx context.Context, session *junos.Junos, target string, opts TracerouteOptions) (*TracerouteResult, error)
 	Close() *junos.Junos
 }
 
 //Connect ...
+//
+// The facts go-junos reads on connect and the NETCONF capabilities are cached
+// on the client on the first connection. With AutoDetect, the facts are
+// detected in full, the serial number and virtual chassis included.
 func (c *Client) Connect() (*junos.Junos, error) {
 
 	auth := &junos.AuthMethod{
@@ -33,6 +60,21 @@ func (c *Client) Connect() (*junos.Junos, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if c.Facts() != nil {
+		return jnpr, nil
+	}
+	facts := netconfFacts(jnpr)
+	if c.AutoDetect {
+		detected, err := DetectFacts(NetconfRunner(jnpr))
+		if err != nil {
+			jnpr.Close()
+			return nil, err
+		}
+		detected.Capabilities = facts.Capabilities
+		facts = detected
+	}
+	c.setFacts(facts)
 	return jnpr, nil
 }
 
@@ -0,0 +1,240 @@
+package networkapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	junos "github.com/kgrvamsi/go-junos"
+)
+
+type pingSSH struct {
+	Target  string `xml:"ping-results>target-host"`
+	Address string `xml:"ping-results>target-ip"`
+	Probes  []struct {
+		Success  *struct{} `xml:"probe-success"`
+		Sequence string    `xml:"sequence-number"`
+		Address  string    `xml:"ip-address"`
+		TTL      string    `xml:"time-to-live"`
+		Size     string    `xml:"response-size"`
+		RTT      string    `xml:"rtt"`
+	} `xml:"ping-results>probe-result"`
+	Summary struct {
+		Sent       string `xml:"probes-sent"`
+		Received   string `xml:"responses-received"`
+		PacketLoss string `xml:"packet-loss"`
+		Minimum    string `xml:"rtt-minimum"`
+		Maximum    string `xml:"rtt-maximum"`
+		Average    string `xml:"rtt-average"`
+		StdDev     string `xml:"rtt-stddev"`
+	} `xml:"ping-results>probe-results-summary"`
+}
+
+type tracerouteSSH struct {
+	Target  string `xml:"traceroute-results>target-host"`
+	Address string `xml:"traceroute-results>target-ip"`
+	Hops    []struct {
+		TTL    string `xml:"ttl-value"`
+		Probes []struct {
+			Address  string `xml:"ip-address"`
+			HostName string `xml:"host-name"`
+			RTT      string `xml:"rtt"`
+		} `xml:"probe-result"`
+	} `xml:"traceroute-results>hop"`
+}
+
+// defaultPingCount is the count of the pings sent when the options don't
+// give one, Junos pinging until interrupted otherwise.
+const defaultPingCount = 5
+
+//PingSSH ...Pings the target from the device, over a session of its own closed when the context is done
+func (c *Client) PingSSH(ctx context.Context, target string, opts PingOptions) (*PingResult, error) {
+	return ReadPing(func(command string) (string, error) {
+		return c.RunSSHContext(ctx, command)
+	}, target, opts)
+}
+
+//Ping ...Pings the target from the device
+//
+// When the context is done Ping closes the session, which the device would
+// answer the RPC over once the pings are sent. The session can't be used
+// afterwards.
+func (c *Client) Ping(ctx context.Context, session *junos.Junos, target string, opts PingOptions) (*PingResult, error) {
+	return ReadPing(contextRunner(ctx, NetconfRunner(session), session.Close), target, opts)
+}
+
+//ReadPing ... Pings the target from a Junos device and returns the responses to the probes
+func ReadPing(run Runner, target string, opts PingOptions) (*PingResult, error) {
+
+	command, err := pingCommand(target, opts)
+	if err != nil {
+		return nil, err
+	}
+	var reply pingSSH
+	if err := junosXML(run, command, &reply); err != nil {
+		return nil, err
+	}
+
+	summary := reply.Summary
+	result := &PingResult{
+		Target:     strings.TrimSpace(reply.Target),
+		Address:    strings.TrimSpace(reply.Address),
+		Sent:       parseInt(summary.Sent),
+		Received:   parseInt(summary.Received),
+		PacketLoss: parseFloat(summary.PacketLoss),
+		RTTMin:     microseconds(summary.Minimum),
+		RTTAvg:     microseconds(summary.Average),
+		RTTMax:     microseconds(summary.Maximum),
+		RTTStdDev:  microseconds(summary.StdDev),
+	}
+	for _, p := range reply.Probes {
+		result.Probes = append(result.Probes, PingProbe{
+			Sequence: parseInt(p.Sequence),
+			Success:  p.Success != nil,
+			Address:  strings.TrimSpace(p.Address),
+			TTL:      parseInt(p.TTL),
+			Size:     parseInt(p.Size),
+			RTT:      microseconds(p.RTT),
+		})
+	}
+	return result, nil
+}
+
+// pingCommand returns the ping command of the options, the arguments being
+// checked as they are passed to the CLI.
+func pingCommand(target string, opts PingOptions) (string, error) {
+
+	command, err := probeCommand("ping", target, opts.Source, opts.RoutingInstance)
+	if err != nil {
+		return "", err
+	}
+	count := opts.Count
+	if count <= 0 {
+		count = defaultPingCount
+	}
+	command += " count " + strconv.Itoa(count)
+	if opts.Size > 0 {
+		command += " size " + strconv.Itoa(opts.Size)
+	}
+	if opts.DoNotFragment {
+		command += " do-not-fragment"
+	}
+	if opts.TTL > 0 {
+		command += " ttl " + strconv.Itoa(opts.TTL)
+	}
+	return command + " rapid", nil
+}
+
+//TracerouteSSH ...Traces the route to the target from the device, over a session of its own closed when the context is done
+func (c *Client) TracerouteSSH(ctx context.Context, target string, opts TracerouteOptions) (*TracerouteResult, error) {
+	return ReadTraceroute(func(command string) (string, error) {
+		return c.RunSSHContext(ctx, command)
+	}, target, opts)
+}
+
+//Traceroute ...Traces the route to the target from the device
+//
+// When the context is done Traceroute closes the session, which the device
+// would answer the RPC over once the trace ends. The session can't be used
+// afterwards.
+func (c *Client) Traceroute(ctx context.Context, session *junos.Junos, target string, opts TracerouteOptions) (*TracerouteResult, error) {
+	return ReadTraceroute(contextRunner(ctx, NetconfRunner(session), session.Close), target, opts)
+}
+
+//ReadTraceroute ... Traces the route to the target from a Junos device and returns the hops with the responses to their probes
+//
+// The addresses of the hops aren't resolved to names.
+func ReadTraceroute(run Runner, target string, opts TracerouteOptions) (*TracerouteResult, error) {
+
+	command, err := probeCommand("traceroute", target, opts.Source, opts.RoutingInstance)
+	if err != nil {
+		return nil, err
+	}
+	if opts.TTL > 0 {
+		command += " ttl " + strconv.Itoa(opts.TTL)
+	}
+	var reply tracerouteSSH
+	if err := junosXML(run, command+" no-resolve", &reply); err != nil {
+		return nil, err
+	}
+
+	result := &TracerouteResult{
+		Target:  strings.TrimSpace(reply.Target),
+		Address: strings.TrimSpace(reply.Address),
+	}
+	for _, h := range reply.Hops {
+		hop := TracerouteHop{TTL: parseInt(h.TTL)}
+		for _, p := range h.Probes {
+			// A probe timing out has neither an address nor a round trip time.
+			probe := TracerouteProbe{
+				Address:  strings.TrimSpace(p.Address),
+				HostName: strings.TrimSpace(p.HostName),
+				RTT:      microseconds(p.RTT),
+			}
+			probe.Success = probe.Address != "" && strings.TrimSpace(p.RTT) != ""
+			hop.Probes = append(hop.Probes, probe)
+		}
+		result.Hops = append(result.Hops, hop)
+	}
+	return result, nil
+}
+
+// probeCommand returns the ping or traceroute command of the target with the
+// options they share.
+func probeCommand(command, target, source, instance string) (string, error) {
+
+	if !routeArgument.MatchString(target) {
+		return "", fmt.Errorf("invalid %s target %q", command, target)
+	}
+	command += " " + target
+	if source != "" {
+		if !routeArgument.MatchString(source) {
+			return "", fmt.Errorf("invalid source %q", source)
+		}
+		command += " source " + source
+	}
+	if instance != "" {
+		if !routeArgument.MatchString(instance) {
+			return "", fmt.Errorf("invalid routing instance %q", instance)
+		}
+		command += " routing-instance " + instance
+	}
+	return command, nil
+}
+
+// microseconds returns the duration of a round trip time, which Junos gives
+// in microseconds.
+func microseconds(rtt string) time.Duration {
+	return time.Duration(parseFloat(rtt) * float64(time.Microsecond))
+}
+
+// contextRunner returns a runner returning when the context is done, calling
+// abort then so that the command still running returns early.
+func contextRunner(ctx context.Context, run Runner, abort func()) Runner {
+	return func(command string) (string, error) {
+
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		type reply struct {
+			output string
+			err    error
+		}
+		done := make(chan reply, 1)
+		go func() {
+			output, err := run(command)
+			done <- reply{output, err}
+		}()
+
+		select {
+		case r := <-done:
+			return r.output, r.err
+		case <-ctx.Done():
+			abort()
+			return "", ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package networkapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadPing(t *testing.T) {
+	tests := []struct {
+		target string
+		want   *PingResult
+	}{
+		// The third ping timed out.
+		{"10.255.0.3", &PingResult{
+			Target: "10.255.0.3", Address: "10.255.0.3", Sent: 5, Received: 4, PacketLoss: 20,
+			RTTMin: 1187 * time.Microsecond, RTTAvg: 1546 * time.Microsecond, RTTMax: 2051 * time.Microsecond, RTTStdDev: 316 * time.Microsecond,
+			Probes: []PingProbe{
+				{Sequence: 0, Success: true, Address: "10.255.0.3", TTL: 63, Size: 64, RTT: 1523 * time.Microsecond},
+				{Sequence: 1, Success: true, Address: "10.255.0.3", TTL: 63, Size: 64, RTT: 1187 * time.Microsecond},
+				{Sequence: 2},
+				{Sequence: 3, Success: true, Address: "10.255.0.3", TTL: 63, Size: 64, RTT: 2051 * time.Microsecond},
+				{Sequence: 4, Success: true, Address: "10.255.0.3", TTL: 63, Size: 64, RTT: 1424 * time.Microsecond},
+			},
+		}},
+		{"192.0.2.99", &PingResult{Target: "192.0.2.99", Address: "192.0.2.99", Sent: 5, PacketLoss: 100}},
+	}
+	for _, tt := range tests {
+		got, err := ReadPing(fixtureRunner(t, PlatformJunos), tt.target, PingOptions{})
+		if err != nil {
+			t.Fatalf("ReadPing(%q): %v", tt.target, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadPing(%q) = %+v, want %+v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestPingCommand(t *testing.T) {
+	tests := []struct {
+		target string
+		opts   PingOptions
+		want   string
+		err    bool
+	}{
+		{"10.255.0.3", PingOptions{}, "ping 10.255.0.3 count 5 rapid", false},
+		{"2001:db8::1", PingOptions{Count: 100, Size: 1472, DoNotFragment: true, TTL: 8}, "ping 2001:db8::1 count 100 size 1472 do-not-fragment ttl 8 rapid", false},
+		{"core-3", PingOptions{Source: "10.255.0.1", RoutingInstance: "customer-a", Count: 1}, "ping core-3 source 10.255.0.1 routing-instance customer-a count 1 rapid", false},
+		{"10.255.0.3 count 100000", PingOptions{}, "", true},
+		{"10.255.0.3", PingOptions{Source: "10.255.0.1; request system reboot"}, "", true},
+		{"10.255.0.3", PingOptions{RoutingInstance: "a b"}, "", true},
+		{"", PingOptions{}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := pingCommand(tt.target, tt.opts)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("pingCommand(%q, %+v) = %q, %v, want %q, error %t", tt.target, tt.opts, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestReadTraceroute(t *testing.T) {
+	probes := func(address string, rtts ...time.Duration) []TracerouteProbe {
+		var probes []TracerouteProbe
+		for _, rtt := range rtts {
+			probes = append(probes, TracerouteProbe{Address: address, RTT: rtt * time.Microsecond, Success: true})
+		}
+		return probes
+	}
+	// The first probe of the second hop timed out.
+	want := &TracerouteResult{
+		Target:  "10.255.0.3",
+		Address: "10.255.0.3",
+		Hops: []TracerouteHop{
+			{TTL: 1, Probes: probes("10.0.0.1", 1021, 874, 902)},
+			{TTL: 2, Probes: append([]TracerouteProbe{{}}, probes("10.0.1.1", 1788, 1652)...)},
+			{TTL: 3, Probes: probes("10.255.0.3", 2210, 1995, 2034)},
+		},
+	}
+	got, err := ReadTraceroute(fixtureRunner(t, PlatformJunos), "10.255.0.3", TracerouteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadTraceroute() = %+v, want %+v", got, want)
+	}
+
+	var command string
+	run := func(c string) (string, error) {
+		command = c
+		return "<rpc-reply/>", nil
+	}
+	opts := TracerouteOptions{Source: "10.255.0.1", RoutingInstance: "customer-a", TTL: 16}
+	if _, err := ReadTraceroute(run, "192.0.2.10", opts); err != nil {
+		t.Fatal(err)
+	}
+	if want := "traceroute 192.0.2.10 source 10.255.0.1 routing-instance customer-a ttl 16 no-resolve | display xml"; command != want {
+		t.Errorf("ReadTraceroute() ran %q, want %q", command, want)
+	}
+	if _, err := ReadTraceroute(run, "192.0.2.10 | save /var/tmp/x", TracerouteOptions{}); err == nil {
+		t.Error("ReadTraceroute() accepted an invalid target")
+	}
+}
@@ -0,0 +1,301 @@
+package networkapi
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetworkREST ... Interface for library connecting over the Junos REST API
+type NetworkREST interface {
+	ConnectREST(opts RESTOptions) (*RESTSession, error)
+	RPCREST(session *RESTSession, rpc string, params map[string]string, format string) (string, error)
+	GetOutputREST(session *RESTSession, command string, format string) (string, error)
+	GetConfigREST(session *RESTSession, format string) (string, error)
+	GetInterfacesStatisticsREST(session *RESTSession) (InterfacesStatisticsSSH, error)
+	GetBGPSummaryREST(session *RESTSession) (RPCReplyBgp, error)
+	GetLLDPNeighborsInfoREST(session *RESTSession) ([]LLDPNeighbor, error)
+	GetSystemUptimeInfoREST(session *RESTSession) (map[string]SystemUptimeInformation, error)
+	CloseREST(session *RESTSession)
+}
+
+//RESTOptions ... Settings of the connection to the Junos REST API
+type RESTOptions struct {
+	// Port defaults to 3000, or 3443 with HTTPS.
+	Port  int
+	HTTPS bool
+	// CAFile is a PEM file of the certificate authorities trusted, instead of the system ones.
+	CAFile string
+	// Insecure skips the verification of the device certificate.
+	Insecure bool
+	// Timeout of a request, 60 seconds by default.
+	Timeout time.Duration
+	// URL overrides the address built from the hostname, port and scheme, e.g. for a test server.
+	URL string
+}
+
+//RESTSession ... Connection to the REST API of a device
+type RESTSession struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+//ConnectREST ... Prepares the connection to the REST API of the device
+//
+// The REST API is stateless, nothing is sent to the device until the first request.
+func (c *Client) ConnectREST(opts RESTOptions) (*RESTSession, error) {
+
+	base := opts.URL
+	if base == "" {
+		scheme, port := "http", opts.Port
+		if opts.HTTPS {
+			scheme = "https"
+		}
+		if port == 0 {
+			port = 3000
+			if opts.HTTPS {
+				port = 3443
+			}
+		}
+		base = (&url.URL{Scheme: scheme, Host: hostPort(c.Hostname, port)}).String()
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.Insecure}
+	if opts.CAFile != "" {
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.CAFile)
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &RESTSession{
+		url:      strings.TrimSuffix(base, "/"),
+		username: c.Username,
+		password: c.Password,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+//CloseREST ... Closes the idle connections of the session
+func (c *Client) CloseREST(session *RESTSession) {
+	session.client.CloseIdleConnections()
+}
+
+//RPCREST ... Runs an RPC, e.g. get-interface-information, and returns its output in text, xml or json
+//
+// Parameters are the RPC arguments, flags such as "terse" take an empty value.
+func (c *Client) RPCREST(session *RESTSession, rpc string, params map[string]string, format string) (string, error) {
+
+	query := url.Values{}
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	target := session.url + "/rpc/" + url.PathEscape(rpc)
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	return session.do(req, rpc, format)
+}
+
+//GetOutputREST ...Takes command and expected output format as input and returns output in text, JSON or XML based on the output format
+func (c *Client) GetOutputREST(session *RESTSession, command string, format string) (string, error) {
+	body := fmt.Sprintf(`<command format="%s">%s</command>`, restFormat(format), escapeXML(command))
+	return session.post(body, "command", format)
+}
+
+//GetConfigREST ... Returns the configuration in text, set, xml or json
+func (c *Client) GetConfigREST(session *RESTSession, format string) (string, error) {
+	body := fmt.Sprintf(`<get-configuration format="%s"/>`, escapeXML(format))
+	return session.post(body, "get-configuration", format)
+}
+
+//GetInterfacesStatisticsREST ...Returns the status and traffic, error and drop counters of the physical interfaces
+func (c *Client) GetInterfacesStatisticsREST(session *RESTSession) (InterfacesStatisticsSSH, error) {
+	var interfaces InterfacesStatisticsSSH
+	err := c.rpcXML(session, "get-interface-information", map[string]string{"statistics": "", "detail": ""}, &interfaces)
+	return interfaces, err
+}
+
+//GetBGPSummaryREST ... Returns the BGP peers with their prefix counts per table
+func (c *Client) GetBGPSummaryREST(session *RESTSession) (RPCReplyBgp, error) {
+	var bgp RPCReplyBgp
+	err := c.rpcXML(session, "get-bgp-summary-information", nil, &bgp)
+	return bgp, err
+}
+
+//GetLLDPNeighborsInfoREST ...Returns the LLDP neighbors of the device
+func (c *Client) GetLLDPNeighborsInfoREST(session *RESTSession) ([]LLDPNeighbor, error) {
+	var lldp LLDPNeighborsInfoSSH
+	err := c.rpcXML(session, "get-lldp-neighbors-information", nil, &lldp)
+	return lldp.Neighbors, err
+}
+
+//GetSystemUptimeInfoREST ...Returns the uptime of every routing engine, keyed by RE name
+func (c *Client) GetSystemUptimeInfoREST(session *RESTSession) (map[string]SystemUptimeInformation, error) {
+	var uptime SystemUptimeSSH
+	if err := c.rpcXML(session, "get-system-uptime-information", nil, &uptime); err != nil {
+		return nil, err
+	}
+	return uptimeByRE(uptime), nil
+}
+
+// rpcXML runs an RPC and decodes its XML output into v, a struct of an rpc-reply.
+func (c *Client) rpcXML(session *RESTSession, rpc string, params map[string]string, v interface{}) error {
+	output, err := c.RPCREST(session, rpc, params, "xml")
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(rpcReply(output), v)
+}
+
+//Run ... Runs a CLI command, the XML of "| display xml" being asked for as the XML format of the command
+func (s *RESTSession) Run(command string) (string, error) {
+	format := "text"
+	if strings.HasSuffix(command, " | display xml") {
+		command, format = strings.TrimSuffix(command, " | display xml"), "xml"
+	}
+	body := fmt.Sprintf(`<command format="%s">%s</command>`, restFormat(format), escapeXML(command))
+	output, err := s.post(body, "command", format)
+	if err != nil || format != "xml" {
+		return output, err
+	}
+	return string(rpcReply(output)), nil
+}
+
+func (s *RESTSession) post(body, rpc, format string) (string, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url+"/rpc", strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	return s.do(req, rpc, format)
+}
+
+// do sends the request and returns the body of the reply. The replies to a
+// POST on /rpc are multipart, their parts are joined.
+func (s *RESTSession) do(req *http.Request, rpc, format string) (string, error) {
+
+	req.SetBasicAuth(s.username, s.password)
+	req.Header.Set("Accept", restAccept(format))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := readRESTBody(res)
+	if err != nil {
+		return "", fmt.Errorf("rest %s: %s", rpc, err)
+	}
+	if res.StatusCode >= 400 {
+		return "", fmt.Errorf("rest %s: %s: %s", rpc, res.Status, strings.TrimSpace(body))
+	}
+	return body, nil
+}
+
+func readRESTBody(res *http.Response) (string, error) {
+
+	mediaType, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		body, err := ioutil.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	var body strings.Builder
+	reader := multipart.NewReader(res.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return body.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		body.Write(data)
+	}
+}
+
+// restFormat returns the output format of the command RPC.
+func restFormat(format string) string {
+	switch strings.ToLower(format) {
+	case "xml", "json":
+		return strings.ToLower(format)
+	}
+	return "text"
+}
+
+// restAccept returns the Accept header selecting the output format.
+func restAccept(format string) string {
+	switch strings.ToLower(format) {
+	case "xml":
+		return "application/xml"
+	case "json":
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// rpcReply wraps the output of an RPC, which the REST API returns without
+// the <rpc-reply> element the SSH and NETCONF replies have.
+func rpcReply(output string) []byte {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "<?xml") {
+		if end := strings.Index(output, "?>"); end >= 0 {
+			output = output[end+2:]
+		}
+	}
+	if strings.HasPrefix(strings.TrimSpace(output), "<rpc-reply") {
+		return []byte(output)
+	}
+	return []byte("<rpc-reply>" + output + "</rpc-reply>")
+}
+
+func escapeXML(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// hostPort joins the hostname and port, unless the hostname has a port already.
+func hostPort(hostname string, port int) string {
+	if _, _, err := net.SplitHostPort(hostname); err == nil {
+		return hostname
+	}
+	return net.JoinHostPort(strings.Trim(hostname, "[]"), strconv.Itoa(port))
+}
@@ -0,0 +1,238 @@
+package networkapi
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kgrvamsi/networkapi/resttest"
+)
+
+const restBGPReply = `<bgp-information>
+    <group-count>1</group-count>
+    <peer-count>1</peer-count>
+    <down-peer-count>0</down-peer-count>
+    <bgp-peer>
+        <peer-address>10.0.0.2</peer-address>
+        <peer-as>65002</peer-as>
+        <peer-state>Established</peer-state>
+    </bgp-peer>
+</bgp-information>`
+
+func testREST(t *testing.T, srv *resttest.Server, opts RESTOptions) (*Client, *RESTSession) {
+	t.Helper()
+	if opts.URL == "" {
+		opts.URL = srv.URL
+	}
+	c := NetworkClient("router1", "admin", "secret")
+	session, err := c.ConnectREST(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.CloseREST(session) })
+	return c, session
+}
+
+func TestRPCREST(t *testing.T) {
+	srv := resttest.NewServer("admin", "secret")
+	defer srv.Close()
+	srv.Handle("get-bgp-summary-information", restBGPReply)
+	c, session := testREST(t, srv, RESTOptions{})
+
+	output, err := c.RPCREST(session, "get-bgp-summary-information", map[string]string{"neighbor-address": "10.0.0.2", "brief": ""}, "xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != restBGPReply {
+		t.Errorf("RPCREST() = %q, want %q", output, restBGPReply)
+	}
+
+	want := resttest.Request{
+		Method: "GET",
+		RPC:    "get-bgp-summary-information",
+		Params: map[string]string{"neighbor-address": "10.0.0.2", "brief": ""},
+		Accept: "application/xml",
+	}
+	if got := srv.Requests(); len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("requests = %+v, want %+v", got, want)
+	}
+
+	bgp, err := c.GetBGPSummaryREST(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peers := bgp.Bgpinformation.Bgppeer; len(peers) != 1 || peers[0].Peeraddress != "10.0.0.2" {
+		t.Errorf("GetBGPSummaryREST() peers = %+v", peers)
+	}
+}
+
+func TestGetOutputREST(t *testing.T) {
+	srv := resttest.NewServer("admin", "secret")
+	defer srv.Close()
+	srv.HandleFormat("show version", "text", "Hostname: router1\n")
+	srv.HandleFormat("show version", "xml", "<software-information/>")
+	srv.HandleFormat("show version", "json", `{"software-information": []}`)
+	c, session := testREST(t, srv, RESTOptions{})
+
+	tests := []struct {
+		format, want, accept, param string
+	}{
+		{"text", "Hostname: router1\n", "text/plain", "text"},
+		{"", "Hostname: router1\n", "text/plain", "text"},
+		{"xml", "<software-information/>", "application/xml", "xml"},
+		{"JSON", `{"software-information": []}`, "application/json", "json"},
+	}
+	for i, tt := range tests {
+		output, err := c.GetOutputREST(session, "show version", tt.format)
+		if err != nil {
+			t.Fatalf("%q: %s", tt.format, err)
+		}
+		if output != tt.want {
+			t.Errorf("%q: GetOutputREST() = %q, want %q", tt.format, output, tt.want)
+		}
+		req := srv.Requests()[i]
+		if req.Method != "POST" || req.Command != "show version" || req.Accept != tt.accept || req.Params["format"] != tt.param {
+			t.Errorf("%q: request = %+v, want Accept %s and format %s", tt.format, req, tt.accept, tt.param)
+		}
+	}
+}
+
+func TestRESTSessionRun(t *testing.T) {
+	srv := resttest.NewServer("admin", "secret")
+	defer srv.Close()
+	srv.HandleFormat("show bgp summary", "xml", restBGPReply)
+	srv.HandleFormat("show bgp summary", "text", "Groups: 1 Peers: 1 Down peers: 0\n")
+	_, session := testREST(t, srv, RESTOptions{})
+
+	output, err := session.Run("show bgp summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "Groups: 1 Peers: 1 Down peers: 0\n" {
+		t.Errorf("Run() = %q", output)
+	}
+
+	// The multipart XML reply is wrapped in an rpc-reply, like over SSH.
+	bgp, err := ReadBGPSummary(session.Run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bgp.Bgpinformation.Peercount != "1" || len(bgp.Bgpinformation.Bgppeer) != 1 {
+		t.Errorf("ReadBGPSummary() = %+v", bgp.Bgpinformation)
+	}
+	if req := srv.Requests()[1]; req.Command != "show bgp summary" || req.Params["format"] != "xml" {
+		t.Errorf("request = %+v, want the command without the display pipe", req)
+	}
+}
+
+func TestRESTErrors(t *testing.T) {
+	srv := resttest.NewServer("admin", "secret")
+	defer srv.Close()
+	srv.Handle("get-bgp-summary-information", restBGPReply)
+
+	tests := []struct {
+		name     string
+		password string
+		rpc      string
+		want     string
+	}{
+		{"unauthorized", "wrong", "get-bgp-summary-information", "401 Unauthorized"},
+		{"unknown rpc", "secret", "get-nothing", "500 Internal Server Error: syntax error: get-nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NetworkClient("router1", "admin", tt.password)
+			session, err := c.ConnectREST(RESTOptions{URL: srv.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.CloseREST(session)
+			_, err = c.RPCREST(session, tt.rpc, nil, "xml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RPCREST() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRESTTLS(t *testing.T) {
+	srv := resttest.NewTLSServer("admin", "secret")
+	defer srv.Close()
+	srv.Handle("get-bgp-summary-information", restBGPReply)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       RESTOptions
+		connectErr string
+		rpcErr     string
+	}{
+		{"system roots", RESTOptions{}, "", "certificate"},
+		{"ca file", RESTOptions{CAFile: caFile}, "", ""},
+		{"insecure", RESTOptions{Insecure: true}, "", ""},
+		{"no certificate", RESTOptions{CAFile: emptyFile}, "no certificate found", ""},
+		{"missing ca file", RESTOptions{CAFile: filepath.Join(dir, "missing.pem")}, "no such file", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.URL = srv.URL
+			c := NetworkClient("router1", "admin", "secret")
+			session, err := c.ConnectREST(tt.opts)
+			if tt.connectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.connectErr) {
+					t.Errorf("ConnectREST() error = %v, want %q", err, tt.connectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.CloseREST(session)
+
+			_, err = c.RPCREST(session, "get-bgp-summary-information", nil, "xml")
+			switch {
+			case tt.rpcErr == "" && err != nil:
+				t.Errorf("RPCREST() error = %v", err)
+			case tt.rpcErr != "" && (err == nil || !strings.Contains(err.Error(), tt.rpcErr)):
+				t.Errorf("RPCREST() error = %v, want %q", err, tt.rpcErr)
+			}
+		})
+	}
+}
+
+func TestConnectRESTURL(t *testing.T) {
+	tests := []struct {
+		hostname string
+		opts     RESTOptions
+		want     string
+	}{
+		{"router1", RESTOptions{}, "http://router1:3000"},
+		{"router1", RESTOptions{HTTPS: true}, "https://router1:3443"},
+		{"router1", RESTOptions{HTTPS: true, Port: 8443}, "https://router1:8443"},
+		{"router1:8080", RESTOptions{}, "http://router1:8080"},
+		{"2001:db8::1", RESTOptions{}, "http://[2001:db8::1]:3000"},
+		{"router1", RESTOptions{URL: "http://127.0.0.1:1234/"}, "http://127.0.0.1:1234"},
+	}
+	for _, tt := range tests {
+		session, err := NetworkClient(tt.hostname, "admin", "secret").ConnectREST(tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session.url != tt.want {
+			t.Errorf("ConnectREST(%s, %+v) url = %s, want %s", tt.hostname, tt.opts, session.url, tt.want)
+		}
+	}
+}
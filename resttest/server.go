@@ -0,0 +1,184 @@
+// Package resttest provides a local server answering like the Junos REST API,
+// to exercise the REST transport without a device.
+//
+//	srv := resttest.NewServer("admin", "secret")
+//	defer srv.Close()
+//	srv.Handle("get-bgp-summary-information", bgpReply)
+//	c := networkapi.NetworkClient("router1", "admin", "secret")
+//	session, _ := c.ConnectREST(networkapi.RESTOptions{URL: srv.URL})
+package resttest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Server is a fake device serving canned RPC replies with basic
+// authentication.
+type Server struct {
+	*httptest.Server
+
+	username string
+	password string
+
+	mu       sync.Mutex
+	replies  map[string]map[string]string
+	requests []Request
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	// RPC is the RPC name, or "command" with Command set.
+	RPC     string
+	Command string
+	Params  map[string]string
+	Accept  string
+}
+
+// NewServer starts a server over HTTP.
+func NewServer(username, password string) *Server {
+	s := newServer(username, password)
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// NewTLSServer starts a server over HTTPS, with a self-signed certificate
+// trusted by s.Client(), connect with RESTOptions.Insecure.
+func NewTLSServer(username, password string) *Server {
+	s := newServer(username, password)
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+func newServer(username, password string) *Server {
+	return &Server{
+		username: username,
+		password: password,
+		replies:  make(map[string]map[string]string),
+	}
+}
+
+// Handle sets the reply to an RPC, e.g. "get-interface-information", or to a
+// CLI command, e.g. "show version". The reply is returned for every format,
+// unless a reply to the format is set with HandleFormat.
+func (s *Server) Handle(rpc, reply string) {
+	s.HandleFormat(rpc, "", reply)
+}
+
+// HandleFormat sets the reply to an RPC or command in a format: xml, json or text.
+func (s *Server) HandleFormat(rpc, format, reply string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.replies[rpc] == nil {
+		s.replies[rpc] = make(map[string]string)
+	}
+	s.replies[rpc][format] = reply
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	username, password, ok := r.BasicAuth()
+	if !ok || username != s.username || password != s.password {
+		w.Header().Set("WWW-Authenticate", `Basic realm="junos"`)
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+
+	req := Request{Method: r.Method, Accept: r.Header.Get("Accept"), Params: make(map[string]string)}
+	multiPart := false
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/rpc/"):
+		req.RPC = strings.TrimPrefix(r.URL.Path, "/rpc/")
+		for name, values := range r.URL.Query() {
+			req.Params[name] = values[0]
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/rpc":
+		body, _ := ioutil.ReadAll(r.Body)
+		var rpc struct {
+			XMLName xml.Name
+			Attrs   []xml.Attr `xml:",any,attr"`
+			Text    string     `xml:",chardata"`
+		}
+		if err := xml.Unmarshal(body, &rpc); err != nil {
+			http.Error(w, fmt.Sprintf("invalid RPC: %s", err), http.StatusBadRequest)
+			return
+		}
+		req.RPC = rpc.XMLName.Local
+		for _, attr := range rpc.Attrs {
+			req.Params[attr.Name.Local] = attr.Value
+		}
+		if req.RPC == "command" {
+			req.Command = strings.TrimSpace(rpc.Text)
+		}
+		multiPart = true
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	key := req.RPC
+	if req.Command != "" {
+		key = req.Command
+	}
+	format := formats[req.Accept]
+	if f := req.Params["format"]; f != "" {
+		format = f
+	}
+	reply, found := s.replies[key][format]
+	if !found {
+		reply, found = s.replies[key][""]
+	}
+	s.mu.Unlock()
+
+	if !found {
+		http.Error(w, fmt.Sprintf("syntax error: %s", key), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := contentTypes[format]
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	if !multiPart {
+		w.Header().Set("Content-Type", contentType)
+		fmt.Fprint(w, reply)
+		return
+	}
+
+	// The replies to a POST on /rpc are multipart, a part per RPC.
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	part, _ := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	fmt.Fprint(part, reply)
+	mw.Close()
+}
+
+var formats = map[string]string{
+	"application/xml":  "xml",
+	"application/json": "json",
+	"text/plain":       "text",
+}
+
+var contentTypes = map[string]string{
+	"xml":  "application/xml",
+	"json": "application/json",
+	"text": "text/plain",
+	"set":  "text/plain",
+}
@@ -0,0 +1,247 @@
+package networkapi
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	junos "github.com/kgrvamsi/go-junos"
+	"golang.org/x/crypto/ssh"
+)
+
+// Matches of a route query, as show route names them.
+const (
+	RouteMatchExact    = "exact"
+	RouteMatchLonger   = "longer"
+	RouteMatchOrLonger = "orlonger"
+)
+
+type routeXML struct {
+	Destination  string `xml:"rt-destination"`
+	PrefixLength string `xml:"rt-prefix-length"`
+	Entries      []struct {
+		ActiveTag       string    `xml:"active-tag"`
+		CurrentActive   *struct{} `xml:"current-active"`
+		Protocol        string    `xml:"protocol-name"`
+		Preference      string    `xml:"preference"`
+		Preference2     string    `xml:"preference2"`
+		Metric          string    `xml:"metric"`
+		Metric2         string    `xml:"metric2"`
+		LocalPreference string    `xml:"local-preference"`
+		NextHopType     string    `xml:"nh-type"`
+		Age             JunosTime `xml:"age"`
+		ASPath          string    `xml:"as-path"`
+		Communities     []string  `xml:"communities>community"`
+		NextHops        []struct {
+			To             string    `xml:"to"`
+			Via            string    `xml:"via"`
+			LocalInterface string    `xml:"nh-local-interface"`
+			Type           string    `xml:"nh-type"`
+			Selected       *struct{} `xml:"selected-next-hop"`
+		} `xml:"nh"`
+	} `xml:"rt-entry"`
+}
+
+//GetRoutesSSH ...Returns the routes matching the query
+func (c *Client) GetRoutesSSH(session *ssh.Session, query RouteQuery) ([]Route, error) {
+	var routes []Route
+	err := c.StreamRoutesSSH(session, query, func(route Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	return routes, err
+}
+
+//StreamRoutesSSH ...Calls fn for every route matching the query as it is read from the device
+//
+// The routes aren't held in memory, which suits full tables. The session is
+// closed when fn returns an error, which is then returned.
+func (c *Client) StreamRoutesSSH(session *ssh.Session, query RouteQuery, fn func(Route) error) error {
+
+	command, err := routeCommand(query)
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := session.Start(displayCommand(command, "xml")); err != nil {
+		return err
+	}
+	if err := DecodeRoutes(stdout, fn); err != nil {
+		session.Close()
+		return err
+	}
+	return session.Wait()
+}
+
+//GetRoutes ...Returns the routes matching the query
+func (c *Client) GetRoutes(session *junos.Junos, query RouteQuery) ([]Route, error) {
+	return ReadRoutes(NetconfRunner(session), query)
+}
+
+//ReadRoutes ... Returns the routes matching the query of a Junos device
+func ReadRoutes(run Runner, query RouteQuery) ([]Route, error) {
+	command, err := routeCommand(query)
+	if err != nil {
+		return nil, err
+	}
+	output, err := run(displayCommand(command, "xml"))
+	if err != nil {
+		return nil, err
+	}
+	var routes []Route
+	err = DecodeRoutes(strings.NewReader(output), func(route Route) error {
+		routes = append(routes, route)
+		return nil
+	})
+	return routes, err
+}
+
+var routeArgument = regexp.MustCompile(`^[\w.:-]+$`)
+
+// routeCommand returns the show route command of the query, detail being
+// needed for the communities.
+func routeCommand(query RouteQuery) (string, error) {
+
+	command := "show route"
+	if query.Prefix.IsValid() {
+		command += " " + query.Prefix.Masked().String()
+	}
+	switch query.Match {
+	case "":
+	case RouteMatchExact, RouteMatchLonger, RouteMatchOrLonger:
+		if !query.Prefix.IsValid() {
+			return "", fmt.Errorf("route match %s needs a prefix", query.Match)
+		}
+		command += " " + query.Match
+	default:
+		return "", fmt.Errorf("unknown route match %q", query.Match)
+	}
+	if query.Table != "" {
+		if !routeArgument.MatchString(query.Table) {
+			return "", fmt.Errorf("invalid routing table %q", query.Table)
+		}
+		command += " table " + query.Table
+	}
+	if query.Protocol != "" {
+		if !routeArgument.MatchString(query.Protocol) {
+			return "", fmt.Errorf("invalid protocol %q", query.Protocol)
+		}
+		command += " protocol " + query.Protocol
+	}
+	return command + " detail", nil
+}
+
+//DecodeRoutes ... Decodes the XML of show route detail, calling fn for every route as it is read
+//
+// Decoding stops at the first error of fn, which is returned. Errors reported
+// by the device in the reply are returned as errors.
+func DecodeRoutes(r io.Reader, fn func(Route) error) error {
+
+	decoder := xml.NewDecoder(r)
+	table := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "table-name":
+			if err := decoder.DecodeElement(&table, &start); err != nil {
+				return err
+			}
+			table = strings.TrimSpace(table)
+		case "rt":
+			var rt routeXML
+			if err := decoder.DecodeElement(&rt, &start); err != nil {
+				return err
+			}
+			for _, route := range rt.routes(table) {
+				if err := fn(route); err != nil {
+					return err
+				}
+			}
+		case "error":
+			var reply struct {
+				Message string `xml:"message"`
+			}
+			if err := decoder.DecodeElement(&reply, &start); err != nil {
+				return err
+			}
+			return errors.New(strings.TrimSpace(reply.Message))
+		}
+	}
+}
+
+// routes returns a route per entry of the destination. The destination holds
+// the prefix length in the brief output and apart from it in the detail one.
+func (rt routeXML) routes(table string) []Route {
+
+	name := strings.TrimSpace(rt.Destination)
+	if length := strings.TrimSpace(rt.PrefixLength); length != "" && !strings.Contains(name, "/") {
+		name += "/" + length
+	}
+	destination, _ := netip.ParsePrefix(name)
+
+	routes := make([]Route, 0, len(rt.Entries))
+	for _, entry := range rt.Entries {
+		route := Route{
+			Table:           table,
+			Name:            name,
+			Destination:     destination,
+			Protocol:        strings.TrimSpace(entry.Protocol),
+			Preference:      parseInt(entry.Preference),
+			Preference2:     parseInt(entry.Preference2),
+			Metric:          parseInt(entry.Metric),
+			Metric2:         parseInt(entry.Metric2),
+			LocalPreference: parseInt(entry.LocalPreference),
+			Age:             junosDuration(entry.Age),
+			ASPath:          asPath(entry.ASPath),
+			Active:          strings.TrimSpace(entry.ActiveTag) == "*" || entry.CurrentActive != nil,
+		}
+		for _, community := range entry.Communities {
+			route.Communities = append(route.Communities, strings.TrimSpace(community))
+		}
+		for _, nh := range entry.NextHops {
+			nextHop := NextHop{
+				Interface: strings.TrimSpace(nh.Via),
+				Type:      strings.TrimSpace(nh.Type),
+				Selected:  nh.Selected != nil,
+			}
+			if nextHop.Interface == "" {
+				nextHop.Interface = strings.TrimSpace(nh.LocalInterface)
+			}
+			// The type is mostly given once for the entry.
+			if nextHop.Type == "" {
+				nextHop.Type = strings.TrimSpace(entry.NextHopType)
+			}
+			nextHop.Address, _ = netip.ParseAddr(strings.TrimSpace(nh.To))
+			route.NextHops = append(route.NextHops, nextHop)
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// asPath returns the path of "AS path: 65001 65002 I", the detail output
+// adding lines such as "AS path: Recorded" after it.
+func asPath(path string) string {
+	path = strings.TrimSpace(path)
+	if i := strings.IndexByte(path, '\n'); i >= 0 {
+		path = path[:i]
+	}
+	return strings.TrimSpace(strings.TrimPrefix(path, "AS path:"))
+}
@@ -0,0 +1,129 @@
+package networkapi
+
+import (
+	"net/netip"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadRoutes(t *testing.T) {
+	gateway := netip.MustParseAddr("10.0.0.1")
+	want := []Route{
+		{
+			Table: "inet.0", Name: "10.0.0.0/31", Destination: netip.MustParsePrefix("10.0.0.0/31"),
+			Protocol: "Direct", Age: 2923403 * time.Second, ASPath: "I", Active: true,
+			NextHops: []NextHop{{Interface: "ge-0/0/0.0", Type: "Interface", Selected: true}},
+		},
+		{
+			Table: "inet.0", Name: "198.51.100.0/24", Destination: netip.MustParsePrefix("198.51.100.0/24"),
+			Protocol: "BGP", Preference: 170, Preference2: 101, Metric: 100, LocalPreference: 100,
+			Age: 93771 * time.Second, ASPath: "65002 65010 I", Communities: []string{"65002:100", "65002:200"}, Active: true,
+			NextHops: []NextHop{{Address: gateway, Interface: "ge-0/0/0.0", Type: "Router", Selected: true}},
+		},
+		{
+			Table: "inet.0", Name: "198.51.100.0/24", Destination: netip.MustParsePrefix("198.51.100.0/24"),
+			Protocol: "BGP", Preference: 170, Preference2: -101, Metric: 100, LocalPreference: 100,
+			Age: 8831 * time.Second, ASPath: "65003 65020 65010 I",
+			NextHops: []NextHop{{Address: netip.MustParseAddr("10.0.0.5"), Interface: "ge-0/0/1.0", Type: "Router", Selected: true}},
+		},
+		{
+			Table: "mpls.0", Name: "299776", Protocol: "LDP", Preference: 9, Metric: 1,
+			Age: 8820 * time.Second, ASPath: "I", Active: true,
+			NextHops: []NextHop{{Address: gateway, Interface: "ge-0/0/0.0", Type: "Router", Selected: true}},
+		},
+	}
+
+	got, err := ReadRoutes(fixtureRunner(t, PlatformJunos), RouteQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRoutes() = %+v, want %+v", got, want)
+	}
+}
+
+// TestDecodeRoutesBrief decodes the output of show route without detail,
+// where the destination holds the prefix length.
+func TestDecodeRoutesBrief(t *testing.T) {
+	gateway := netip.MustParseAddr("10.0.0.1")
+	want := []Route{
+		{
+			Table: "inet.0", Name: "10.255.0.2/32", Destination: netip.MustParsePrefix("10.255.0.2/32"),
+			Protocol: "OSPF", Preference: 10, Metric: 1, Age: 8831 * time.Second, Active: true,
+			NextHops: []NextHop{{Address: gateway, Interface: "ge-0/0/0.0", Selected: true}},
+		},
+		{
+			Table: "inet.0", Name: "198.51.100.0/24", Destination: netip.MustParsePrefix("198.51.100.0/24"),
+			Protocol: "BGP", Preference: 170, Metric: 100, LocalPreference: 100,
+			Age: 93771 * time.Second, ASPath: "65002 65010 I", Active: true,
+			NextHops: []NextHop{{Address: gateway, Interface: "ge-0/0/0.0", Selected: true}},
+		},
+		{
+			Table: "inet.0", Name: "10.255.0.1/32", Destination: netip.MustParsePrefix("10.255.0.1/32"),
+			Protocol: "Direct", Age: 2923403 * time.Second, Active: true,
+			NextHops: []NextHop{{Interface: "lo0.0", Selected: true}},
+		},
+		{
+			Table: "inet.0", Name: "10.0.0.0/32", Destination: netip.MustParsePrefix("10.0.0.0/32"),
+			Protocol: "Local", Age: 2923403 * time.Second, Active: true,
+			NextHops: []NextHop{{Interface: "ge-0/0/0.0", Type: "Local"}},
+		},
+	}
+
+	f, err := os.Open("testdata/junos/route-brief.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []Route
+	if err := DecodeRoutes(f, func(route Route) error {
+		got = append(got, route)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeRoutes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeRoutesError(t *testing.T) {
+	reply := `<rpc-reply xmlns:junos="http://xml.juniper.net/junos/21.4R3/junos">
+<xnm:error xmlns="http://xml.juniper.net/xnm/1.1/xnm" xmlns:xnm="http://xml.juniper.net/xnm/1.1/xnm">
+<source-daemon>rpd</source-daemon>
+<message>
+invalid table name: inet.9
+</message>
+</xnm:error>
+</rpc-reply>`
+	err := DecodeRoutes(strings.NewReader(reply), func(Route) error { return nil })
+	if err == nil || err.Error() != "invalid table name: inet.9" {
+		t.Errorf("DecodeRoutes() error = %v, want the device error", err)
+	}
+}
+
+func TestRouteCommand(t *testing.T) {
+	prefix := netip.MustParsePrefix("10.0.0.1/24")
+	tests := []struct {
+		query RouteQuery
+		want  string
+		err   bool
+	}{
+		{RouteQuery{}, "show route detail", false},
+		{RouteQuery{Prefix: prefix}, "show route 10.0.0.0/24 detail", false},
+		{RouteQuery{Prefix: prefix, Match: RouteMatchOrLonger, Table: "inet.0", Protocol: "bgp"}, "show route 10.0.0.0/24 orlonger table inet.0 protocol bgp detail", false},
+		{RouteQuery{Match: RouteMatchExact}, "", true},
+		{RouteQuery{Prefix: prefix, Match: "shorter"}, "", true},
+		{RouteQuery{Table: "inet.0 | save /var/tmp/x"}, "", true},
+		{RouteQuery{Protocol: "bgp; exit"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := routeCommand(tt.query)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("routeCommand(%+v) = %q, %v, want %q", tt.query, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+func getInterfaces(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	return driver.GetInterfaces(run)
+}
+
+func getBGP(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	return driver.GetBGPSummary(run)
+}
+
+func getLLDP(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	return driver.GetLLDPNeighbors(run)
+}
+
+func getOptics(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	return driver.GetOptics(run)
+}
+
+func getUptime(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	uptime, err := driver.GetUptime(run)
+	if err != nil {
+		return nil, err
+	}
+	return Uptime{Uptime: uptime.String(), Seconds: int64(uptime / time.Second)}, nil
+}
+
+// getConfig returns the configuration in the format given by the format
+// query parameter: text (the default), set, xml or json. The text format is
+// the configuration of every platform, the others are only shown by Junos.
+func getConfig(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "text", "set", "xml", "json":
+	default:
+		return nil, &APIError{http.StatusBadRequest, fmt.Sprintf("unsupported configuration format %q", format)}
+	}
+
+	driver, err := c.Driver()
+	if err != nil {
+		return nil, err
+	}
+	if format == "" || format == "text" {
+		return driver.GetConfig(run)
+	}
+	if driver.Platform() != networkapi.PlatformJunos {
+		return nil, &APIError{http.StatusBadRequest, fmt.Sprintf("configuration format %q is only supported on Junos, not %s", format, driver.Platform())}
+	}
+
+	output, err := run("show configuration | display " + format)
+	if err != nil || format != "json" {
+		return output, err
+	}
+	return decodeJSON(output)
+}
+
+// postCommand is replaced by commandHandler once the request is validated.
+func postCommand(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+	return nil, &APIError{http.StatusInternalServerError, "command request was not validated"}
+}
+
+// commandHandler reads the command request and returns the handler running it,
+// or an error when the command is not allowlisted.
+func (s *Server) commandHandler(r *http.Request) (handlerFunc, error) {
+
+	var req CommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &APIError{http.StatusBadRequest, "invalid request body - " + err.Error()}
+	}
+
+	switch req.Format {
+	case "":
+		req.Format = "text"
+	case "text", "xml", "json":
+	default:
+		return nil, &APIError{http.StatusBadRequest, fmt.Sprintf("unsupported format %q", req.Format)}
+	}
+
+	command, ok := allowed(req.Command, s.config.Allowlist)
+	if !ok {
+		return nil, &APIError{http.StatusForbidden, fmt.Sprintf("command %q is not allowed", req.Command)}
+	}
+
+	if req.Format != "text" {
+		command += " | display " + req.Format
+	}
+	return func(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error) {
+		output, err := run(command)
+		if err != nil {
+			return nil, err
+		}
+		if req.Format == "json" {
+			return decodeJSON(output)
+		}
+		return output, nil
+	}, nil
+}
+
+// allowed normalizes the command and reports whether it starts with one of the
+// allowlisted prefixes. Pipes and command separators are always refused, so
+// output can't be redirected to files or into other commands.
+func allowed(command string, allowlist []string) (string, bool) {
+
+	if strings.ContainsAny(command, "|;\r\n") {
+		return "", false
+	}
+
+	command = strings.Join(strings.Fields(command), " ")
+	for _, prefix := range allowlist {
+		if command == prefix || strings.HasPrefix(command, prefix+" ") {
+			return command, true
+		}
+	}
+
+	return "", false
+}
+
+func decodeJSON(output string) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON output from device - %s", err)
+	}
+	return data, nil
+}
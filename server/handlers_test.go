@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+func TestAllowed(t *testing.T) {
+	allowlist := []string{"show interfaces", "show bgp summary", "ping"}
+	tests := []struct {
+		command string
+		want    string
+		ok      bool
+	}{
+		{"show interfaces", "show interfaces", true},
+		{"show interfaces ge-0/0/0 extensive", "show interfaces ge-0/0/0 extensive", true},
+		{"  show   bgp\tsummary ", "show bgp summary", true},
+		{"ping 10.0.0.1 count 5", "ping 10.0.0.1 count 5", true},
+		{"show interfaces-descriptions", "", false},
+		{"show bgp neighbor", "", false},
+		{"show", "", false},
+		{"", "", false},
+		{"show interfaces | save /var/tmp/x", "", false},
+		{"show interfaces; request system reboot", "", false},
+		{"show interfaces\nrequest system reboot", "", false},
+		{"show interfaces\r", "", false},
+		{"request system reboot", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := allowed(tt.command, allowlist)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("allowed(%q) = %q, %t, want %q, %t", tt.command, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	outputs := map[string]string{
+		"show configuration":                "system { host-name r1; }\n",
+		"show configuration | display set":  "set system host-name r1\n",
+		"show configuration | display json": `{"configuration": {"system": {"host-name": "r1"}}}`,
+		"show running-config":               "hostname r1\n",
+		"show configuration | display xml":  "<configuration/>",
+	}
+	tests := []struct {
+		platform, format string
+		command          string
+		want             interface{}
+		status           int
+	}{
+		{networkapi.PlatformJunos, "", "show configuration", "system { host-name r1; }\n", 0},
+		{networkapi.PlatformJunos, "text", "show configuration", "system { host-name r1; }\n", 0},
+		{networkapi.PlatformJunos, "set", "show configuration | display set", "set system host-name r1\n", 0},
+		{networkapi.PlatformJunos, "xml", "show configuration | display xml", "<configuration/>", 0},
+		{networkapi.PlatformJunos, "json", "show configuration | display json", map[string]interface{}{
+			"configuration": map[string]interface{}{"system": map[string]interface{}{"host-name": "r1"}},
+		}, 0},
+		{networkapi.PlatformJunos, "inline", "", nil, http.StatusBadRequest},
+		{networkapi.PlatformNXOS, "", "show running-config", "hostname r1\n", 0},
+		{networkapi.PlatformEOS, "text", "show running-config", "hostname r1\n", 0},
+		{networkapi.PlatformNXOS, "set", "", nil, http.StatusBadRequest},
+		{networkapi.PlatformEOS, "xml", "", nil, http.StatusBadRequest},
+		{networkapi.PlatformIOSXR, "json", "", nil, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		c := networkapi.NetworkClient("r1", "admin", "secret")
+		c.Platform = tt.platform
+		var commands []string
+		run := func(command string) (string, error) {
+			commands = append(commands, command)
+			return outputs[command], nil
+		}
+		r := httptest.NewRequest(http.MethodGet, "/devices/r1/config?format="+tt.format, nil)
+
+		got, err := getConfig(c, run, r)
+		var apiErr *APIError
+		switch {
+		case tt.status != 0:
+			if !errors.As(err, &apiErr) || apiErr.Status != tt.status {
+				t.Errorf("%s %q: getConfig() error = %v, want status %d", tt.platform, tt.format, err, tt.status)
+			}
+			if len(commands) > 0 {
+				t.Errorf("%s %q: ran %q, want no command", tt.platform, tt.format, commands)
+			}
+		case err != nil:
+			t.Errorf("%s %q: getConfig() error = %v", tt.platform, tt.format, err)
+		case !reflect.DeepEqual(got, tt.want) || len(commands) != 1 || commands[0] != tt.command:
+			t.Errorf("%s %q: getConfig() = %#v running %q, want %#v running %q", tt.platform, tt.format, got, commands, tt.want, tt.command)
+		}
+	}
+}
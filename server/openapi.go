@@ -0,0 +1,463 @@
+package server
+
+// openAPISpec describes the endpoints, it is served at /openapi.json.
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "networkapi",
+    "version": "1.0.0",
+    "description": "Collects data from the network devices of the inventory."
+  },
+  "paths": {
+    "/devices/{host}/interfaces": {
+      "get": {
+        "summary": "Interfaces with their status, description and speed",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "type": "array",
+                      "items": {
+                        "$ref": "#/components/schemas/Interface"
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/bgp": {
+      "get": {
+        "summary": "BGP peers with their state and prefix counts",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "type": "array",
+                      "items": {
+                        "$ref": "#/components/schemas/BGPPeer"
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/lldp": {
+      "get": {
+        "summary": "LLDP neighbors",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "type": "array",
+                      "items": {
+                        "$ref": "#/components/schemas/LLDPNeighbor"
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/optics": {
+      "get": {
+        "summary": "Optics diagnostics of the interfaces",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "type": "array",
+                      "items": {
+                        "$ref": "#/components/schemas/Optics"
+                      }
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/config": {
+      "get": {
+        "summary": "Configuration of the device",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          },
+          {
+            "name": "format",
+            "in": "query",
+            "description": "The set, xml and json formats are only supported on Junos.",
+            "schema": {
+              "type": "string",
+              "enum": [
+                "text",
+                "set",
+                "xml",
+                "json"
+              ],
+              "default": "text"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "oneOf": [
+                        {
+                          "type": "string"
+                        },
+                        {
+                          "type": "object"
+                        }
+                      ]
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/uptime": {
+      "get": {
+        "summary": "Uptime of the device",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "$ref": "#/components/schemas/Uptime"
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    },
+    "/devices/{host}/command": {
+      "post": {
+        "summary": "Run an allowlisted operational command",
+        "parameters": [
+          {
+            "$ref": "#/components/parameters/host"
+          }
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/CommandRequest"
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "properties": {
+                    "host": {
+                      "type": "string"
+                    },
+                    "data": {
+                      "oneOf": [
+                        {
+                          "type": "string"
+                        },
+                        {
+                          "type": "object"
+                        }
+                      ]
+                    }
+                  }
+                }
+              }
+            }
+          },
+          "default": {
+            "$ref": "#/components/responses/error"
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "host": {
+        "name": "host",
+        "in": "path",
+        "required": true,
+        "description": "Hostname of an inventory device",
+        "schema": {
+          "type": "string"
+        }
+      }
+    },
+    "responses": {
+      "error": {
+        "description": "Error",
+        "content": {
+          "application/json": {
+            "schema": {
+              "type": "object",
+              "properties": {
+                "error": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {
+          "status": {
+            "type": "integer"
+          },
+          "message": {
+            "type": "string"
+          }
+        }
+      },
+      "Interface": {
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string"
+          },
+          "admin_status": {
+            "type": "string"
+          },
+          "oper_status": {
+            "type": "string"
+          },
+          "description": {
+            "type": "string"
+          },
+          "speed": {
+            "type": "integer"
+          }
+        }
+      },
+      "BGPPeer": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "type": "string"
+          },
+          "as": {
+            "type": "string"
+          },
+          "state": {
+            "type": "string"
+          },
+          "prefixes_received": {
+            "type": "integer"
+          },
+          "prefixes_accepted": {
+            "type": "integer"
+          }
+        }
+      },
+      "LLDPNeighbor": {
+        "type": "object",
+        "properties": {
+          "local_port_id": {
+            "type": "string"
+          },
+          "local_interface": {
+            "type": "string"
+          },
+          "local_parent_interface": {
+            "type": "string"
+          },
+          "remote_chassis_id_subtype": {
+            "type": "string"
+          },
+          "remote_chassis_id": {
+            "type": "string"
+          },
+          "remote_port_id_subtype": {
+            "type": "string"
+          },
+          "remote_port_id": {
+            "type": "string"
+          },
+          "remote_port_description": {
+            "type": "string"
+          },
+          "remote_system_name": {
+            "type": "string"
+          }
+        }
+      },
+      "Optics": {
+        "type": "object",
+        "properties": {
+          "interface": {
+            "type": "string"
+          },
+          "lane": {
+            "type": "integer"
+          },
+          "temperature": {
+            "type": "number"
+          },
+          "tx_power": {
+            "type": "number"
+          },
+          "rx_power": {
+            "type": "number"
+          }
+        }
+      },
+      "Uptime": {
+        "type": "object",
+        "properties": {
+          "uptime": {
+            "type": "string"
+          },
+          "seconds": {
+            "type": "integer"
+          }
+        }
+      },
+      "CommandRequest": {
+        "type": "object",
+        "required": [
+          "command"
+        ],
+        "properties": {
+          "command": {
+            "type": "string",
+            "example": "show chassis alarms"
+          },
+          "format": {
+            "type": "string",
+            "enum": [
+              "text",
+              "xml",
+              "json"
+            ],
+            "default": "text"
+          }
+        }
+      }
+    }
+  }
+}
+`
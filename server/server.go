@@ -0,0 +1,247 @@
+// Package server exposes the networkapi collection methods over HTTP as JSON.
+//
+// Devices are looked up in the inventory by hostname, so only devices listed
+// there can be queried. Arbitrary commands are limited to an allowlist.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// DefaultAllowlist holds the command prefixes accepted by POST /devices/{host}/command
+// when none are configured. The configuration is only served by
+// GET /devices/{host}/config.
+var DefaultAllowlist = []string{
+	"show arp",
+	"show bgp",
+	"show chassis",
+	"show interfaces",
+	"show lacp",
+	"show ldp",
+	"show lldp",
+	"show mpls",
+	"show route",
+	"show rsvp",
+	"show system",
+	"show version",
+}
+
+// Config holds the settings of the server.
+type Config struct {
+	Inventory *networkapi.Inventory
+	Timeout   time.Duration
+	Allowlist []string
+}
+
+// Server serves the device endpoints.
+type Server struct {
+	config Config
+
+	mu      sync.Mutex
+	clients map[string]*networkapi.Client
+}
+
+// APIError is the body of every error response.
+type APIError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// CommandRequest is the body of POST /devices/{host}/command.
+type CommandRequest struct {
+	Command string `json:"command"`
+	Format  string `json:"format"`
+}
+
+type response struct {
+	Host string      `json:"host"`
+	Data interface{} `json:"data"`
+}
+
+// Uptime is the body of GET /devices/{host}/uptime.
+type Uptime struct {
+	Uptime  string `json:"uptime"`
+	Seconds int64  `json:"seconds"`
+}
+
+type handlerFunc func(c *networkapi.Client, run networkapi.Runner, r *http.Request) (interface{}, error)
+
+// New returns a server for the devices of the inventory.
+func New(config Config) *Server {
+
+	if config.Timeout == 0 {
+		config.Timeout = 60 * time.Second
+	}
+	if config.Allowlist == nil {
+		config.Allowlist = DefaultAllowlist
+	}
+
+	return &Server{
+		config:  config,
+		clients: make(map[string]*networkapi.Client),
+	}
+}
+
+// Close closes the connections to the devices.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for host, client := range s.clients {
+		client.DisconnectSSH()
+		delete(s.clients, host)
+	}
+}
+
+// ServeHTTP routes the request to the handler of the resource.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path == "/openapi.json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(openAPISpec))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "devices" {
+		writeError(w, &APIError{http.StatusNotFound, "no such endpoint " + r.URL.Path})
+		return
+	}
+	host, resource := parts[1], parts[2]
+
+	handler, method := route(resource)
+	if handler == nil {
+		writeError(w, &APIError{http.StatusNotFound, "no such resource " + resource})
+		return
+	}
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, &APIError{http.StatusMethodNotAllowed, "method " + r.Method + " not allowed"})
+		return
+	}
+
+	client, err := s.client(host)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if resource == "command" {
+		// The command is checked before connecting to the device.
+		if handler, err = s.commandHandler(r); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	data, err := s.call(r.Context(), client, handler, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response{Host: host, Data: data})
+}
+
+func route(resource string) (handlerFunc, string) {
+	switch resource {
+	case "interfaces":
+		return getInterfaces, http.MethodGet
+	case "bgp":
+		return getBGP, http.MethodGet
+	case "lldp":
+		return getLLDP, http.MethodGet
+	case "optics":
+		return getOptics, http.MethodGet
+	case "config":
+		return getConfig, http.MethodGet
+	case "uptime":
+		return getUptime, http.MethodGet
+	case "command":
+		return postCommand, http.MethodPost
+	}
+	return nil, ""
+}
+
+// client returns the client of an inventory device, creating it on first use
+// so the SSH connection is shared between requests.
+func (s *Server) client(host string) (*networkapi.Client, error) {
+
+	device, ok := s.config.Inventory.Lookup(host)
+	if !ok {
+		return nil, &APIError{http.StatusNotFound, "unknown device " + host}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, ok := s.clients[device.Hostname]
+	if !ok {
+		client = s.config.Inventory.Client(device)
+		s.clients[device.Hostname] = client
+	}
+	return client, nil
+}
+
+// call runs the handler, each command in a session of its own. The session is
+// closed when the request times out or is cancelled, which aborts the running
+// command.
+func (s *Server) call(ctx context.Context, c *networkapi.Client, handler handlerFunc, r *http.Request) (interface{}, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	run := func(command string) (string, error) {
+		return c.RunSSHContext(ctx, command)
+	}
+
+	type outcome struct {
+		data interface{}
+		err  error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		data, err := handler(c, run, r)
+		done <- outcome{data, err}
+	}()
+
+	select {
+	case out := <-done:
+		var apiErr *APIError
+		if out.err != nil && !errors.As(out.err, &apiErr) {
+			out.err = &APIError{http.StatusBadGateway, out.err.Error()}
+		}
+		return out.data, out.err
+	case <-ctx.Done():
+		return nil, &APIError{http.StatusGatewayTimeout, "device did not answer within " + s.config.Timeout.String()}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		apiErr = &APIError{http.StatusInternalServerError, err.Error()}
+	}
+	writeJSON(w, apiErr.Status, struct {
+		Error *APIError `json:"error"`
+	}{apiErr})
+}
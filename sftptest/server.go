@@ -0,0 +1,339 @@
+// Package sftptest provides a local SSH server serving SFTP and scp from an
+// in-memory file system, to exercise file transfers without a device.
+//
+//	srv, _ := sftptest.NewServer("admin", "secret")
+//	defer srv.Close()
+//	srv.WriteFile("/var/log/messages", logs)
+//	c := networkapi.NetworkClient(srv.Addr, "admin", "secret")
+//	err := c.GetFileSSH("/var/log/messages", "messages", networkapi.TransferOptions{Verify: true})
+//
+// The server answers file checksum sha-256 like Junos, and the commands given
+// an output with Handle. It starts with the /var/tmp, /var/log and /var/crash
+// directories.
+package sftptest
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTP open flags of the requests made to the handlers.
+const (
+	flagRead  = 0x01
+	flagWrite = 0x02
+	flagCreat = 0x08
+	flagTrunc = 0x10
+)
+
+// Server is a fake device serving SFTP and scp with password
+// authentication.
+type Server struct {
+	// Addr is the host:port of the server, to use as the hostname of the client.
+	Addr string
+
+	config   *ssh.ServerConfig
+	listener net.Listener
+	handlers sftp.Handlers
+
+	mu       sync.Mutex
+	commands []string
+	outputs  map[string]string
+	conns    []net.Conn
+}
+
+// NewServer starts a server on a local port.
+func NewServer(username, password string) (*Server, error) {
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(meta ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if meta.User() == username && string(pass) == password {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("password rejected for %s", meta.User())
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		Addr:     listener.Addr().String(),
+		config:   config,
+		listener: listener,
+		handlers: sftp.InMemHandler(),
+		outputs:  make(map[string]string),
+	}
+	for _, dir := range []string{"/var", "/var/tmp", "/var/log", "/var/crash"} {
+		if err := s.handlers.FileCmd.Filecmd(sftp.NewRequest("Mkdir", dir)); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Close stops the server and closes its connections.
+func (s *Server) Close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// WriteFile writes a file of the server, its directory having to exist.
+func (s *Server) WriteFile(name string, data []byte) error {
+	req := sftp.NewRequest("Put", name)
+	req.Flags = flagWrite | flagCreat | flagTrunc
+	w, err := s.handlers.FilePut.Filewrite(req)
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteAt(data, 0)
+	return err
+}
+
+// ReadFile returns the content of a file of the server.
+func (s *Server) ReadFile(name string) ([]byte, error) {
+	size, err := s.size(name)
+	if err != nil {
+		return nil, err
+	}
+	req := sftp.NewRequest("Get", name)
+	req.Flags = flagRead
+	r, err := s.handlers.FileGet.Fileread(req)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *Server) size(name string) (int64, error) {
+	lister, err := s.handlers.FileList.Filelist(sftp.NewRequest("Stat", name))
+	if err != nil {
+		return 0, err
+	}
+	infos := make([]os.FileInfo, 1)
+	if n, err := lister.ListAt(infos, 0); n == 0 {
+		return 0, err
+	}
+	return infos[0].Size(), nil
+}
+
+// Handle sets the output of a command, e.g. "show interfaces | display xml".
+func (s *Server) Handle(command, output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outputs[command] = output
+}
+
+// Commands returns the commands run on the server, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.handleSession(channel, requests)
+	}
+}
+
+// handleSession serves the sftp subsystem or runs the command of an exec
+// request.
+func (s *Server) handleSession(channel ssh.Channel, requests <-chan *ssh.Request) {
+
+	for req := range requests {
+		switch req.Type {
+		case "subsystem":
+			if payload(req.Payload) != "sftp" {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+			server := sftp.NewRequestServer(channel, s.handlers)
+			server.Serve()
+			server.Close()
+			return
+		case "exec":
+			req.Reply(true, nil)
+			command := payload(req.Payload)
+			s.mu.Lock()
+			s.commands = append(s.commands, command)
+			s.mu.Unlock()
+			status := s.run(channel, command)
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			channel.Close()
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// payload returns the string of a subsystem or exec request.
+func payload(b []byte) string {
+	if len(b) < 4 {
+		return ""
+	}
+	n := binary.BigEndian.Uint32(b)
+	if int(n) > len(b)-4 {
+		return ""
+	}
+	return string(b[4 : 4+n])
+}
+
+// run runs the commands given an output, file checksum sha-256, scp -t and
+// scp -f, returning the exit status.
+func (s *Server) run(channel ssh.Channel, command string) uint32 {
+
+	s.mu.Lock()
+	output, found := s.outputs[command]
+	s.mu.Unlock()
+	if found {
+		io.WriteString(channel, output)
+		return 0
+	}
+
+	fields := strings.Fields(command)
+	switch {
+	case len(fields) == 4 && fields[0] == "file" && fields[1] == "checksum" && fields[2] == "sha-256":
+		data, err := s.ReadFile(fields[3])
+		if err != nil {
+			fmt.Fprintf(channel.Stderr(), "error: could not open %s\n", fields[3])
+			return 1
+		}
+		fmt.Fprintf(channel, "SHA256 (%s) = %x\n", fields[3], sha256.Sum256(data))
+		return 0
+	case len(fields) == 3 && fields[0] == "scp" && fields[1] == "-t":
+		return s.scpSink(channel, fields[2])
+	case len(fields) == 3 && fields[0] == "scp" && fields[1] == "-f":
+		return s.scpSource(channel, fields[2])
+	}
+	fmt.Fprintf(channel.Stderr(), "error: unknown command: %s\n", command)
+	return 1
+}
+
+// scpSink receives a file sent with scp -t.
+func (s *Server) scpSink(channel ssh.Channel, name string) uint32 {
+
+	reader := bufio.NewReader(channel)
+	channel.Write([]byte{0})
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 1
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[0], "C") {
+		fmt.Fprintf(channel, "\x01unexpected %q\n", line)
+		return 1
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		fmt.Fprintf(channel, "\x01invalid size %q\n", fields[1])
+		return 1
+	}
+	if strings.HasSuffix(name, "/") {
+		name = path.Join(name, fields[2])
+	}
+	channel.Write([]byte{0})
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 1
+	}
+	if _, err := reader.ReadByte(); err != nil {
+		return 1
+	}
+	if err := s.WriteFile(name, data); err != nil {
+		fmt.Fprintf(channel, "\x01%s: %s\n", name, err)
+		return 1
+	}
+	channel.Write([]byte{0})
+	return 0
+}
+
+// scpSource sends a file asked for with scp -f.
+func (s *Server) scpSource(channel ssh.Channel, name string) uint32 {
+
+	reader := bufio.NewReader(channel)
+	if _, err := reader.ReadByte(); err != nil {
+		return 1
+	}
+	data, err := s.ReadFile(name)
+	if err != nil {
+		fmt.Fprintf(channel, "\x01%s: %s\n", name, err)
+		return 1
+	}
+	fmt.Fprintf(channel, "C0644 %d %s\n", len(data), path.Base(name))
+	if _, err := reader.ReadByte(); err != nil {
+		return 1
+	}
+	channel.Write(data)
+	channel.Write([]byte{0})
+	if _, err := reader.ReadByte(); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,331 @@
+// Package snapshot takes snapshots of the state of devices before and after a
+// change and compares them with declarative tests.
+//
+// A snapshot holds the records of a set of collectors, BGP peers, interfaces
+// and LLDP neighbors by default, OSPF neighbors and IS-IS adjacencies when
+// asked for. Records are flat maps of fields identified by the key field of
+// their collector, the peer address for BGP, so tests can match the records
+// of two snapshots without knowing what they are.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kgrvamsi/networkapi"
+	"golang.org/x/crypto/ssh"
+)
+
+// Record is an item collected from a device, such as a BGP peer.
+type Record map[string]string
+
+// Collector collects records from a device.
+type Collector struct {
+	// Key is the field identifying a record in two snapshots.
+	Key     string
+	Collect func(c *networkapi.Client, session *ssh.Session) ([]Record, error)
+}
+
+// Collectors lists the available collectors by name, more can be registered.
+var Collectors = map[string]Collector{
+	"bgp":        {Key: "peer-address", Collect: collectBGP},
+	"interfaces": {Key: "name", Collect: collectInterfaces},
+	"lldp":       {Key: "neighbor", Collect: collectLLDP},
+	"ospf":       {Key: "neighbor", Collect: collectOSPF(2)},
+	"ospf3":      {Key: "neighbor", Collect: collectOSPF(3)},
+	"isis":       {Key: "adjacency", Collect: collectISIS},
+	"isis-lsps":  {Key: "lsp", Collect: collectISISDatabase},
+}
+
+// DefaultCollectors lists the collectors run when none are given.
+var DefaultCollectors = []string{"bgp", "interfaces", "lldp"}
+
+// Snapshot is the state of a device at a point in time.
+type Snapshot struct {
+	Name    string              `json:"name"`
+	Host    string              `json:"host"`
+	Taken   time.Time           `json:"taken"`
+	Records map[string][]Record `json:"records"`
+	Errors  map[string]string   `json:"errors,omitempty"`
+}
+
+// Take runs the collectors on the device. A collector failing is recorded in
+// the snapshot, an error is only returned when the device can't be reached.
+func Take(c *networkapi.Client, host, name string, collectors []string) (*Snapshot, error) {
+
+	if len(collectors) == 0 {
+		collectors = DefaultCollectors
+	}
+	for _, collector := range collectors {
+		if _, ok := Collectors[collector]; !ok {
+			return nil, fmt.Errorf("unknown collector %q", collector)
+		}
+	}
+
+	defer c.DisconnectSSH()
+
+	s := &Snapshot{
+		Name:    name,
+		Host:    host,
+		Taken:   time.Now().UTC(),
+		Records: make(map[string][]Record),
+		Errors:  make(map[string]string),
+	}
+	for _, collector := range collectors {
+		session, err := c.ConnectSSH()
+		if err != nil {
+			return nil, err
+		}
+		records, err := Collectors[collector].Collect(c, session)
+		c.CloseSSH(session)
+		if err != nil {
+			s.Errors[collector] = err.Error()
+			continue
+		}
+		s.Records[collector] = records
+	}
+
+	return s, nil
+}
+
+// TakeAll takes a snapshot of the devices and saves them in dir. It returns
+// the errors by host.
+func TakeAll(inventory *networkapi.Inventory, devices []networkapi.Device, dir, name string, collectors []string, parallel int) map[string]error {
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errors = make(map[string]error)
+	)
+
+	sem := make(chan struct{}, parallel)
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d networkapi.Device) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			s, err := Take(inventory.Client(d), d.Hostname, name, collectors)
+			if err == nil {
+				err = s.Save(dir)
+			}
+			if err != nil {
+				mu.Lock()
+				errors[d.Hostname] = err
+				mu.Unlock()
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	return errors
+}
+
+// Save writes the snapshot to dir/<name>/<host>.json.
+func (s *Snapshot) Save(dir string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, s.Name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename(dir, s.Name, s.Host), data, 0644)
+}
+
+// Load reads the snapshot of a device saved by Save.
+func Load(dir, name, host string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(filename(dir, name, host))
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("error reading snapshot %s of %s - %s", name, host, err)
+	}
+	return &s, nil
+}
+
+// Hosts returns the hosts having a snapshot of the given name in dir.
+func Hosts(dir, name string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, name, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, len(files))
+	for i, file := range files {
+		hosts[i] = strings.TrimSuffix(filepath.Base(file), ".json")
+	}
+	sort.Strings(hosts)
+	return hosts, nil
+}
+
+func filename(dir, name, host string) string {
+	return filepath.Join(dir, name, host+".json")
+}
+
+func collectBGP(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+	bgp, err := c.GetBGPSummarySSH(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, peer := range bgp.Bgpinformation.Bgppeer {
+		var active, received, accepted int
+		for _, rib := range peer.Bgprib {
+			active += atoi(rib.Activeprefixcount)
+			received += atoi(rib.Receivedprefixcount)
+			accepted += atoi(rib.Acceptedprefixcount)
+		}
+		records = append(records, Record{
+			"peer-address":      strings.TrimSpace(peer.Peeraddress),
+			"peer-as":           strings.TrimSpace(peer.Peeras),
+			"state":             strings.TrimSpace(peer.Peerstate),
+			"flap-count":        strings.TrimSpace(peer.Flapcount),
+			"active-prefixes":   strconv.Itoa(active),
+			"received-prefixes": strconv.Itoa(received),
+			"accepted-prefixes": strconv.Itoa(accepted),
+		})
+	}
+	return records, nil
+}
+
+func collectInterfaces(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+	interfaces, err := c.GetInterfacesStatisticsSSH(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, intf := range interfaces.PhysicalInterface {
+		records = append(records, Record{
+			"name":         strings.TrimSpace(intf.Name),
+			"admin-status": strings.TrimSpace(intf.Adminstatus),
+			"oper-status":  strings.TrimSpace(intf.Operstatus),
+			"description":  strings.TrimSpace(intf.Description),
+			"speed":        strings.TrimSpace(intf.Speed),
+		})
+	}
+	return records, nil
+}
+
+// collectLLDP collects the LLDP neighbors, keyed by local port, remote
+// chassis and remote port as a port may have several neighbors.
+func collectLLDP(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+	neighbors, err := c.GetLLDPNeighborsInfoSSH(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, n := range neighbors {
+		local, chassis, port := strings.TrimSpace(n.LocalPort()), strings.TrimSpace(n.RemoteChassisID), strings.TrimSpace(n.RemotePort())
+		records = append(records, Record{
+			"neighbor":          local + " " + chassis + " " + port,
+			"local-port":        local,
+			"remote-system":     strings.TrimSpace(n.RemoteSystemName),
+			"remote-port":       port,
+			"remote-chassis-id": chassis,
+		})
+	}
+	return records, nil
+}
+
+// collectOSPF collects the OSPF neighbors, keyed by interface and router ID
+// as a router may be a neighbor on several interfaces.
+func collectOSPF(version int) func(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+	return func(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+		neighbors, err := c.GetOSPFNeighborsSSH(session, version)
+		if err != nil {
+			return nil, err
+		}
+
+		var records []Record
+		for _, n := range neighbors {
+			address := ""
+			if n.Address.IsValid() {
+				address = n.Address.String()
+			}
+			records = append(records, Record{
+				"neighbor":  n.Interface + " " + n.RouterID,
+				"interface": n.Interface,
+				"router-id": n.RouterID,
+				"address":   address,
+				"state":     n.State,
+				"area":      n.Area,
+				"dr":        n.DR,
+				"bdr":       n.BDR,
+			})
+		}
+		return records, nil
+	}
+}
+
+// collectISIS collects the IS-IS adjacencies, keyed by interface, system and level.
+func collectISIS(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+	adjacencies, err := c.GetISISAdjacenciesSSH(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, a := range adjacencies {
+		level := strconv.Itoa(a.Level)
+		records = append(records, Record{
+			"adjacency": a.Interface + " " + a.System + " L" + level,
+			"interface": a.Interface,
+			"system":    a.System,
+			"level":     level,
+			"state":     a.State,
+			"snpa":      a.SNPA,
+		})
+	}
+	return records, nil
+}
+
+// collectISISDatabase collects the LSPs of the IS-IS database, keyed by level
+// and LSP ID.
+func collectISISDatabase(c *networkapi.Client, session *ssh.Session) ([]Record, error) {
+
+	lsps, err := c.GetISISDatabaseSSH(session)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, lsp := range lsps {
+		level := strconv.Itoa(lsp.Level)
+		records = append(records, Record{
+			"lsp":        "L" + level + " " + lsp.LSPID,
+			"level":      level,
+			"lsp-id":     lsp.LSPID,
+			"sequence":   strconv.FormatUint(lsp.Sequence, 10),
+			"attributes": lsp.Attributes,
+		})
+	}
+	return records, nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
@@ -0,0 +1,320 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Checks lists the checks a test can run:
+//   - no-diff: the records are in both snapshots, with the same fields,
+//   - list-not-less: the records of the pre snapshot are still there, with the same fields,
+//   - list-not-more: the records of the post snapshot were already there,
+//   - count: the number of records changed by at most the tolerance,
+//   - delta: the numeric fields of the records changed by at most the tolerance.
+var Checks = []string{"no-diff", "list-not-less", "list-not-more", "count", "delta"}
+
+// Test is a declarative comparison of two snapshots.
+//
+// Where restricts the test to the records whose fields have the given values,
+// compared without case. Fields lists the fields compared. Tolerance is an
+// absolute value, "2", or a percentage of the pre value, "10%".
+//
+// "No BGP peer that was Established is down" is
+//
+//	{Collector: "bgp", Check: "list-not-less", Where: {"state": "Established"}, Fields: ["state"]}
+type Test struct {
+	Name      string            `json:"name" yaml:"name"`
+	Collector string            `json:"collector" yaml:"collector"`
+	Check     string            `json:"check" yaml:"check"`
+	Where     map[string]string `json:"where,omitempty" yaml:"where,omitempty"`
+	Fields    []string          `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Tolerance string            `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+}
+
+type testFile struct {
+	Tests []Test `json:"tests" yaml:"tests"`
+}
+
+// LoadTests reads the tests from a YAML or JSON file.
+func LoadTests(filename string) ([]Test, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file testFile
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported tests format %q", filepath.Ext(filename))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading tests %s - %s", filename, err)
+	}
+
+	for i, test := range file.Tests {
+		if err := test.Validate(); err != nil {
+			return nil, fmt.Errorf("tests %s: test %d: %s", filename, i, err)
+		}
+	}
+	return file.Tests, nil
+}
+
+// Validate reports whether the test is complete.
+func (t Test) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("test has no name")
+	}
+	if _, ok := Collectors[t.Collector]; !ok {
+		return fmt.Errorf("%s: unknown collector %q", t.Name, t.Collector)
+	}
+	known := false
+	for _, check := range Checks {
+		known = known || check == t.Check
+	}
+	if !known {
+		return fmt.Errorf("%s: unknown check %q", t.Name, t.Check)
+	}
+	if t.Check == "delta" && len(t.Fields) == 0 {
+		return fmt.Errorf("%s: delta needs the fields to compare", t.Name)
+	}
+	if _, _, err := parseTolerance(t.Tolerance); err != nil {
+		return fmt.Errorf("%s: %s", t.Name, err)
+	}
+	return nil
+}
+
+// TestResult is the outcome of a test.
+type TestResult struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Failures []string `json:"failures,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Report is the outcome of the tests comparing two snapshots of a device.
+type Report struct {
+	Host    string       `json:"host"`
+	Pre     string       `json:"pre"`
+	Post    string       `json:"post"`
+	Results []TestResult `json:"results"`
+}
+
+// Passed reports whether every test passed.
+func (r Report) Passed() bool {
+	for _, res := range r.Results {
+		if !res.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare runs the tests on the pre and post snapshots of a device.
+func Compare(pre, post *Snapshot, tests []Test) Report {
+
+	report := Report{
+		Host: post.Host,
+		Pre:  fmt.Sprintf("%s (%s)", pre.Name, pre.Taken.Format("2006-01-02 15:04:05 MST")),
+		Post: fmt.Sprintf("%s (%s)", post.Name, post.Taken.Format("2006-01-02 15:04:05 MST")),
+	}
+	for _, test := range tests {
+		res := TestResult{Name: test.Name}
+		failures, err := test.run(pre, post)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Failures = failures
+			res.Passed = len(failures) == 0
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func (t Test) run(pre, post *Snapshot) ([]string, error) {
+
+	collector, ok := Collectors[t.Collector]
+	if !ok {
+		return nil, fmt.Errorf("unknown collector %q", t.Collector)
+	}
+	for _, s := range []*Snapshot{pre, post} {
+		if msg, failed := s.Errors[t.Collector]; failed {
+			return nil, fmt.Errorf("%s snapshot: %s failed: %s", s.Name, t.Collector, msg)
+		}
+		if _, taken := s.Records[t.Collector]; !taken {
+			return nil, fmt.Errorf("%s snapshot has no %s records", s.Name, t.Collector)
+		}
+	}
+
+	before := index(pre.Records[t.Collector], collector.Key)
+	after := index(post.Records[t.Collector], collector.Key)
+	preKeys := t.selected(before)
+	postKeys := t.selected(after)
+
+	var failures []string
+	switch t.Check {
+	case "no-diff", "list-not-less", "list-not-more":
+		var keys []string
+		if t.Check != "list-not-more" {
+			keys = append(keys, preKeys...)
+		}
+		if t.Check != "list-not-less" {
+			for _, key := range postKeys {
+				if t.Check == "list-not-more" || !contains(preKeys, key) {
+					keys = append(keys, key)
+				}
+			}
+		}
+		for _, key := range keys {
+			a, inPre := before[key]
+			b, inPost := after[key]
+			switch {
+			case !inPost:
+				failures = append(failures, fmt.Sprintf("%s %s missing", collector.Key, key))
+			case !inPre:
+				failures = append(failures, fmt.Sprintf("%s %s added", collector.Key, key))
+			default:
+				for _, field := range t.Fields {
+					if a[field] != b[field] {
+						failures = append(failures, fmt.Sprintf("%s %s: %s %q -> %q", collector.Key, key, field, a[field], b[field]))
+					}
+				}
+			}
+		}
+
+	case "count":
+		if !within(float64(len(preKeys)), float64(len(postKeys)), t.Tolerance) {
+			failures = append(failures, fmt.Sprintf("count %d -> %d, tolerance %s", len(preKeys), len(postKeys), tolerance(t.Tolerance)))
+		}
+
+	case "delta":
+		for _, key := range preKeys {
+			b, ok := after[key]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("%s %s missing", collector.Key, key))
+				continue
+			}
+			for _, field := range t.Fields {
+				x, errX := strconv.ParseFloat(before[key][field], 64)
+				y, errY := strconv.ParseFloat(b[field], 64)
+				if errX != nil || errY != nil {
+					failures = append(failures, fmt.Sprintf("%s %s: %s %q -> %q is not numeric", collector.Key, key, field, before[key][field], b[field]))
+					continue
+				}
+				if !within(x, y, t.Tolerance) {
+					failures = append(failures, fmt.Sprintf("%s %s: %s %s -> %s, tolerance %s", collector.Key, key, field, before[key][field], b[field], tolerance(t.Tolerance)))
+				}
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+// index returns the records by key.
+func index(records []Record, key string) map[string]Record {
+	m := make(map[string]Record)
+	for _, r := range records {
+		m[r[key]] = r
+	}
+	return m
+}
+
+// selected returns the sorted keys of the records matching Where.
+func (t Test) selected(records map[string]Record) []string {
+	var keys []string
+	for key, r := range records {
+		match := true
+		for field, value := range t.Where {
+			match = match && strings.EqualFold(r[field], value)
+		}
+		if match {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTolerance returns the tolerance and whether it is a percentage.
+func parseTolerance(s string) (float64, bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false, nil
+	}
+	percent := strings.HasSuffix(s, "%")
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+	if err != nil || value < 0 {
+		return 0, false, fmt.Errorf("invalid tolerance %q", s)
+	}
+	return value, percent, nil
+}
+
+// within reports whether after differs from before by at most the tolerance.
+func within(before, after float64, tol string) bool {
+	value, percent, err := parseTolerance(tol)
+	if err != nil {
+		return false
+	}
+	if percent {
+		value = math.Abs(before) * value / 100
+	}
+	return math.Abs(after-before) <= value
+}
+
+func tolerance(tol string) string {
+	if tol == "" {
+		return "0"
+	}
+	return tol
+}
+
+// WriteText writes the reports in a form suited to a change ticket.
+func WriteText(w io.Writer, reports []Report) {
+
+	passed, failed := 0, 0
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s: %s -> %s\n", report.Host, report.Pre, report.Post)
+		for _, res := range report.Results {
+			switch {
+			case res.Error != "":
+				failed++
+				fmt.Fprintf(w, "  ERROR %s: %s\n", res.Name, res.Error)
+			case res.Passed:
+				passed++
+				fmt.Fprintf(w, "  PASS  %s\n", res.Name)
+			default:
+				failed++
+				fmt.Fprintf(w, "  FAIL  %s\n", res.Name)
+				for _, failure := range res.Failures {
+					fmt.Fprintf(w, "        %s\n", failure)
+				}
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+}
@@ -2,9 +2,14 @@ package networkapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"sort"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
@@ -16,40 +21,161 @@ type NetworkSSH interface {
 	GetConfigSSH(session *ssh.Session, format string) (string, error)
 	GetInterfacesSSH(session *ssh.Session, format string) (string, error)
 	GetInterfacesDiagnosticsSSH(session *ssh.Session) (InterfacesDiagnosticsSSH, error)
+	GetInterfacesStatisticsSSH(session *ssh.Session) (InterfacesStatisticsSSH, error)
 	GetBGPStatusSSH(session *ssh.Session, format string) (string, error)
+	GetBGPSummarySSH(session *ssh.Session) (RPCReplyBgp, error)
+	GetSystemUptimeInfoSSH(session *ssh.Session) (map[string]SystemUptimeInformation, error)
 	GetLogMessagesSSH(session *ssh.Session) (string, error)
 	GetCommitHistorySSH(session *ssh.Session, port string) (string, error)
 	GetLLDPNeighborsSSH(session *ssh.Session, format string) (string, error)
+	GetLLDPNeighborsInfoSSH(session *ssh.Session) ([]LLDPNeighbor, error)
 	GetOutputSSH(session *ssh.Session, command string, format string) (string, error)
+	StreamOutputSSH(session *ssh.Session, command string, format string) (io.ReadCloser, error)
+	StreamConfigSSH(session *ssh.Session, format string) (io.ReadCloser, error)
+	StreamLogMessagesSSH(session *ssh.Session) (io.ReadCloser, error)
+	ScanLogMessagesSSH(session *ssh.Session, fn func(line string) error) error
+	StreamElementsSSH(session *ssh.Session, command string, name string, fn func(decoder *xml.Decoder, start xml.StartElement) error) error
+	GetChassisInventorySSH(session *ssh.Session) ([]ChassisInventory, error)
+	GetRoutesSSH(session *ssh.Session, query RouteQuery) ([]Route, error)
+	StreamRoutesSSH(session *ssh.Session, query RouteQuery, fn func(Route) error) error
+	GetARPTableSSH(session *ssh.Session) ([]Neighbor, error)
+	GetIPv6NeighborsSSH(session *ssh.Session) ([]Neighbor, error)
+	GetEthernetSwitchingTableSSH(session *ssh.Session) ([]MACEntry, error)
+	GetOSPFNeighborsSSH(session *ssh.Session, version int) ([]OSPFNeighbor, error)
+	GetOSPFInterfacesSSH(session *ssh.Session, version int) ([]OSPFInterface, error)
+	GetISISAdjacenciesSSH(session *ssh.Session) ([]ISISAdjacency, error)
+	GetISISDatabaseSSH(session *ssh.Session) ([]ISISLSP, error)
+	GetMPLSLSPsSSH(session *ssh.Session) ([]LSP, error)
+	GetRSVPSessionsSSH(session *ssh.Session) ([]RSVPSession, error)
+	GetLDPNeighborsSSH(session *ssh.Session) ([]LDPNeighbor, error)
+	GetChassisAlarmsSSH(session *ssh.Session) ([]Alarm, error)
+	GetSystemAlarmsSSH(session *ssh.Session) ([]Alarm, error)
+	GetEnvironmentSSH(session *ssh.Session) ([]EnvironmentItem, error)
+	GetRoutingEnginesSSH(session *ssh.Session) ([]RoutingEngineStatus, error)
+	GetFirewallCountersSSH(session *ssh.Session, filter string) ([]FirewallFilter, error)
+	PingSSH(ctx context.Context, target string, opts PingOptions) (*PingResult, error)
+	TracerouteSSH(ctx context.Context, target string, opts TracerouteOptions) (*TracerouteResult, error)
+	ListFilesSSH(dir string) ([]RemoteFile, error)
+	PutFileSSH(local, remote string, opts TransferOptions) error
+	GetFileSSH(remote, local string, opts TransferOptions) error
+	GetFilesSSH(pattern, dir string, opts TransferOptions) ([]string, error)
+	GetFileChecksumSSH(remote string) (string, error)
 	CloseSSH(session *ssh.Session)
+	DisconnectSSH()
 }
 
 // ConnectSSH ... Establishes session with the device
+//
+// The connection to the device is kept open and shared by the sessions, a new
+// connection is only dialed when there is none or the previous one was lost.
+// With AutoDetect, the facts of the device are detected on the first connection,
+// and on the next ones until a detection succeeds.
 func (c *Client) ConnectSSH() (*ssh.Session, error) {
-	hostname := c.Hostname + ":22"
-	config := &ssh.ClientConfig{
-		User: c.Username,
-		Auth: []ssh.AuthMethod{
-			ssh.Password(c.Password),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	session, err := c.connectSSH()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.detectOnConnect(); err != nil {
+		session.Close()
+		return nil, err
 	}
-	client, err := ssh.Dial("tcp", hostname, config)
+	return session, nil
+}
+
+func (c *Client) connectSSH() (*ssh.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sshClient != nil {
+		session, err := c.sshClient.NewSession()
+		if err == nil {
+			return session, nil
+		}
+		c.sshClient.Close()
+		c.sshClient = nil
+	}
+
+	client, err := c.dialSSH()
 	if err != nil {
 		return nil, err
 	}
 	session, err := client.NewSession()
 	if err != nil {
+		client.Close()
 		return nil, err
 	}
+	c.sshClient = client
 	return session, nil
 }
 
+// sshConnection returns the connection shared by the sessions, dialing it
+// when there is none or the previous one was lost, e.g. to run SFTP over it.
+func (c *Client) sshConnection() (*ssh.Client, error) {
+	client, err := c.liveConnection()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.detectOnConnect(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *Client) liveConnection() (*ssh.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sshClient != nil {
+		// A keepalive tells a lost connection, which the device answers
+		// even when it doesn't know the request.
+		if _, _, err := c.sshClient.SendRequest("keepalive@openssh.com", true, nil); err == nil {
+			return c.sshClient, nil
+		}
+		c.sshClient.Close()
+		c.sshClient = nil
+	}
+
+	client, err := c.dialSSH()
+	if err != nil {
+		return nil, err
+	}
+	c.sshClient = client
+	return client, nil
+}
+
+func (c *Client) dialSSH() (*ssh.Client, error) {
+
+	// The hostname may give the port, e.g. to reach a test server.
+	hostname := c.Hostname
+	if _, _, err := net.SplitHostPort(hostname); err != nil {
+		hostname = net.JoinHostPort(c.Hostname, "22")
+	}
+	config := &ssh.ClientConfig{
+		User: c.Username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(c.Password),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+	return ssh.Dial("tcp", hostname, config)
+}
+
 // CloseSSH ...
 func (c *Client) CloseSSH(session *ssh.Session) {
 	session.Close()
 }
 
+// DisconnectSSH ... Closes the connection shared by the sessions
+func (c *Client) DisconnectSSH() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sshClient != nil {
+		c.sshClient.Close()
+		c.sshClient = nil
+	}
+}
+
 // GetConfigSSH ... Returns the configuration of device
 func (c *Client) GetConfigSSH(session *ssh.Session, format string) (string, error) {
 	var stdoutBuf bytes.Buffer
@@ -101,6 +227,25 @@ func (c *Client) GetInterfacesDiagnosticsSSH(session *ssh.Session) (InterfacesDi
 	return interfaces, nil
 }
 
+//GetInterfacesStatisticsSSH ...Returns the status and traffic counters of the physical interfaces
+func (c *Client) GetInterfacesStatisticsSSH(session *ssh.Session) (InterfacesStatisticsSSH, error) {
+	return ReadInterfacesStatistics(sessionRunner(session))
+}
+
+//ReadInterfacesStatistics ... Returns the status and traffic counters of the physical interfaces of a Junos device
+func ReadInterfacesStatistics(run Runner) (InterfacesStatisticsSSH, error) {
+	var interfaces InterfacesStatisticsSSH
+	err := junosXML(run, "show interfaces statistics detail", &interfaces)
+	return interfaces, err
+}
+
+//ReadInterfacesDiagnostics ... Returns the optics diagnostics of the interfaces of a Junos device
+func ReadInterfacesDiagnostics(run Runner) (InterfacesDiagnosticsSSH, error) {
+	var interfaces InterfacesDiagnosticsSSH
+	err := junosXML(run, "show interfaces diagnostics optics", &interfaces)
+	return interfaces, err
+}
+
 // GetBGPStatusSSH ...
 func (c *Client) GetBGPStatusSSH(session *ssh.Session, format string) (string, error) {
 	var stdoutBuf bytes.Buffer
@@ -120,6 +265,18 @@ func (c *Client) GetBGPStatusSSH(session *ssh.Session, format string) (string, e
 	return string(output), nil
 }
 
+// GetBGPSummarySSH ... Returns the BGP peers with their prefix counts per table
+func (c *Client) GetBGPSummarySSH(session *ssh.Session) (RPCReplyBgp, error) {
+	return ReadBGPSummary(sessionRunner(session))
+}
+
+//ReadBGPSummary ... Returns the BGP peers of a Junos device with their prefix counts per table
+func ReadBGPSummary(run Runner) (RPCReplyBgp, error) {
+	var bgp RPCReplyBgp
+	err := junosXML(run, "show bgp summary", &bgp)
+	return bgp, err
+}
+
 //GetLogMessagesSSH ...
 func (c *Client) GetLogMessagesSSH(session *ssh.Session) (string, error) {
 	var stdoutBuf bytes.Buffer
@@ -131,28 +288,63 @@ func (c *Client) GetLogMessagesSSH(session *ssh.Session) (string, error) {
 }
 
 //GetSystemUptimeSSH ...
+//
+// The json format returns the current, boot and last configuration times of
+// re0, or of the first routing engine, as a RouterTimeRes.
 func (c *Client) GetSystemUptimeSSH(session *ssh.Session, format string) (string, error) {
-	var (
-		stdoutBuf   bytes.Buffer
-		_routerTime RouterTime
-	)
-	session.Stdout = &stdoutBuf
-	if format == "json" {
-		session.Run("show system uptime | display " + format)
-		result := stdoutBuf.Bytes()
-		json.Unmarshal(result, &_routerTime)
+	if format != "json" {
+		return sessionRunner(session)("show system uptime | display " + format)
+	}
 
-		_result := RouterTimeRes{Currenttime: _routerTime.MultiRoutingEngineResults[0].MultiRoutingEngineItem[0].SystemUptimeInformation[0].Currenttime[0].Datetime[0].Data,
-			LastConfiguredTime: _routerTime.MultiRoutingEngineResults[0].MultiRoutingEngineItem[0].SystemUptimeInformation[0].LastConfiguredTime[0].DateTime[0].Data,
-			SystemBootedTime:   _routerTime.MultiRoutingEngineResults[0].MultiRoutingEngineItem[0].SystemUptimeInformation[0].SystemBootedTime[0].DateTime[0].Data}
+	engines, err := ReadSystemUptimeInfo(sessionRunner(session))
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", errors.New("show system uptime: no routing engine in the output")
+	}
+	sort.Strings(names)
+
+	uptime := engines[names[0]]
+	output, err := json.Marshal(RouterTimeRes{
+		Currenttime:        strings.TrimSpace(uptime.CurrentTime.Value),
+		LastConfiguredTime: strings.TrimSpace(uptime.LastConfiguredTime.Value),
+		SystemBootedTime:   strings.TrimSpace(uptime.SystemBootedTime.Value),
+	})
+	return string(output), err
+}
+
+//GetSystemUptimeInfoSSH ...Returns the uptime of every routing engine, keyed by RE name
+//
+// Devices with a single routing engine don't name it, its uptime is returned under "re0".
+func (c *Client) GetSystemUptimeInfoSSH(session *ssh.Session) (map[string]SystemUptimeInformation, error) {
+	return ReadSystemUptimeInfo(sessionRunner(session))
+}
+
+//ReadSystemUptimeInfo ... Returns the uptime of every routing engine of a Junos device, keyed by RE name
+func ReadSystemUptimeInfo(run Runner) (map[string]SystemUptimeInformation, error) {
+	var uptime SystemUptimeSSH
+	if err := junosXML(run, "show system uptime", &uptime); err != nil {
+		return nil, err
+	}
+	return uptimeByRE(uptime), nil
+}
 
-		output, _ := json.Marshal(_result)
-		return string(output), nil
-	} else {
-		session.Run("show system uptime | display " + format)
-		result := stdoutBuf.String()
-		return result, nil
+// uptimeByRE keys the uptime of the routing engines by RE name, a single
+// unnamed routing engine is "re0".
+func uptimeByRE(uptime SystemUptimeSSH) map[string]SystemUptimeInformation {
+	result := make(map[string]SystemUptimeInformation)
+	if uptime.SystemUptimeInformation != nil {
+		result["re0"] = *uptime.SystemUptimeInformation
 	}
+	for _, item := range uptime.MultiRoutingEngineItem {
+		result[strings.TrimSpace(item.ReName)] = item.SystemUptimeInformation
+	}
+	return result
 }
 
 //GetCommitHistorySSH ... Returns commit history
@@ -173,16 +365,49 @@ func (c *Client) GetLLDPNeighborsSSH(session *ssh.Session, format string) (strin
 	return result, nil
 }
 
+//GetLLDPNeighborsInfoSSH ...Returns the LLDP neighbors of the device
+func (c *Client) GetLLDPNeighborsInfoSSH(session *ssh.Session) ([]LLDPNeighbor, error) {
+
+	var (
+		stdoutBuf bytes.Buffer
+		lldp      LLDPNeighborsInfoSSH
+	)
+	session.Stdout = &stdoutBuf
+	if err := session.Run("show lldp neighbors | display xml"); err != nil {
+		return nil, err
+	}
+
+	err := xml.Unmarshal(stdoutBuf.Bytes(), &lldp)
+	return lldp.Neighbors, err
+}
+
 //GetOutputSSH ...Takes command and expected output format as input and returns output in text, JSON or XML based on the output format
 func (c *Client) GetOutputSSH(session *ssh.Session, command string, format string) (string, error) {
+	var stdoutBuf bytes.Buffer
+	session.Stdout = &stdoutBuf
+	session.Run(displayCommand(command, format))
+	result := stdoutBuf.String()
+	return result, nil
+}
+
+// sessionRunner runs a command on the session, which runs a single one.
+func sessionRunner(session *ssh.Session) Runner {
+	return func(command string) (string, error) {
+		var stdoutBuf bytes.Buffer
+		session.Stdout = &stdoutBuf
+		if err := session.Run(command); err != nil {
+			return "", err
+		}
+		return stdoutBuf.String(), nil
+	}
+}
+
+// displayCommand pipes the command to display xml or json as the format asks.
+func displayCommand(command string, format string) string {
 	if strings.ToLower(format) == "xml" {
 		command = command + " | display xml"
 	} else if strings.ToLower(format) == "json" {
 		command = command + " | display json"
 	}
-	var stdoutBuf bytes.Buffer
-	session.Stdout = &stdoutBuf
-	session.Run(command)
-	result := stdoutBuf.String()
-	return result, nil
+	return command
 }
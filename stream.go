@@ -0,0 +1,147 @@
+package networkapi
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sessionStream is the output of a command as it is read from the session,
+// closing it closing the session.
+type sessionStream struct {
+	io.Reader
+	session *ssh.Session
+	eof     bool
+}
+
+func (s *sessionStream) Read(p []byte) (int, error) {
+	n, err := s.Reader.Read(p)
+	if err == io.EOF {
+		s.eof = true
+	}
+	return n, err
+}
+
+// Close returns the exit error of the command once its output was read to
+// the end. Before, it stops the command, whose error is then left out.
+func (s *sessionStream) Close() error {
+	if s.eof {
+		err := s.session.Wait()
+		s.session.Close()
+		return err
+	}
+	if err := s.session.Close(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+//StreamOutputSSH ...Returns the output of the command in text, JSON or XML as it is read from the device
+//
+// The output isn't held in memory, unlike GetOutputSSH. Closing the reader
+// closes the session, which stops the command when it hasn't ended yet, and
+// returns the exit error of the command once the output was read to the end.
+func (c *Client) StreamOutputSSH(session *ssh.Session, command string, format string) (io.ReadCloser, error) {
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Start(displayCommand(command, format)); err != nil {
+		return nil, err
+	}
+	return &sessionStream{Reader: stdout, session: session}, nil
+}
+
+//StreamConfigSSH ...Returns the configuration of the device as it is read, in the format of GetConfigSSH
+func (c *Client) StreamConfigSSH(session *ssh.Session, format string) (io.ReadCloser, error) {
+	return c.StreamOutputSSH(session, "show configuration | display "+format, "text")
+}
+
+//StreamLogMessagesSSH ...Returns the messages log as it is read from the device
+func (c *Client) StreamLogMessagesSSH(session *ssh.Session) (io.ReadCloser, error) {
+	return c.StreamOutputSSH(session, "show log messages", "text")
+}
+
+//ScanLogMessagesSSH ...Calls fn for every line of the messages log as it is read from the device
+//
+// The session is closed when fn returns an error, which is then returned.
+func (c *Client) ScanLogMessagesSSH(session *ssh.Session, fn func(line string) error) error {
+
+	stream, err := c.StreamLogMessagesSSH(session)
+	if err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Text()); err != nil {
+			stream.Close()
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		stream.Close()
+		return err
+	}
+	return stream.Close()
+}
+
+//StreamElementsSSH ...Calls fn for every element of the name in the XML output of the command as it is read from the device
+//
+// This is how the output of commands too large to be held in memory is read,
+// e.g. the physical-interface elements of show interfaces extensive. The
+// session is closed when fn returns an error, which is then returned.
+func (c *Client) StreamElementsSSH(session *ssh.Session, command string, name string, fn func(decoder *xml.Decoder, start xml.StartElement) error) error {
+
+	stream, err := c.StreamOutputSSH(session, command, "xml")
+	if err != nil {
+		return err
+	}
+	if err := DecodeElements(stream, name, fn); err != nil {
+		stream.Close()
+		return err
+	}
+	return stream.Close()
+}
+
+//DecodeElements ... Decodes XML incrementally, calling fn for every element of the name
+//
+// fn decodes the element with decoder.DecodeElement, or skips it with
+// decoder.Skip. Decoding stops at the first error of fn, which is returned.
+// Errors reported by the device in the reply are returned as errors.
+func DecodeElements(r io.Reader, name string, fn func(decoder *xml.Decoder, start xml.StartElement) error) error {
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case name:
+			if err := fn(decoder, start); err != nil {
+				return err
+			}
+		case "error":
+			var reply struct {
+				Message string `xml:"message"`
+			}
+			if err := decoder.DecodeElement(&reply, &start); err != nil {
+				return err
+			}
+			return errors.New(strings.TrimSpace(reply.Message))
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package networkapi
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kgrvamsi/networkapi/sftptest"
+	"golang.org/x/crypto/ssh"
+)
+
+// streamInterface is the part of a physical-interface the tests decode.
+type streamInterface struct {
+	Name       string `xml:"name"`
+	OperStatus string `xml:"oper-status"`
+	InputBytes uint64 `xml:"traffic-statistics>input-bytes"`
+}
+
+// interfacesXML returns the XML of show interfaces extensive with n
+// physical interfaces.
+func interfacesXML(n int) string {
+	var b strings.Builder
+	b.WriteString("<rpc-reply>\n<interface-information>\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<physical-interface>
+<name>ge-%d/0/%d</name>
+<admin-status>up</admin-status>
+<oper-status>up</oper-status>
+<description>to-core-%d</description>
+<traffic-statistics>
+<input-bytes>%d</input-bytes>
+<output-bytes>%d</output-bytes>
+<input-packets>%d</input-packets>
+<output-packets>%d</output-packets>
+</traffic-statistics>
+<input-error-list>
+<input-errors>0</input-errors>
+<input-drops>0</input-drops>
+<framing-errors>0</framing-errors>
+</input-error-list>
+</physical-interface>
+`, i/48, i%48, i, i*1000, i*2000, i*10, i*20)
+	}
+	b.WriteString("</interface-information>\n</rpc-reply>\n")
+	return b.String()
+}
+
+func testSSH(t testing.TB) (*sftptest.Server, *Client) {
+	t.Helper()
+	srv, err := sftptest.NewServer("admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	c := NetworkClient(srv.Addr, "admin", "secret")
+	t.Cleanup(c.DisconnectSSH)
+	return srv, c
+}
+
+func TestStreamOutputSSHClose(t *testing.T) {
+	srv, c := testSSH(t)
+	srv.Handle("show interfaces extensive | display xml", interfacesXML(3))
+
+	tests := []struct {
+		name    string
+		command string
+		read    bool
+		exit    int
+	}{
+		{"read to the end", "show interfaces extensive", true, 0},
+		{"closed early", "show interfaces extensive", false, 0},
+		{"failed command", "show nothing", true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := c.ConnectSSH()
+			if err != nil {
+				t.Fatal(err)
+			}
+			stream, err := c.StreamOutputSSH(session, tt.command, "xml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.read {
+				if _, err := io.Copy(io.Discard, stream); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = stream.Close()
+			var exitErr *ssh.ExitError
+			switch {
+			case tt.exit == 0 && err != nil:
+				t.Errorf("Close() = %v, want nil", err)
+			case tt.exit != 0 && (!errors.As(err, &exitErr) || exitErr.ExitStatus() != tt.exit):
+				t.Errorf("Close() = %v, want exit status %d", err, tt.exit)
+			}
+		})
+	}
+}
+
+func TestStreamElementsSSH(t *testing.T) {
+	srv, c := testSSH(t)
+	srv.Handle("show interfaces extensive | display xml", interfacesXML(100))
+
+	session, err := c.ConnectSSH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	err = c.StreamElementsSSH(session, "show interfaces extensive", "physical-interface", func(decoder *xml.Decoder, start xml.StartElement) error {
+		var intf streamInterface
+		if err := decoder.DecodeElement(&intf, &start); err != nil {
+			return err
+		}
+		names = append(names, intf.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 100 || names[0] != "ge-0/0/0" || names[99] != "ge-2/0/3" {
+		t.Errorf("StreamElementsSSH() decoded %d interfaces, first %q", len(names), names[0])
+	}
+
+	// The exit error of a command failing after its output is returned.
+	session, err = c.ConnectSSH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.StreamElementsSSH(session, "show nothing", "physical-interface", func(*xml.Decoder, xml.StartElement) error { return nil })
+	var exitErr *ssh.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Errorf("StreamElementsSSH() = %v, want an exit error", err)
+	}
+}
+
+// The benchmarks compare reading the physical interfaces of a large show
+// interfaces extensive held in memory and as it is streamed.
+const benchmarkInterfaces = 10000
+
+func BenchmarkGetOutputSSH(b *testing.B) {
+	srv, c := testSSH(b)
+	srv.Handle("show interfaces extensive | display xml", interfacesXML(benchmarkInterfaces))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		session, err := c.ConnectSSH()
+		if err != nil {
+			b.Fatal(err)
+		}
+		output, err := c.GetOutputSSH(session, "show interfaces extensive", "xml")
+		c.CloseSSH(session)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var reply struct {
+			Interfaces []streamInterface `xml:"interface-information>physical-interface"`
+		}
+		if err := xml.Unmarshal([]byte(output), &reply); err != nil {
+			b.Fatal(err)
+		}
+		if len(reply.Interfaces) != benchmarkInterfaces {
+			b.Fatalf("decoded %d interfaces", len(reply.Interfaces))
+		}
+	}
+}
+
+func BenchmarkStreamOutputSSH(b *testing.B) {
+	srv, c := testSSH(b)
+	srv.Handle("show interfaces extensive | display xml", interfacesXML(benchmarkInterfaces))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		session, err := c.ConnectSSH()
+		if err != nil {
+			b.Fatal(err)
+		}
+		stream, err := c.StreamOutputSSH(session, "show interfaces extensive", "xml")
+		if err != nil {
+			b.Fatal(err)
+		}
+		count := 0
+		err = DecodeElements(stream, "physical-interface", func(decoder *xml.Decoder, start xml.StartElement) error {
+			var intf streamInterface
+			count++
+			return decoder.DecodeElement(&intf, &start)
+		})
+		if err := stream.Close(); err != nil {
+			b.Fatal(err)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		if count != benchmarkInterfaces {
+			b.Fatalf("decoded %d interfaces", count)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package networkapi
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ziutek/telnet"
+)
+
+// NetworkTelnet ... Interface for library connecting over telnet, e.g. through a console server
+type NetworkTelnet interface {
+	ConnectTelnet(opts TelnetOptions) (*TelnetSession, error)
+	GetOutputTelnet(session *TelnetSession, command string, format string) (string, error)
+	GetConfigTelnet(session *TelnetSession, format string) (string, error)
+	GetInterfacesStatisticsTelnet(session *TelnetSession) (InterfacesStatisticsSSH, error)
+	GetBGPSummaryTelnet(session *TelnetSession) (RPCReplyBgp, error)
+	GetLLDPNeighborsInfoTelnet(session *TelnetSession) ([]LLDPNeighbor, error)
+	GetSystemUptimeInfoTelnet(session *TelnetSession) (map[string]SystemUptimeInformation, error)
+	CloseTelnet(session *TelnetSession)
+}
+
+//TelnetOptions ... Settings of a telnet connection
+type TelnetOptions struct {
+	// Port defaults to 23. Console servers usually map a port to each line, e.g. 2001.
+	Port int
+	// Timeout of the login and of every command, 60 seconds by default.
+	Timeout time.Duration
+	// Prompt is a regular expression matching the end of the CLI prompt. By
+	// default the prompt is the line the device shows after the login.
+	Prompt string
+}
+
+//TelnetSession ... CLI session over telnet
+//
+// Unlike SSH sessions, a telnet session runs any number of commands, one at a time.
+type TelnetSession struct {
+	conn    *telnet.Conn
+	timeout time.Duration
+	prompt  *regexp.Regexp
+}
+
+var (
+	telnetLogin    = regexp.MustCompile(`(?i)(login|username|user name)\s*:\s*$`)
+	telnetPassword = regexp.MustCompile(`(?i)password\s*:\s*$`)
+	telnetFailed   = regexp.MustCompile(`(?i)(login incorrect|authentication failed|access denied)`)
+	telnetMore     = regexp.MustCompile(`(---\(more[^)]*\)---|--More--)\s*$`)
+	// telnetAnyPrompt matches the end of a CLI prompt, user@host> or user@host#,
+	// and the shell prompt, root@host:RE:0%, a console lands on after a root login.
+	telnetAnyPrompt = regexp.MustCompile(`[\w.@:()\-\[\]]+[>#%$] ?$`)
+	telnetShell     = regexp.MustCompile(`% ?$`)
+	// telnetRE matches the line showing the routing engine above the prompt, {master:0}.
+	telnetRE = regexp.MustCompile(`\n*\{[\w:-]+\}\n$`)
+)
+
+//ConnectTelnet ... Logs in to the device over telnet and disables the pager
+func (c *Client) ConnectTelnet(opts TelnetOptions) (*TelnetSession, error) {
+
+	port := opts.Port
+	if port == 0 {
+		port = 23
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	conn, err := telnet.DialTimeout("tcp", hostPort(c.Hostname, port), timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetUnixWriteMode(true)
+	session := &TelnetSession{conn: conn, timeout: timeout}
+
+	if err := session.login(c.Username, c.Password, opts.Prompt); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for _, command := range []string{"set cli screen-length 0", "set cli screen-width 0"} {
+		if _, err := session.Run(command); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// login answers the login and password prompts until the CLI prompt shows up.
+// Console lines may already be logged in, or need a new line to show a prompt.
+func (s *TelnetSession) login(username, password, prompt string) error {
+
+	if prompt != "" {
+		var err error
+		if s.prompt, err = regexp.Compile(prompt + `\s*$`); err != nil {
+			return fmt.Errorf("invalid prompt: %s", err)
+		}
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	var buf []byte
+	woken, sentUsername, sentPassword := false, false, false
+	for {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("telnet login: timed out waiting for a prompt")
+		}
+		read, err := s.read(&buf, s.timeout/10)
+		if err != nil && !isTimeout(err) {
+			return err
+		}
+		last := lastLine(buf)
+
+		switch {
+		case telnetFailed.Match(buf):
+			return fmt.Errorf("telnet login failed: %s", telnetFailed.Find(buf))
+		case telnetLogin.MatchString(last):
+			if sentUsername && !sentPassword {
+				return fmt.Errorf("telnet login failed: username asked again")
+			}
+			sentUsername, sentPassword = true, false
+			err = s.send(username)
+		case telnetPassword.MatchString(last):
+			if sentPassword {
+				return fmt.Errorf("telnet login failed: password asked again")
+			}
+			sentPassword = true
+			err = s.send(password)
+		case s.prompt != nil && s.prompt.MatchString(last),
+			s.prompt == nil && telnetAnyPrompt.MatchString(last) && !telnetShell.MatchString(last):
+			if s.prompt == nil {
+				s.prompt = regexp.MustCompile(regexp.QuoteMeta(strings.TrimSpace(last)) + `\s*$`)
+			}
+			return nil
+		case telnetShell.MatchString(last):
+			err = s.send("cli")
+		case !read && !woken:
+			// Console lines stay silent until they get a new line.
+			woken = true
+			err = s.send("")
+		case !read:
+			return fmt.Errorf("telnet login: timed out waiting for a prompt")
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+}
+
+//Run ... Runs a CLI command and returns its output, without the echoed command and the prompt
+func (s *TelnetSession) Run(command string) (string, error) {
+
+	// Drop anything left over, e.g. a late message on a console line.
+	s.read(new([]byte), time.Millisecond)
+
+	if err := s.send(command); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(s.timeout)
+	var buf []byte
+	for {
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("telnet: %q timed out after %s", command, s.timeout)
+		}
+		if _, err := s.read(&buf, time.Until(deadline)); err != nil && !isTimeout(err) {
+			return "", err
+		}
+		last := lastLine(buf)
+		if telnetMore.MatchString(last) {
+			// The pager is still on, e.g. the screen length couldn't be set.
+			buf = telnetMore.ReplaceAll(buf, nil)
+			if _, err := s.conn.Write([]byte(" ")); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if s.prompt.MatchString(last) {
+			return telnetOutput(buf, command), nil
+		}
+	}
+}
+
+// read appends what the device sends within wait to buf, and reports whether
+// anything was read.
+func (s *TelnetSession) read(buf *[]byte, wait time.Duration) (bool, error) {
+	s.conn.SetReadDeadline(time.Now().Add(wait))
+	defer s.conn.SetReadDeadline(time.Time{})
+
+	read := false
+	chunk := make([]byte, 4096)
+	for {
+		n, err := s.conn.Read(chunk)
+		if n > 0 {
+			*buf = append(*buf, chunk[:n]...)
+			read = true
+		}
+		if err != nil {
+			return read, err
+		}
+		last := lastLine(*buf)
+		if telnetMore.MatchString(last) || telnetLogin.MatchString(last) || telnetPassword.MatchString(last) ||
+			s.prompt != nil && s.prompt.MatchString(last) || s.prompt == nil && telnetAnyPrompt.MatchString(last) {
+			return read, nil
+		}
+	}
+}
+
+func (s *TelnetSession) send(line string) error {
+	_, err := s.conn.Write([]byte(line + "\n"))
+	return err
+}
+
+// telnetOutput drops the echoed command, the prompt and the carriage returns from the output.
+//
+// A carriage return within a line takes the cursor back to its start, the
+// text after it overwriting the text before, as when the pager is erased.
+func telnetOutput(buf []byte, command string) string {
+	lines := strings.Split(string(buf), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if j := strings.LastIndex(line, "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	output := strings.Join(lines, "\n")
+	if i := strings.Index(output, command); i >= 0 {
+		output = output[i+len(command):]
+	}
+	output = strings.TrimPrefix(output, "\n")
+	if i := strings.LastIndex(output, "\n"); i >= 0 {
+		output = output[:i+1]
+	} else {
+		output = ""
+	}
+	return telnetRE.ReplaceAllString(output, "\n")
+}
+
+func lastLine(buf []byte) string {
+	buf = bytes.TrimRight(buf, "\r\n\x00")
+	if i := bytes.LastIndexAny(buf, "\r\n"); i >= 0 {
+		buf = buf[i+1:]
+	}
+	return string(buf)
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+//CloseTelnet ... Logs out and closes the connection
+func (c *Client) CloseTelnet(session *TelnetSession) {
+	session.conn.SetDeadline(time.Now().Add(time.Second))
+	session.send("exit")
+	session.conn.Close()
+}
+
+//GetOutputTelnet ...Takes command and expected output format as input and returns output in text, JSON or XML based on the output format
+func (c *Client) GetOutputTelnet(session *TelnetSession, command string, format string) (string, error) {
+	return session.Run(displayCommand(command, format))
+}
+
+//GetConfigTelnet ... Returns the configuration in text, set, xml or json
+func (c *Client) GetConfigTelnet(session *TelnetSession, format string) (string, error) {
+	return session.Run("show configuration | display " + format)
+}
+
+//GetInterfacesStatisticsTelnet ...Returns the status and traffic counters of the physical interfaces
+func (c *Client) GetInterfacesStatisticsTelnet(session *TelnetSession) (InterfacesStatisticsSSH, error) {
+	var interfaces InterfacesStatisticsSSH
+	err := junosXML(session.Run, "show interfaces statistics detail", &interfaces)
+	return interfaces, err
+}
+
+//GetBGPSummaryTelnet ... Returns the BGP peers with their prefix counts per table
+func (c *Client) GetBGPSummaryTelnet(session *TelnetSession) (RPCReplyBgp, error) {
+	var bgp RPCReplyBgp
+	err := junosXML(session.Run, "show bgp summary", &bgp)
+	return bgp, err
+}
+
+//GetLLDPNeighborsInfoTelnet ...Returns the LLDP neighbors of the device
+func (c *Client) GetLLDPNeighborsInfoTelnet(session *TelnetSession) ([]LLDPNeighbor, error) {
+	var lldp LLDPNeighborsInfoSSH
+	err := junosXML(session.Run, "show lldp neighbors", &lldp)
+	return lldp.Neighbors, err
+}
+
+//GetSystemUptimeInfoTelnet ...Returns the uptime of every routing engine, keyed by RE name
+func (c *Client) GetSystemUptimeInfoTelnet(session *TelnetSession) (map[string]SystemUptimeInformation, error) {
+	var uptime SystemUptimeSSH
+	if err := junosXML(session.Run, "show system uptime", &uptime); err != nil {
+		return nil, err
+	}
+	return uptimeByRE(uptime), nil
+}
@@ -0,0 +1,185 @@
+package networkapi
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTelnet is a console line of a Junos device answering the telnet login
+// and the commands with their output, sent in the chunks given.
+type fakeTelnet struct {
+	password string
+	// silent lines wait for a new line before showing the login prompt.
+	silent bool
+	// shell logins land on the shell, cli being needed to get the CLI.
+	shell   bool
+	outputs map[string][]string
+}
+
+// listen serves the line on a local port and returns its address.
+func (f *fakeTelnet) listen(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func (f *fakeTelnet) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	readLine := func() (string, error) {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+	write := func(s string) {
+		conn.Write([]byte(s))
+	}
+
+	if f.silent {
+		if _, err := readLine(); err != nil {
+			return
+		}
+	}
+	write("\r\n\r\nedge-1 (ttyu0)\r\n\r\nlogin: ")
+	user, err := readLine()
+	if err != nil {
+		return
+	}
+	write("Password:")
+	password, err := readLine()
+	if err != nil {
+		return
+	}
+	if password != f.password {
+		write("\r\nLogin incorrect\r\nlogin: ")
+		readLine()
+		return
+	}
+
+	write("\r\nLast login: Thu Oct 19 10:40:42 on ttyu0\r\n\r\n--- JUNOS 21.4R3.15 Kernel 64-bit  JNPR-12.1-20221027.2d4c5f8_buil\r\n")
+	if f.shell {
+		write(user + "@edge-1:RE:0% ")
+		if line, err := readLine(); err != nil || line != "cli" {
+			return
+		}
+		write("cli\r\n")
+	}
+	prompt := user + "@edge-1> "
+	for {
+		write("\r\n{master:0}\r\n")
+		// The prompt comes in two reads.
+		write(prompt[:4])
+		time.Sleep(20 * time.Millisecond)
+		write(prompt[4:])
+
+		command, err := readLine()
+		if err != nil {
+			return
+		}
+		write(command + "\r\n")
+		for _, chunk := range f.outputs[command] {
+			write(chunk)
+			if strings.HasSuffix(chunk, "---(more)---") {
+				// The pager waits for a space to show the next screen.
+				if b, err := r.ReadByte(); err != nil || b != ' ' {
+					return
+				}
+				continue
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
+
+func TestTelnet(t *testing.T) {
+	outputs := map[string][]string{
+		"set cli screen-length 0": {"Screen length set to 0\r\n"},
+		"set cli screen-width 0":  {"Screen width set to 0\r\n"},
+		"show system uptime": {
+			"Current time: 2023-10-19 10:40:42 UTC\r\n",
+			"System booted: 2023-10-17 08:12:05 UTC (2d 02:28 ago)\r\n",
+		},
+		// The screen length is still set, the pager stops the output.
+		"show interfaces terse": {
+			"Interface               Admin Link Proto    Local                 Remote\r\nge-0/0/0                up    up\r\n---(more)---",
+			"\r                                        \rge-0/0/0.0              up    up   inet     10.0.0.2/30\r\n",
+			"lo0.0                   up    up   inet     10.255.0.1          --> 0/0\r\n",
+		},
+	}
+	tests := []struct {
+		name    string
+		line    fakeTelnet
+		user    string
+		command string
+		want    string
+		err     string
+	}{
+		{"login", fakeTelnet{password: "secret", outputs: outputs}, "admin", "show system uptime",
+			"Current time: 2023-10-19 10:40:42 UTC\nSystem booted: 2023-10-17 08:12:05 UTC (2d 02:28 ago)\n", ""},
+		{"silent line", fakeTelnet{password: "secret", silent: true, outputs: outputs}, "admin", "show system uptime",
+			"Current time: 2023-10-19 10:40:42 UTC\nSystem booted: 2023-10-17 08:12:05 UTC (2d 02:28 ago)\n", ""},
+		{"shell", fakeTelnet{password: "secret", shell: true, outputs: outputs}, "root", "show system uptime",
+			"Current time: 2023-10-19 10:40:42 UTC\nSystem booted: 2023-10-17 08:12:05 UTC (2d 02:28 ago)\n", ""},
+		{"pager", fakeTelnet{password: "secret", outputs: outputs}, "admin", "show interfaces terse",
+			"Interface               Admin Link Proto    Local                 Remote\nge-0/0/0                up    up\n" +
+				"ge-0/0/0.0              up    up   inet     10.0.0.2/30\n" +
+				"lo0.0                   up    up   inet     10.255.0.1          --> 0/0\n", ""},
+		{"bad password", fakeTelnet{password: "other", outputs: outputs}, "admin", "", "", "telnet login failed: Login incorrect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NetworkClient(tt.line.listen(t), tt.user, "secret")
+			session, err := c.ConnectTelnet(TelnetOptions{Timeout: 2 * time.Second})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("ConnectTelnet() error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.CloseTelnet(session)
+
+			got, err := session.Run(tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Run(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelnetOutput(t *testing.T) {
+	tests := []struct {
+		buf, command, want string
+	}{
+		{"show version\r\nHostname: edge-1\r\nModel: mx204\r\n\r\n{master:0}\r\nadmin@edge-1> ", "show version", "Hostname: edge-1\nModel: mx204\n"},
+		{"show version\r\nHostname: edge-1\r\n\r\nadmin@edge-1> ", "show version", "Hostname: edge-1\n\n"},
+		{"show version\r\nadmin@edge-1> ", "show version", ""},
+		{"admin@edge-1> ", "show version", ""},
+		{"show version\r\r\nHostname: edge-1\r\r\n\r\nadmin@edge-1> ", "show version", "Hostname: edge-1\n\n"},
+	}
+	for _, tt := range tests {
+		if got := telnetOutput([]byte(tt.buf), tt.command); got != tt.want {
+			t.Errorf("telnetOutput(%q) = %q, want %q", tt.buf, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package topology
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formats lists the formats the graph can be written in.
+var Formats = []string{"json", "graphml", "dot"}
+
+// Write writes the graph in the given format.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	case "graphml":
+		return g.WriteGraphML(w)
+	case "dot":
+		return g.WriteDOT(w)
+	}
+	return fmt.Errorf("unsupported topology format %q", format)
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// data returns the data elements of the non-empty values, given as key, value pairs.
+func data(pairs ...string) []graphMLData {
+	var d []graphMLData
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			d = append(d, graphMLData{Key: pairs[i], Value: pairs[i+1]})
+		}
+	}
+	return d
+}
+
+// WriteGraphML writes the graph in GraphML, links are undirected edges.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+
+	doc := graphML{Xmlns: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []graphMLKey{
+		{"known", "node", "known", "boolean"},
+		{"site", "node", "site", "string"},
+		{"role", "node", "role", "string"},
+		{"chassis_id", "node", "chassis_id", "string"},
+		{"source_interface", "edge", "source_interface", "string"},
+		{"source_description", "edge", "source_description", "string"},
+		{"source_oper_status", "edge", "source_oper_status", "string"},
+		{"target_interface", "edge", "target_interface", "string"},
+		{"target_description", "edge", "target_description", "string"},
+		{"target_oper_status", "edge", "target_oper_status", "string"},
+		{"bidirectional", "edge", "bidirectional", "boolean"},
+	}
+	doc.Graph.ID = "topology"
+	doc.Graph.EdgeDefault = "undirected"
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   n.ID,
+			Data: data("known", strconv.FormatBool(n.Known), "site", n.Site, "role", n.Role, "chassis_id", n.ChassisID),
+		})
+	}
+	for _, l := range g.Links {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: l.A.Device,
+			Target: l.B.Device,
+			Data: data(
+				"source_interface", l.A.Interface, "source_description", l.A.Description, "source_oper_status", l.A.OperStatus,
+				"target_interface", l.B.Interface, "target_description", l.B.Description, "target_oper_status", l.B.OperStatus,
+				"bidirectional", strconv.FormatBool(l.Bidirectional)),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Devices missing
+// from the inventory and links seen from one end only are dashed.
+func (g *Graph) WriteDOT(w io.Writer) error {
+
+	var b strings.Builder
+	b.WriteString("graph topology {\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.ID)}
+		if n.Site != "" || n.Role != "" {
+			attrs[0] = "label=" + dotQuote(strings.TrimSpace(n.ID+"\n"+strings.TrimSpace(n.Site+" "+n.Role)))
+		}
+		if !n.Known {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, l := range g.Links {
+		attrs := []string{
+			"taillabel=" + dotQuote(dotPort(l.A)),
+			"headlabel=" + dotQuote(dotPort(l.B)),
+		}
+		if tooltip := strings.TrimSpace(l.A.Description + " / " + l.B.Description); tooltip != "/" {
+			attrs = append(attrs, "tooltip="+dotQuote(tooltip))
+		}
+		if !l.Bidirectional {
+			attrs = append(attrs, "style=dashed")
+		}
+		if l.A.OperStatus == "down" || l.B.OperStatus == "down" {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %s -- %s [%s];\n", dotQuote(l.A.Device), dotQuote(l.B.Device), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotPort(e Endpoint) string {
+	if e.OperStatus == "" {
+		return e.Interface
+	}
+	return e.Interface + " (" + e.OperStatus + ")"
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,294 @@
+// Package topology builds the network topology from the LLDP neighbors of the
+// inventory devices.
+//
+// Remote system names are matched to inventory devices by hostname, a link
+// seen from both of its ends is reported once. Links only seen from one end
+// while the other end was polled are asymmetric, neighbors missing from the
+// inventory are unknown, both are flagged as issues.
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kgrvamsi/networkapi"
+)
+
+// Node is a device of the topology.
+type Node struct {
+	ID        string `json:"id"`
+	Known     bool   `json:"known"`
+	Site      string `json:"site,omitempty"`
+	Role      string `json:"role,omitempty"`
+	ChassisID string `json:"chassis_id,omitempty"`
+}
+
+// Endpoint is an end of a link.
+type Endpoint struct {
+	Device      string `json:"device"`
+	Interface   string `json:"interface"`
+	Description string `json:"description,omitempty"`
+	OperStatus  string `json:"oper_status,omitempty"`
+}
+
+// Link is a cable between two devices. Bidirectional is true when both ends
+// see each other.
+type Link struct {
+	A             Endpoint `json:"a"`
+	B             Endpoint `json:"b"`
+	Bidirectional bool     `json:"bidirectional"`
+}
+
+// Kinds of issues.
+const (
+	Asymmetric      = "asymmetric"
+	UnknownNeighbor = "unknown-neighbor"
+	CollectionError = "collection-error"
+)
+
+// Issue is an inconsistency found while building the topology.
+type Issue struct {
+	Type      string `json:"type"`
+	Device    string `json:"device"`
+	Interface string `json:"interface,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Graph is the topology of the network.
+type Graph struct {
+	Nodes  []Node  `json:"nodes"`
+	Links  []Link  `json:"links"`
+	Issues []Issue `json:"issues,omitempty"`
+}
+
+// DeviceData is what was collected from a device.
+type DeviceData struct {
+	Neighbors  []networkapi.LLDPNeighbor
+	Interfaces []networkapi.InterfacesList
+	Error      string
+}
+
+// Collect polls the LLDP neighbors and interfaces of the devices, by hostname.
+func Collect(inventory *networkapi.Inventory, devices []networkapi.Device, parallel int) map[string]DeviceData {
+
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		data = make(map[string]DeviceData)
+	)
+
+	sem := make(chan struct{}, parallel)
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d networkapi.Device) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			dd, err := collect(inventory.Client(d))
+			if err != nil {
+				dd.Error = err.Error()
+			}
+
+			mu.Lock()
+			data[d.Hostname] = dd
+			mu.Unlock()
+		}(d)
+	}
+	wg.Wait()
+
+	return data
+}
+
+func collect(c *networkapi.Client) (DeviceData, error) {
+
+	var dd DeviceData
+	defer c.DisconnectSSH()
+
+	session, err := c.ConnectSSH()
+	if err != nil {
+		return dd, err
+	}
+	dd.Neighbors, err = c.GetLLDPNeighborsInfoSSH(session)
+	c.CloseSSH(session)
+	if err != nil {
+		return dd, err
+	}
+
+	session, err = c.ConnectSSH()
+	if err != nil {
+		return dd, err
+	}
+	defer c.CloseSSH(session)
+
+	output, err := c.GetInterfacesSSH(session, "xml")
+	if err != nil {
+		return dd, err
+	}
+	if err := json.Unmarshal([]byte(output), &dd.Interfaces); err != nil {
+		return dd, fmt.Errorf("reading interfaces: %s", err)
+	}
+	return dd, nil
+}
+
+// Build collects the devices and returns their topology.
+func Build(inventory *networkapi.Inventory, devices []networkapi.Device, parallel int) *Graph {
+	return Assemble(inventory, Collect(inventory, devices, parallel))
+}
+
+var unitPattern = regexp.MustCompile(`\.[0-9]+$`)
+
+// Port returns the physical interface of an LLDP port, ge-0/0/0.0 is ge-0/0/0.
+func Port(name string) string {
+	return unitPattern.ReplaceAllString(strings.TrimSpace(name), "")
+}
+
+type end struct {
+	device, port string
+}
+
+// neighbor is an LLDP neighbor, seen from the local end.
+type neighbor struct {
+	local, remote end
+}
+
+func (e end) less(o end) bool {
+	if e.device != o.device {
+		return e.device < o.device
+	}
+	return e.port < o.port
+}
+
+// Assemble returns the topology of the collected devices.
+func Assemble(inventory *networkapi.Inventory, data map[string]DeviceData) *Graph {
+
+	g := &Graph{}
+	nodes := make(map[string]*Node)
+	addNode := func(n Node) {
+		if _, ok := nodes[n.ID]; !ok {
+			nodes[n.ID] = &n
+		}
+	}
+
+	hosts := make([]string, 0, len(data))
+	for host := range data {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	// seen holds every neighbor, with the remote devices named after the
+	// inventory, and remotes the remote ends seen on each local port: a port
+	// may have several neighbors, e.g. behind an unmanaged switch.
+	seen := make(map[neighbor]bool)
+	remotes := make(map[end][]end)
+	for _, host := range hosts {
+		d, _ := inventory.Lookup(host)
+		addNode(Node{ID: host, Known: true, Site: d.Site, Role: d.Role})
+
+		dd := data[host]
+		if dd.Error != "" {
+			g.Issues = append(g.Issues, Issue{Type: CollectionError, Device: host, Message: dd.Error})
+			continue
+		}
+		for _, n := range dd.Neighbors {
+			remote := strings.TrimSpace(n.RemoteSystemName)
+			if remote == "" {
+				remote = strings.TrimSpace(n.RemoteChassisID)
+			}
+			if rd, ok := inventory.Lookup(remote); ok {
+				remote = rd.Hostname
+				addNode(Node{ID: remote, Known: true, Site: rd.Site, Role: rd.Role})
+			} else {
+				addNode(Node{ID: remote, ChassisID: strings.TrimSpace(n.RemoteChassisID)})
+			}
+			nb := neighbor{end{host, Port(n.LocalPort())}, end{remote, Port(n.RemotePort())}}
+			if !seen[nb] {
+				seen[nb] = true
+				remotes[nb.local] = append(remotes[nb.local], nb.remote)
+			}
+		}
+	}
+
+	neighbors := make([]neighbor, 0, len(seen))
+	for nb := range seen {
+		neighbors = append(neighbors, nb)
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].local != neighbors[j].local {
+			return neighbors[i].local.less(neighbors[j].local)
+		}
+		return neighbors[i].remote.less(neighbors[j].remote)
+	})
+
+	done := make(map[neighbor]bool)
+	for _, nb := range neighbors {
+		local, remote := nb.local, nb.remote
+		pair := nb
+		if remote.less(local) {
+			pair = neighbor{remote, local}
+		}
+		if done[pair] {
+			continue
+		}
+		done[pair] = true
+
+		link := Link{A: endpoint(data, local), B: endpoint(data, remote)}
+		back := remotes[remote]
+		_, collected := data[remote.device]
+		switch {
+		case seen[neighbor{remote, local}]:
+			link.Bidirectional = true
+		case !nodes[remote.device].Known:
+			g.Issues = append(g.Issues, Issue{Type: UnknownNeighbor, Device: local.device, Interface: local.port,
+				Message: fmt.Sprintf("neighbor %s %s is not in the inventory", remote.device, remote.port)})
+		case len(back) > 0:
+			g.Issues = append(g.Issues, Issue{Type: Asymmetric, Device: local.device, Interface: local.port,
+				Message: fmt.Sprintf("sees %s %s, which sees %s", remote.device, remote.port, ends(back))})
+		case collected && data[remote.device].Error == "":
+			g.Issues = append(g.Issues, Issue{Type: Asymmetric, Device: local.device, Interface: local.port,
+				Message: fmt.Sprintf("sees %s %s, which has no neighbor on that port", remote.device, remote.port)})
+		}
+		g.Links = append(g.Links, link)
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		g.Nodes = append(g.Nodes, *nodes[id])
+	}
+
+	return g
+}
+
+// ends lists the ends as "device port, device port".
+func ends(list []end) string {
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.device + " " + e.port
+	}
+	return strings.Join(names, ", ")
+}
+
+// endpoint returns the end of a link annotated with its interface.
+func endpoint(data map[string]DeviceData, e end) Endpoint {
+	ep := Endpoint{Device: e.device, Interface: e.port}
+	for _, intf := range data[e.device].Interfaces {
+		if strings.TrimSpace(intf.Interfacename) == e.port {
+			ep.Description = strings.TrimSpace(intf.Description)
+			ep.OperStatus = strings.TrimSpace(intf.Operstatus)
+			break
+		}
+	}
+	return ep
+}
@@ -0,0 +1,433 @@
+package networkapi
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// remotePath matches the paths passed to the commands run on the device.
+var remotePath = regexp.MustCompile(`^[\w./+@%:-]+$`)
+
+//ListFilesSSH ...Returns the files of a directory of the device, such as /var/log, with their sizes and modification times
+func (c *Client) ListFilesSSH(dir string) ([]RemoteFile, error) {
+
+	client, err := c.connectSFTP()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	infos, err := client.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]RemoteFile, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, remoteFile(path.Join(dir, info.Name()), info))
+	}
+	return files, nil
+}
+
+func remoteFile(name string, info os.FileInfo) RemoteFile {
+	return RemoteFile{
+		Name:    info.Name(),
+		Path:    name,
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime(),
+		Dir:     info.IsDir(),
+	}
+}
+
+//PutFileSSH ...Copies a local file to the device, e.g. a configuration or a software image to /var/tmp/
+//
+// The file keeps its name when the remote path ends with a slash.
+func (c *Client) PutFileSSH(local, remote string, opts TransferOptions) error {
+
+	if opts.Resume && !opts.Verify {
+		return errResumeVerify
+	}
+	if strings.HasSuffix(remote, "/") {
+		remote += filepath.Base(local)
+	}
+	file, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if opts.SCP {
+		err = c.scpPut(file, info.Size(), remote, opts)
+	} else {
+		err = c.sftpPut(file, info.Size(), remote, opts)
+	}
+	if err != nil || !opts.Verify {
+		return err
+	}
+	return c.verifyChecksum(local, remote)
+}
+
+func (c *Client) sftpPut(file *os.File, size int64, remote string, opts TransferOptions) error {
+
+	client, err := c.connectSFTP()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var offset int64
+	if opts.Resume {
+		if info, err := client.Stat(remote); err == nil && info.Size() <= size {
+			offset = info.Size()
+		}
+	}
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := client.OpenFile(remote, flags)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	progress := &transferProgress{transferred: offset, total: size, fn: opts.Progress}
+	if _, err := io.Copy(dst, io.TeeReader(file, progress)); err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+//GetFileSSH ...Copies a file of the device, such as a log or a core, to a local file
+//
+// The file keeps its name when the local path is a directory.
+func (c *Client) GetFileSSH(remote, local string, opts TransferOptions) error {
+
+	if opts.Resume && !opts.Verify {
+		return errResumeVerify
+	}
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		local = filepath.Join(local, path.Base(remote))
+	}
+
+	var err error
+	if opts.SCP {
+		err = c.scpGet(remote, local, opts)
+	} else {
+		err = c.sftpGet(remote, local, opts)
+	}
+	if err != nil || !opts.Verify {
+		return err
+	}
+	return c.verifyChecksum(local, remote)
+}
+
+//GetFilesSSH ...Copies the files of the device matching the pattern, e.g. /var/crash/*, to a local directory and returns their local paths
+func (c *Client) GetFilesSSH(pattern, dir string, opts TransferOptions) ([]string, error) {
+
+	if opts.Resume && !opts.Verify {
+		return nil, errResumeVerify
+	}
+	client, err := c.connectSFTP()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := client.Glob(pattern)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	var names []string
+	for _, match := range matches {
+		if info, err := client.Stat(match); err == nil && info.Mode().IsRegular() {
+			names = append(names, match)
+		}
+	}
+	client.Close()
+
+	var locals []string
+	for _, name := range names {
+		local := filepath.Join(dir, path.Base(name))
+		if err := c.GetFileSSH(name, local, opts); err != nil {
+			return locals, err
+		}
+		locals = append(locals, local)
+	}
+	return locals, nil
+}
+
+func (c *Client) sftpGet(remote, local string, opts TransferOptions) error {
+
+	client, err := c.connectSFTP()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	src, err := client.Open(remote)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	if opts.Resume {
+		if partial, err := os.Stat(local); err == nil && partial.Size() <= info.Size() {
+			offset = partial.Size()
+		}
+	}
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(local, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	progress := &transferProgress{transferred: offset, total: info.Size(), fn: opts.Progress}
+	if _, err := io.Copy(io.MultiWriter(file, progress), src); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// connectSFTP starts an SFTP client over the connection shared by the
+// sessions, dialing it when needed.
+func (c *Client) connectSFTP() (*sftp.Client, error) {
+	conn, err := c.sshConnection()
+	if err != nil {
+		return nil, err
+	}
+	return sftp.NewClient(conn)
+}
+
+// errResumeVerify is returned when resuming without verifying the copy, the
+// start of the file at the destination being taken as is.
+var errResumeVerify = errors.New("resuming a transfer needs Verify, the start of the file at the destination being taken as is")
+
+// transferProgress counts the bytes written to it, calling fn with the
+// bytes transferred so far.
+type transferProgress struct {
+	transferred int64
+	total       int64
+	fn          func(transferred, total int64)
+}
+
+func (p *transferProgress) Write(b []byte) (int, error) {
+	p.transferred += int64(len(b))
+	if p.fn != nil {
+		p.fn(p.transferred, p.total)
+	}
+	return len(b), nil
+}
+
+//GetFileChecksumSSH ...Returns the SHA-256 of a file of the device, from file checksum sha-256
+func (c *Client) GetFileChecksumSSH(remote string) (string, error) {
+
+	if !remotePath.MatchString(remote) {
+		return "", fmt.Errorf("invalid path %q", remote)
+	}
+	output, err := c.RunSSH("file checksum sha-256 " + remote)
+	if err != nil {
+		return "", err
+	}
+	// The output is "SHA256 (/var/tmp/file) = 9f86d0...".
+	output = strings.TrimSpace(output)
+	sum := strings.TrimSpace(output[strings.LastIndex(output, "=")+1:])
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("file checksum sha-256 %s: %s", remote, output)
+	}
+	return strings.ToLower(sum), nil
+}
+
+// verifyChecksum compares the SHA-256 of the local file to the one of the
+// file of the device.
+func (c *Client) verifyChecksum(local, remote string) error {
+
+	file, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+
+	sum, err := c.GetFileChecksumSSH(remote)
+	if err != nil {
+		return err
+	}
+	if want := hex.EncodeToString(hash.Sum(nil)); sum != want {
+		return fmt.Errorf("checksum of %s is %s on the device, %s expected", remote, sum, want)
+	}
+	return nil
+}
+
+// scpPut copies the file with scp -t, which can't resume a transfer.
+func (c *Client) scpPut(file *os.File, size int64, remote string, opts TransferOptions) error {
+
+	if opts.Resume {
+		return errors.New("scp can't resume a transfer")
+	}
+	if !remotePath.MatchString(remote) {
+		return fmt.Errorf("invalid path %q", remote)
+	}
+	session, err := c.ConnectSSH()
+	if err != nil {
+		return err
+	}
+	defer c.CloseSSH(session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(stdout)
+	if err := session.Start("scp -t " + remote); err != nil {
+		return err
+	}
+
+	if err := scpAck(reader); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(stdin, "C0644 %d %s\n", size, path.Base(remote)); err != nil {
+		return err
+	}
+	if err := scpAck(reader); err != nil {
+		return err
+	}
+	progress := &transferProgress{total: size, fn: opts.Progress}
+	if _, err := io.CopyN(stdin, io.TeeReader(file, progress), size); err != nil {
+		return err
+	}
+	if _, err := stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	if err := scpAck(reader); err != nil {
+		return err
+	}
+	stdin.Close()
+	return session.Wait()
+}
+
+// scpGet copies the file of the device with scp -f.
+func (c *Client) scpGet(remote, local string, opts TransferOptions) error {
+
+	if opts.Resume {
+		return errors.New("scp can't resume a transfer")
+	}
+	if !remotePath.MatchString(remote) {
+		return fmt.Errorf("invalid path %q", remote)
+	}
+	session, err := c.ConnectSSH()
+	if err != nil {
+		return err
+	}
+	defer c.CloseSSH(session)
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(stdout)
+	if err := session.Start("scp -f " + remote); err != nil {
+		return err
+	}
+
+	if _, err := stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	// The file is announced as "C0644 <size> <name>", errors as 1 or 2 and a message.
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[0], "C") {
+		return fmt.Errorf("scp: %s", strings.TrimSpace(strings.TrimLeft(line, "\x01\x02")))
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("scp: invalid size %q", fields[1])
+	}
+	if _, err := stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+
+	file, err := os.Create(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	progress := &transferProgress{total: size, fn: opts.Progress}
+	if _, err := io.CopyN(io.MultiWriter(file, progress), reader, size); err != nil {
+		return err
+	}
+	if err := scpAck(reader); err != nil {
+		return err
+	}
+	if _, err := stdin.Write([]byte{0}); err != nil {
+		return err
+	}
+	stdin.Close()
+	if err := session.Wait(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// scpAck reads the answer of scp, a zero byte or an error and its message.
+func scpAck(reader *bufio.Reader) error {
+	b, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b == 0 {
+		return nil
+	}
+	message, _ := reader.ReadString('\n')
+	return fmt.Errorf("scp: %s", strings.TrimSpace(message))
+}
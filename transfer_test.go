@@ -0,0 +1,267 @@
+package networkapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var transferData = bytes.Repeat([]byte("junos-install-21.4R3.tgz "), 4096)
+
+func writeLocal(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	local := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(local, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return local
+}
+
+func readLocal(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPutFileSSH(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TransferOptions
+	}{
+		{"sftp", TransferOptions{}},
+		{"sftp verified", TransferOptions{Verify: true}},
+		{"scp", TransferOptions{SCP: true}},
+		{"scp verified", TransferOptions{SCP: true, Verify: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, c := testSSH(t)
+			local := writeLocal(t, "image.tgz", transferData)
+
+			var transferred, total int64
+			tt.opts.Progress = func(n, size int64) { transferred, total = n, size }
+			if err := c.PutFileSSH(local, "/var/tmp/", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			got, err := srv.ReadFile("/var/tmp/image.tgz")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, transferData) {
+				t.Errorf("copied %d bytes, want %d", len(got), len(transferData))
+			}
+			if size := int64(len(transferData)); transferred != size || total != size {
+				t.Errorf("progress = %d/%d, want %d/%d", transferred, total, size, size)
+			}
+		})
+	}
+}
+
+func TestGetFileSSH(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TransferOptions
+	}{
+		{"sftp", TransferOptions{}},
+		{"sftp verified", TransferOptions{Verify: true}},
+		{"scp", TransferOptions{SCP: true}},
+		{"scp verified", TransferOptions{SCP: true, Verify: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, c := testSSH(t)
+			if err := srv.WriteFile("/var/log/messages", transferData); err != nil {
+				t.Fatal(err)
+			}
+			dir := t.TempDir()
+			if err := c.GetFileSSH("/var/log/messages", dir, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := readLocal(t, filepath.Join(dir, "messages")); !bytes.Equal(got, transferData) {
+				t.Errorf("copied %d bytes, want %d", len(got), len(transferData))
+			}
+		})
+	}
+}
+
+func TestGetFileSSHMissing(t *testing.T) {
+	_, c := testSSH(t)
+	for _, opts := range []TransferOptions{{}, {SCP: true}} {
+		local := filepath.Join(t.TempDir(), "core")
+		if err := c.GetFileSSH("/var/crash/core.0", local, opts); err == nil {
+			t.Errorf("GetFileSSH(%+v) of a missing file succeeded", opts)
+		}
+	}
+}
+
+func TestGetFilesSSH(t *testing.T) {
+	srv, c := testSSH(t)
+	files := map[string]string{
+		"/var/crash/rpd.core.0":  "core 0",
+		"/var/crash/rpd.core.1":  "core 1",
+		"/var/crash/README":      "not a core",
+		"/var/log/rpd.core.jail": "elsewhere",
+	}
+	for name, data := range files {
+		if err := srv.WriteFile(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	locals, err := c.GetFilesSSH("/var/crash/*.core.*", dir, TransferOptions{Verify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(locals)
+	want := []string{filepath.Join(dir, "rpd.core.0"), filepath.Join(dir, "rpd.core.1")}
+	if !reflect.DeepEqual(locals, want) {
+		t.Fatalf("GetFilesSSH() = %v, want %v", locals, want)
+	}
+	for _, local := range locals {
+		if got := string(readLocal(t, local)); got != files["/var/crash/"+filepath.Base(local)] {
+			t.Errorf("%s = %q", local, got)
+		}
+	}
+}
+
+func TestListFilesSSH(t *testing.T) {
+	srv, c := testSSH(t)
+	if err := srv.WriteFile("/var/log/messages", []byte("log")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := c.ListFilesSSH("/var")
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]RemoteFile)
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+	if f := byName["log"]; !f.Dir || f.Path != "/var/log" {
+		t.Errorf("ListFilesSSH(/var) log = %+v, want the /var/log directory", f)
+	}
+
+	files, err = c.ListFilesSSH("/var/log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "messages" || files[0].Size != 3 || files[0].Dir {
+		t.Errorf("ListFilesSSH(/var/log) = %+v", files)
+	}
+}
+
+func TestTransferResume(t *testing.T) {
+	half := len(transferData) / 2
+
+	t.Run("put", func(t *testing.T) {
+		srv, c := testSSH(t)
+		if err := srv.WriteFile("/var/tmp/image.tgz", transferData[:half]); err != nil {
+			t.Fatal(err)
+		}
+		var first int64 = -1
+		opts := TransferOptions{Resume: true, Verify: true, Progress: func(n, _ int64) {
+			if first < 0 {
+				first = n
+			}
+		}}
+		if err := c.PutFileSSH(writeLocal(t, "image.tgz", transferData), "/var/tmp/image.tgz", opts); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := srv.ReadFile("/var/tmp/image.tgz"); !bytes.Equal(got, transferData) {
+			t.Errorf("resumed copy has %d bytes, want %d", len(got), len(transferData))
+		}
+		if first <= int64(half) {
+			t.Errorf("first progress = %d, want the copy to start after %d bytes", first, half)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		srv, c := testSSH(t)
+		if err := srv.WriteFile("/var/log/messages", transferData); err != nil {
+			t.Fatal(err)
+		}
+		local := writeLocal(t, "messages", transferData[:half])
+		if err := c.GetFileSSH("/var/log/messages", local, TransferOptions{Resume: true, Verify: true}); err != nil {
+			t.Fatal(err)
+		}
+		if got := readLocal(t, local); !bytes.Equal(got, transferData) {
+			t.Errorf("resumed copy has %d bytes, want %d", len(got), len(transferData))
+		}
+	})
+
+	t.Run("other file", func(t *testing.T) {
+		srv, c := testSSH(t)
+		if err := srv.WriteFile("/var/tmp/image.tgz", bytes.ToUpper(transferData[:half])); err != nil {
+			t.Fatal(err)
+		}
+		err := c.PutFileSSH(writeLocal(t, "image.tgz", transferData), "/var/tmp/image.tgz", TransferOptions{Resume: true, Verify: true})
+		if err == nil || !strings.Contains(err.Error(), "checksum of /var/tmp/image.tgz") {
+			t.Errorf("PutFileSSH() = %v, want a checksum mismatch", err)
+		}
+	})
+
+	t.Run("without verify", func(t *testing.T) {
+		_, c := testSSH(t)
+		local := writeLocal(t, "image.tgz", transferData)
+		if err := c.PutFileSSH(local, "/var/tmp/", TransferOptions{Resume: true}); err != errResumeVerify {
+			t.Errorf("PutFileSSH() = %v, want %v", err, errResumeVerify)
+		}
+		if err := c.GetFileSSH("/var/log/messages", local, TransferOptions{Resume: true}); err != errResumeVerify {
+			t.Errorf("GetFileSSH() = %v, want %v", err, errResumeVerify)
+		}
+	})
+
+	t.Run("scp", func(t *testing.T) {
+		_, c := testSSH(t)
+		err := c.PutFileSSH(writeLocal(t, "image.tgz", transferData), "/var/tmp/", TransferOptions{Resume: true, Verify: true, SCP: true})
+		if err == nil || !strings.Contains(err.Error(), "scp can't resume") {
+			t.Errorf("PutFileSSH() = %v, want scp refusing to resume", err)
+		}
+	})
+}
+
+func TestGetFileChecksumSSH(t *testing.T) {
+	srv, c := testSSH(t)
+	if err := srv.WriteFile("/var/tmp/image.tgz", transferData); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(transferData)
+
+	got, err := c.GetFileChecksumSSH("/var/tmp/image.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("GetFileChecksumSSH() = %s, want %s", got, want)
+	}
+	if _, err := c.GetFileChecksumSSH("/var/tmp/missing"); err == nil {
+		t.Error("GetFileChecksumSSH() of a missing file succeeded")
+	}
+	if _, err := c.GetFileChecksumSSH("/var/tmp/x; rm -rf /"); err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("GetFileChecksumSSH() = %v, want an invalid path", err)
+	}
+}
+
+func TestSFTPAfterDisconnect(t *testing.T) {
+	srv, c := testSSH(t)
+	if err := srv.WriteFile("/var/log/messages", []byte("log")); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListFilesSSH("/var/log"); err != nil {
+			t.Fatal(err)
+		}
+		c.DisconnectSSH()
+	}
+}
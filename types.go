@@ -1,6 +1,12 @@
 package networkapi
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
 
 type CommitHistory struct {
 	User      string `json:"user"`
@@ -68,6 +74,27 @@ type Bgppeer struct {
 	Flapcount       string   `xml:"flap-count"`
 	Elapsedtime     string   `xml:"elapsed-time"`
 	Peerstate       string   `xml:"peer-state"`
+	Bgprib          []BgpRib `xml:"bgp-rib"`
+}
+
+//Instance ... Returns the routing instance of the peer, named by its tables ("customer-a.inet.0"), "default" for the main instance or a peer without tables
+func (p Bgppeer) Instance() string {
+	for _, rib := range p.Bgprib {
+		parts := strings.Split(strings.TrimSpace(rib.Name), ".")
+		if len(parts) > 2 && parts[0] != "bgp" {
+			return strings.Join(parts[:len(parts)-2], ".")
+		}
+	}
+	return "default"
+}
+
+type BgpRib struct {
+	XMLName               xml.Name `xml:"bgp-rib"`
+	Name                  string   `xml:"name"`
+	Activeprefixcount     string   `xml:"active-prefix-count"`
+	Receivedprefixcount   string   `xml:"received-prefix-count"`
+	Acceptedprefixcount   string   `xml:"accepted-prefix-count"`
+	Suppressedprefixcount string   `xml:"suppressed-prefix-count"`
 }
 
 type Bgppeers struct {
@@ -299,7 +326,10 @@ type InterfacesDiagnosticsSSH struct {
 				LaserRxPowerHighWarnThresholdDbm   string `xml:"laser-rx-power-high-warn-threshold-dbm"`
 				LaserRxPowerLowWarnThreshold       string `xml:"laser-rx-power-low-warn-threshold"`
 				LaserRxPowerLowWarnThresholdDbm    string `xml:"laser-rx-power-low-warn-threshold-dbm"`
-				OpticsDiagnosticsLaneValues        struct {
+				LaserBiasCurrent                   string `xml:"laser-bias-current"`
+				LaserOutputPowerDbm                string `xml:"laser-output-power-dbm"`
+				RxSignalAvgOpticalPowerDbm         string `xml:"rx-signal-avg-optical-power-dbm"`
+				OpticsDiagnosticsLaneValues        []struct {
 					LaneIndex                        string `xml:"lane-index"`
 					LaserBiasCurrent                 string `xml:"laser-bias-current"`
 					LaserOutputPower                 string `xml:"laser-output-power"`
@@ -328,3 +358,592 @@ type RouterTimeRes struct {
 	LastConfiguredTime string `json:"last_configured_time"`
 	SystemBootedTime   string `json:"system_booted_time"`
 }
+
+type JunosTime struct {
+	Seconds string `xml:"seconds,attr"`
+	Value   string `xml:",chardata"`
+}
+
+type SystemUptimeInformation struct {
+	XMLName            xml.Name  `xml:"system-uptime-information"`
+	CurrentTime        JunosTime `xml:"current-time>date-time"`
+	SystemBootedTime   JunosTime `xml:"system-booted-time>date-time"`
+	SystemUptime       JunosTime `xml:"system-booted-time>time-length"`
+	ProtocolsStarted   JunosTime `xml:"protocols-started-time>date-time"`
+	LastConfiguredTime JunosTime `xml:"last-configured-time>date-time"`
+	LastConfiguredBy   string    `xml:"last-configured-time>user"`
+}
+
+type SystemUptimeSSH struct {
+	XMLName                 xml.Name                 `xml:"rpc-reply"`
+	SystemUptimeInformation *SystemUptimeInformation `xml:"system-uptime-information"`
+	MultiRoutingEngineItem  []struct {
+		ReName                  string                  `xml:"re-name"`
+		SystemUptimeInformation SystemUptimeInformation `xml:"system-uptime-information"`
+	} `xml:"multi-routing-engine-results>multi-routing-engine-item"`
+}
+
+type InterfacesStatisticsSSH struct {
+	XMLName           xml.Name `xml:"rpc-reply"`
+	PhysicalInterface []struct {
+		Name              string `xml:"name"`
+		Adminstatus       string `xml:"admin-status"`
+		Operstatus        string `xml:"oper-status"`
+		Description       string `xml:"description"`
+		Speed             string `xml:"speed"`
+		TrafficStatistics struct {
+			InputBytes    uint64 `xml:"input-bytes"`
+			OutputBytes   uint64 `xml:"output-bytes"`
+			InputPackets  uint64 `xml:"input-packets"`
+			OutputPackets uint64 `xml:"output-packets"`
+		} `xml:"traffic-statistics"`
+		InputErrors struct {
+			InputErrors uint64 `xml:"input-errors"`
+			InputDrops  uint64 `xml:"input-drops"`
+		} `xml:"input-error-list"`
+		OutputErrors struct {
+			OutputErrors uint64 `xml:"output-errors"`
+			OutputDrops  uint64 `xml:"output-drops"`
+		} `xml:"output-error-list"`
+	} `xml:"interface-information>physical-interface"`
+}
+
+type LLDPNeighborsInfoSSH struct {
+	XMLName   xml.Name       `xml:"rpc-reply"`
+	Neighbors []LLDPNeighbor `xml:"lldp-neighbors-information>lldp-neighbor-information"`
+}
+
+type LLDPNeighbor struct {
+	LocalPortID            string `xml:"lldp-local-port-id" json:"local_port_id,omitempty"`
+	LocalInterface         string `xml:"lldp-local-interface" json:"local_interface,omitempty"`
+	LocalParentInterface   string `xml:"lldp-local-parent-interface-name" json:"local_parent_interface,omitempty"`
+	RemoteChassisIDSubtype string `xml:"lldp-remote-chassis-id-subtype" json:"remote_chassis_id_subtype"`
+	RemoteChassisID        string `xml:"lldp-remote-chassis-id" json:"remote_chassis_id"`
+	RemotePortIDSubtype    string `xml:"lldp-remote-port-id-subtype" json:"remote_port_id_subtype,omitempty"`
+	RemotePortID           string `xml:"lldp-remote-port-id" json:"remote_port_id,omitempty"`
+	RemotePortDescription  string `xml:"lldp-remote-port-description" json:"remote_port_description,omitempty"`
+	RemoteSystemName       string `xml:"lldp-remote-system-name" json:"remote_system_name"`
+}
+
+//LocalPort ... Returns the local interface of the neighbor, whatever the platform calls it
+func (n LLDPNeighbor) LocalPort() string {
+	if n.LocalPortID != "" {
+		return n.LocalPortID
+	}
+	return n.LocalInterface
+}
+
+//RemotePort ... Returns the remote interface of the neighbor
+//
+// The port ID is preferred unless it is a locally assigned index, in which case
+// the port description usually holds the interface name.
+func (n LLDPNeighbor) RemotePort() string {
+	if n.RemotePortID != "" && (n.RemotePortDescription == "" || !strings.EqualFold(n.RemotePortIDSubtype, "Locally assigned")) {
+		return n.RemotePortID
+	}
+	return n.RemotePortDescription
+}
+
+//Interface ... Interface of any platform
+type Interface struct {
+	Name        string `json:"name"`
+	AdminStatus string `json:"admin_status"`
+	OperStatus  string `json:"oper_status"`
+	Description string `json:"description,omitempty"`
+	// Speed in bits per second, 0 when unknown.
+	Speed uint64 `json:"speed,omitempty"`
+}
+
+//BGPPeer ... BGP peer of any platform, with its prefix counts summed over the address families
+type BGPPeer struct {
+	// VRF is the VRF or routing instance of the peer, "default" for the main one.
+	VRF              string `json:"vrf"`
+	Address          string `json:"address"`
+	AS               string `json:"as"`
+	State            string `json:"state"`
+	PrefixesReceived int    `json:"prefixes_received"`
+	PrefixesAccepted int    `json:"prefixes_accepted"`
+}
+
+//Optics ... Diagnostics of an optical module, or of a lane of a multi-lane module
+type Optics struct {
+	Interface string `json:"interface"`
+	Lane      int    `json:"lane"`
+	// Temperature in Celsius.
+	Temperature float64 `json:"temperature"`
+	// TxPower and RxPower in dBm, NoLight without light.
+	TxPower float64 `json:"tx_power"`
+	RxPower float64 `json:"rx_power"`
+}
+
+//DeviceFacts ... Platform, identity, version and routing engines of a device
+type DeviceFacts struct {
+	Platform     string `json:"platform"`
+	Hostname     string `json:"hostname,omitempty"`
+	Domain       string `json:"domain,omitempty"`
+	Model        string `json:"model,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	// Version of the master routing engine.
+	Version  string        `json:"version,omitempty"`
+	MasterRE string        `json:"master_re,omitempty"`
+	Uptime   time.Duration `json:"uptime,omitempty"`
+	// RoutingEngines are the routing engines, virtual chassis members or
+	// cluster nodes. DetectFacts only names those of multi routing engine
+	// devices, ReadFacts reads them all.
+	RoutingEngines []RoutingEngineFacts `json:"routing_engines,omitempty"`
+	MultiRE        bool                 `json:"multi_re"`
+	VirtualChassis bool                 `json:"virtual_chassis"`
+	Cluster        bool                 `json:"cluster"`
+	// Capabilities of the NETCONF hello.
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+type SoftwareInformation struct {
+	HostName           string `xml:"host-name"`
+	ProductModel       string `xml:"product-model"`
+	ProductName        string `xml:"product-name"`
+	JunosVersion       string `xml:"junos-version"`
+	PackageInformation []struct {
+		Name    string `xml:"name"`
+		Comment string `xml:"comment"`
+	} `xml:"package-information"`
+}
+
+type SoftwareInformationSSH struct {
+	XMLName                xml.Name             `xml:"rpc-reply"`
+	SoftwareInformation    *SoftwareInformation `xml:"software-information"`
+	MultiRoutingEngineItem []struct {
+		ReName              string              `xml:"re-name"`
+		SoftwareInformation SoftwareInformation `xml:"software-information"`
+	} `xml:"multi-routing-engine-results>multi-routing-engine-item"`
+}
+
+//Version ... Returns the Junos version, from the package comments on releases before 15.1
+func (s SoftwareInformation) Version() string {
+	if version := strings.TrimSpace(s.JunosVersion); version != "" {
+		return version
+	}
+	for _, pkg := range s.PackageInformation {
+		comment := pkg.Comment
+		if start, end := strings.Index(comment, "["), strings.Index(comment, "]"); start >= 0 && end > start {
+			return comment[start+1 : end]
+		}
+	}
+	return ""
+}
+
+//RoutingEngineFacts ... Routing engine, virtual chassis member or cluster node
+type RoutingEngineFacts struct {
+	Name         string `json:"name"`
+	Model        string `json:"model,omitempty"`
+	Version      string `json:"version,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+	Status       string `json:"status,omitempty"`
+	// Mastership is master, backup or linecard.
+	Mastership string        `json:"mastership,omitempty"`
+	Uptime     time.Duration `json:"uptime"`
+}
+
+type JunosTemperature struct {
+	Celsius string `xml:"celsius,attr"`
+	Value   string `xml:",chardata"`
+}
+
+type RouteEngine struct {
+	Slot                    string           `xml:"slot"`
+	MastershipState         string           `xml:"mastership-state"`
+	Status                  string           `xml:"status"`
+	Model                   string           `xml:"model"`
+	SerialNumber            string           `xml:"serial-number"`
+	UpTime                  JunosTime        `xml:"up-time"`
+	Temperature             JunosTemperature `xml:"temperature"`
+	CPUTemperature          JunosTemperature `xml:"cpu-temperature"`
+	MemoryDRAMSize          string           `xml:"memory-dram-size"`
+	MemoryBufferUtilization string           `xml:"memory-buffer-utilization"`
+	CPUUser                 string           `xml:"cpu-user"`
+	CPUBackground           string           `xml:"cpu-background"`
+	CPUSystem               string           `xml:"cpu-system"`
+	CPUInterrupt            string           `xml:"cpu-interrupt"`
+	CPUIdle                 string           `xml:"cpu-idle"`
+	LoadAverageOne          string           `xml:"load-average-one"`
+	LoadAverageFive         string           `xml:"load-average-five"`
+	LoadAverageFifteen      string           `xml:"load-average-fifteen"`
+}
+
+type RouteEngineInformationSSH struct {
+	XMLName                xml.Name      `xml:"rpc-reply"`
+	RouteEngine            []RouteEngine `xml:"route-engine-information>route-engine"`
+	MultiRoutingEngineItem []struct {
+		ReName      string        `xml:"re-name"`
+		RouteEngine []RouteEngine `xml:"route-engine-information>route-engine"`
+	} `xml:"multi-routing-engine-results>multi-routing-engine-item"`
+}
+
+//ChassisModule ... Hardware module of show chassis hardware, with the modules it holds
+type ChassisModule struct {
+	Name         string          `xml:"name" json:"name"`
+	Version      string          `xml:"version" json:"version,omitempty"`
+	PartNumber   string          `xml:"part-number" json:"part_number,omitempty"`
+	SerialNumber string          `xml:"serial-number" json:"serial_number,omitempty"`
+	Description  string          `xml:"description" json:"description,omitempty"`
+	ModelNumber  string          `xml:"model-number" json:"model_number,omitempty"`
+	CLEICode     string          `xml:"clei-code" json:"clei_code,omitempty"`
+	Modules      []ChassisModule `xml:"-" json:"modules,omitempty"`
+}
+
+//ChassisInventory ... Chassis of a device, or of a virtual chassis member or cluster node
+type ChassisInventory struct {
+	Member  string        `json:"member,omitempty"`
+	Chassis ChassisModule `json:"chassis"`
+}
+
+//RouteQuery ... Filters of show route, the zero value asks for every route of every table
+type RouteQuery struct {
+	Table    string       `json:"table,omitempty"`
+	Prefix   netip.Prefix `json:"prefix,omitempty"`
+	Protocol string       `json:"protocol,omitempty"`
+	// Match is RouteMatchExact, RouteMatchLonger or RouteMatchOrLonger, it needs a prefix.
+	Match string `json:"match,omitempty"`
+}
+
+//Route ... A route of the routing table, each route to a destination is a Route
+//
+// Destination is the zero prefix for the routes of tables not keyed by a prefix,
+// like the labels of mpls.0, Name holds the destination as the device shows it.
+type Route struct {
+	Table           string        `json:"table"`
+	Name            string        `json:"name,omitempty"`
+	Destination     netip.Prefix  `json:"destination"`
+	Protocol        string        `json:"protocol"`
+	Preference      int           `json:"preference"`
+	Preference2     int           `json:"preference2"`
+	Metric          int           `json:"metric"`
+	Metric2         int           `json:"metric2"`
+	LocalPreference int           `json:"local_preference"`
+	Age             time.Duration `json:"age"`
+	ASPath          string        `json:"as_path,omitempty"`
+	Communities     []string      `json:"communities,omitempty"`
+	Active          bool          `json:"active"`
+	NextHops        []NextHop     `json:"next_hops"`
+}
+
+//NextHop ... A next hop of a route, the address is the zero address for interface routes
+type NextHop struct {
+	Address   netip.Addr `json:"address"`
+	Interface string     `json:"interface,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Selected  bool       `json:"selected"`
+}
+
+//Neighbor ... An entry of the ARP table or of the IPv6 neighbor cache
+//
+// Port is the member interface of an irb or ae interface the neighbor was
+// learned on, when the device tells it. Junos reports the time left before
+// the entry expires rather than its age.
+type Neighbor struct {
+	Address   netip.Addr       `json:"address"`
+	MAC       net.HardwareAddr `json:"mac"`
+	Interface string           `json:"interface"`
+	Port      string           `json:"port,omitempty"`
+	State     string           `json:"state,omitempty"`
+	Router    bool             `json:"router"`
+	Expire    time.Duration    `json:"expire"`
+}
+
+//MACEntry ... An entry of the ethernet switching table
+type MACEntry struct {
+	MAC       net.HardwareAddr `json:"mac"`
+	VLAN      string           `json:"vlan"`
+	VLANID    int              `json:"vlan_id"`
+	Interface string           `json:"interface"`
+	Type      string           `json:"type,omitempty"`
+	Age       time.Duration    `json:"age"`
+}
+
+//HostLocation ... Where a host is plugged in, from its neighbor entry and the switching table
+type HostLocation struct {
+	Address   netip.Addr       `json:"address"`
+	MAC       net.HardwareAddr `json:"mac"`
+	Interface string           `json:"interface"`
+	Port      string           `json:"port,omitempty"`
+	VLAN      string           `json:"vlan,omitempty"`
+	VLANID    int              `json:"vlan_id,omitempty"`
+}
+
+//OSPFNeighbor ... An OSPF or OSPFv3 neighbor
+//
+// The designated routers are addresses for OSPF and router IDs for OSPFv3,
+// whose neighbor address is link-local. DeadTime is the time left before the
+// neighbor is declared down.
+type OSPFNeighbor struct {
+	Address   netip.Addr    `json:"address"`
+	RouterID  string        `json:"router_id"`
+	Interface string        `json:"interface"`
+	State     string        `json:"state"`
+	Area      string        `json:"area,omitempty"`
+	Priority  int           `json:"priority"`
+	DR        string        `json:"dr,omitempty"`
+	BDR       string        `json:"bdr,omitempty"`
+	DeadTime  time.Duration `json:"dead_time"`
+	Uptime    time.Duration `json:"uptime"`
+}
+
+//OSPFInterface ... An interface running OSPF or OSPFv3
+type OSPFInterface struct {
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Area      string `json:"area"`
+	Type      string `json:"type,omitempty"`
+	DR        string `json:"dr,omitempty"`
+	BDR       string `json:"bdr,omitempty"`
+	Neighbors int    `json:"neighbors"`
+	Cost      int    `json:"cost"`
+	Passive   bool   `json:"passive"`
+}
+
+//ISISAdjacency ... An IS-IS adjacency, level 3 being an adjacency of both levels
+type ISISAdjacency struct {
+	Interface string        `json:"interface"`
+	System    string        `json:"system"`
+	Level     int           `json:"level"`
+	State     string        `json:"state"`
+	SNPA      string        `json:"snpa,omitempty"`
+	HoldTime  time.Duration `json:"hold_time"`
+}
+
+//ISISLSP ... A link state PDU of the IS-IS database
+type ISISLSP struct {
+	Level      int           `json:"level"`
+	LSPID      string        `json:"lsp_id"`
+	Sequence   uint64        `json:"sequence"`
+	Checksum   uint64        `json:"checksum"`
+	Lifetime   time.Duration `json:"lifetime"`
+	Attributes string        `json:"attributes,omitempty"`
+}
+
+//LSP ... An RSVP signaled label switched path, as the ingress, transit or egress router sees it
+//
+// The active path, bandwidth and last change are only known at the ingress,
+// the last change being the time of the last event of the active path as the
+// device shows it, without the year.
+type LSP struct {
+	Name       string   `json:"name"`
+	Role       string   `json:"role"`
+	State      string   `json:"state"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	ActivePath string   `json:"active_path,omitempty"`
+	Bandwidth  uint64   `json:"bandwidth,omitempty"`
+	ERO        []string `json:"ero,omitempty"`
+	RRO        []string `json:"rro,omitempty"`
+	LabelIn    string   `json:"label_in,omitempty"`
+	LabelOut   string   `json:"label_out,omitempty"`
+	LastChange string   `json:"last_change,omitempty"`
+	LastEvent  string   `json:"last_event,omitempty"`
+}
+
+//RSVPSession ... An RSVP session, role being ingress, transit or egress
+type RSVPSession struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	State    string `json:"state"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Style    string `json:"style,omitempty"`
+	LabelIn  string `json:"label_in,omitempty"`
+	LabelOut string `json:"label_out,omitempty"`
+}
+
+//LDPNeighbor ... A neighbor discovered by LDP hellos
+type LDPNeighbor struct {
+	Address    string        `json:"address"`
+	Interface  string        `json:"interface"`
+	LabelSpace string        `json:"label_space"`
+	HoldTime   time.Duration `json:"hold_time"`
+}
+
+//LDPSession ... An LDP session with the number of labels it received and advertised
+type LDPSession struct {
+	Neighbor         string        `json:"neighbor"`
+	State            string        `json:"state"`
+	ConnectionState  string        `json:"connection_state"`
+	HoldTime         time.Duration `json:"hold_time"`
+	LabelsReceived   int           `json:"labels_received"`
+	LabelsAdvertised int           `json:"labels_advertised"`
+}
+
+//Alarm ... An active alarm of show chassis alarms or show system alarms
+type Alarm struct {
+	Time        time.Time     `json:"time"`
+	Severity    AlarmSeverity `json:"severity"`
+	Type        string        `json:"type"`
+	Description string        `json:"description"`
+}
+
+//EnvironmentItem ... A temperature sensor, fan or power supply of show chassis environment
+//
+// The temperature is in degrees Celsius and only set for temperature sensors.
+type EnvironmentItem struct {
+	Name        string  `json:"name"`
+	Class       string  `json:"class"`
+	Status      string  `json:"status"`
+	Temperature float64 `json:"temperature,omitempty"`
+	Comment     string  `json:"comment,omitempty"`
+}
+
+//RoutingEngineStatus ... The health of a routing engine, the utilizations being percentages
+type RoutingEngineStatus struct {
+	Name              string        `json:"name"`
+	Model             string        `json:"model,omitempty"`
+	Status            string        `json:"status"`
+	Mastership        string        `json:"mastership,omitempty"`
+	CPUUser           int           `json:"cpu_user"`
+	CPUBackground     int           `json:"cpu_background"`
+	CPUSystem         int           `json:"cpu_system"`
+	CPUInterrupt      int           `json:"cpu_interrupt"`
+	CPUIdle           int           `json:"cpu_idle"`
+	CPUUtilization    int           `json:"cpu_utilization"`
+	MemoryTotal       int           `json:"memory_total_mb"`
+	MemoryUtilization int           `json:"memory_utilization"`
+	Temperature       float64       `json:"temperature"`
+	CPUTemperature    float64       `json:"cpu_temperature,omitempty"`
+	LoadAverage       [3]float64    `json:"load_average"`
+	Uptime            time.Duration `json:"uptime"`
+}
+
+//LACPBundle ... An aggregated ethernet interface with the LACP state of its members
+//
+// A bundle is degraded when it is down or, running LACP, has fewer active
+// members than members or fewer than its minimum links. Its partner
+// mismatches when its members see different partner systems or keys, a
+// member being cabled to another device.
+type LACPBundle struct {
+	Name            string       `json:"name"`
+	AdminStatus     string       `json:"admin_status"`
+	OperStatus      string       `json:"oper_status"`
+	Speed           uint64       `json:"speed"`
+	MinimumLinks    int          `json:"minimum_links"`
+	Members         []LACPMember `json:"members"`
+	ActiveMembers   int          `json:"active_members"`
+	Degraded        bool         `json:"degraded"`
+	PartnerMismatch bool         `json:"partner_mismatch"`
+}
+
+//LACPMember ... A member link of a bundle, as its actor and partner see it
+//
+// A member is active when the actor is collecting and distributing on it.
+type LACPMember struct {
+	Name            string `json:"name"`
+	Collecting      bool   `json:"collecting"`
+	Distributing    bool   `json:"distributing"`
+	Synchronized    bool   `json:"synchronized"`
+	Expired         bool   `json:"expired"`
+	Defaulted       bool   `json:"defaulted"`
+	Activity        string `json:"activity"`
+	Timeout         string `json:"timeout"`
+	PartnerTimeout  string `json:"partner_timeout"`
+	ActorSystemID   string `json:"actor_system_id,omitempty"`
+	PartnerSystemID string `json:"partner_system_id,omitempty"`
+	ActorKey        int    `json:"actor_key,omitempty"`
+	PartnerKey      int    `json:"partner_key,omitempty"`
+	MuxState        string `json:"mux_state"`
+	ReceiveState    string `json:"receive_state"`
+}
+
+//FirewallFilter ... Counters and policers of a firewall filter
+type FirewallFilter struct {
+	Name     string            `json:"name"`
+	Counters []FirewallCounter `json:"counters,omitempty"`
+	Policers []FirewallCounter `json:"policers,omitempty"`
+}
+
+//FirewallCounter ... Packets and bytes counted by a term of a firewall filter, or dropped by a policer
+type FirewallCounter struct {
+	Name    string `json:"name"`
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+//PingOptions ... Options of a ping, Count defaulting to 5 and the other options to those of the device
+type PingOptions struct {
+	Source          string `json:"source,omitempty"`
+	RoutingInstance string `json:"routing_instance,omitempty"`
+	Count           int    `json:"count,omitempty"`
+	Size            int    `json:"size,omitempty"`
+	DoNotFragment   bool   `json:"do_not_fragment,omitempty"`
+	TTL             int    `json:"ttl,omitempty"`
+}
+
+//PingResult ... Responses to the pings and their round trip times
+type PingResult struct {
+	Target     string        `json:"target"`
+	Address    string        `json:"address"`
+	Sent       int           `json:"sent"`
+	Received   int           `json:"received"`
+	PacketLoss float64       `json:"packet_loss"`
+	RTTMin     time.Duration `json:"rtt_min"`
+	RTTAvg     time.Duration `json:"rtt_avg"`
+	RTTMax     time.Duration `json:"rtt_max"`
+	RTTStdDev  time.Duration `json:"rtt_stddev"`
+	Probes     []PingProbe   `json:"probes,omitempty"`
+}
+
+//PingProbe ... Response to a ping
+type PingProbe struct {
+	Sequence int           `json:"sequence"`
+	Success  bool          `json:"success"`
+	Address  string        `json:"address,omitempty"`
+	TTL      int           `json:"ttl"`
+	Size     int           `json:"size"`
+	RTT      time.Duration `json:"rtt"`
+}
+
+//TracerouteOptions ... Options of a traceroute, TTL being the maximum number of hops
+type TracerouteOptions struct {
+	Source          string `json:"source,omitempty"`
+	RoutingInstance string `json:"routing_instance,omitempty"`
+	TTL             int    `json:"ttl,omitempty"`
+}
+
+//TracerouteResult ... Hops of the route to the target of a traceroute
+type TracerouteResult struct {
+	Target  string          `json:"target"`
+	Address string          `json:"address"`
+	Hops    []TracerouteHop `json:"hops"`
+}
+
+//TracerouteHop ... Responses to the probes sent with the TTL of the hop
+type TracerouteHop struct {
+	TTL    int               `json:"ttl"`
+	Probes []TracerouteProbe `json:"probes,omitempty"`
+}
+
+//TracerouteProbe ... Response to a probe of a hop, not successful when it timed out
+type TracerouteProbe struct {
+	Address  string        `json:"address,omitempty"`
+	HostName string        `json:"host_name,omitempty"`
+	RTT      time.Duration `json:"rtt"`
+	Success  bool          `json:"success"`
+}
+
+//TransferOptions ... Options of a file transfer, over SFTP unless SCP is set
+type TransferOptions struct {
+	// Progress is called as the file is copied, with the bytes copied so far and the size of the file.
+	Progress func(transferred, total int64) `json:"-"`
+	// Resume copies the rest of the file when the destination holds the start of it, SCP not being able to.
+	// The start isn't compared to the file, so Resume needs Verify.
+	Resume bool `json:"resume,omitempty"`
+	// Verify compares the SHA-256 of the copy to the one given by file checksum sha-256 on the device.
+	Verify bool `json:"verify,omitempty"`
+	SCP    bool `json:"scp,omitempty"`
+}
+
+//RemoteFile ... File of a directory of the device
+type RemoteFile struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+	Dir     bool      `json:"dir"`
+}